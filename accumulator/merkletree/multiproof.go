@@ -0,0 +1,291 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package merkletree
+
+import (
+	"bytes"
+	"errors"
+	"hash"
+	"io"
+	"math/bits"
+	"sort"
+)
+
+var errCachedMultiProof = errors.New("multi proofs are not supported on cached trees")
+
+// multiNodeID identifies a complete subtree by its height and the index of
+// its first leaf. A height of -1 stands for the join of all the complete
+// subtrees from start to the last leaf.
+type multiNodeID struct {
+	height int
+	start  uint64
+}
+
+// SetIndices will tell the Tree to create a proof that the leaves at the input
+// indices are in the tree, see ProveMulti. The indices are sorted and
+// deduplicated. SetIndices must be called on an empty tree. It can be used
+// together with SetIndex.
+//
+// Multi proofs are not supported on cached trees, whose leaf sums are the
+// pushed data: the verifier would hash the leaves again. Complete subtrees
+// which don't contain any of the indices can still be added with PushSubTree.
+func (t *Tree) SetIndices(indices ...uint64) error {
+	if t.head != nil {
+		return errors.New("cannot call SetIndices on Tree if Tree has not been reset")
+	}
+	if t.cachedTree {
+		return errCachedMultiProof
+	}
+	if len(indices) == 0 {
+		return errors.New("at least one index is needed")
+	}
+	t.proofIndices = sortIndices(indices)
+	t.multiLeaves = make(map[uint64][]byte, len(t.proofIndices))
+	t.multiNodes = make(map[multiNodeID][]byte)
+	t.multiProofTree = true
+	return nil
+}
+
+// sortIndices returns a sorted copy of indices, without duplicates
+func sortIndices(indices []uint64) []uint64 {
+	res := make([]uint64, len(indices))
+	copy(res, indices)
+	sort.Slice(res, func(i, j int) bool { return res[i] < res[j] })
+	n := 0
+	for i := range res {
+		if i == 0 || res[i] != res[n-1] {
+			res[n] = res[i]
+			n++
+		}
+	}
+	return res[:n]
+}
+
+// containsIndex returns true if one of the indices set with SetIndices is in
+// [start, end).
+func (t *Tree) containsIndex(start, end uint64) bool {
+	i := sort.Search(len(t.proofIndices), func(i int) bool { return t.proofIndices[i] >= start })
+	return i < len(t.proofIndices) && t.proofIndices[i] < end
+}
+
+// recordSubTree keeps the sum of the complete subtree of the given height
+// whose last leaf is at index last, if it is needed for the multi proof, that
+// is if it doesn't contain any proof index and its sibling does.
+func (t *Tree) recordSubTree(height int, last uint64, sum []byte) {
+	if !t.multiProofTree {
+		return
+	}
+	size := uint64(1) << uint(height)
+	start := last + 1 - size
+	sibling := start ^ size
+	if !t.containsIndex(start, start+size) && t.containsIndex(sibling, sibling+size) {
+		t.multiNodes[multiNodeID{height, start}] = sum
+	}
+}
+
+// ProveMulti creates a proof that the leaves at the indices established by
+// SetIndices are elements of the Merkle tree. The siblings which can be
+// computed from the proven leaves are not included, so the proof is smaller
+// than the concatenation of the single proofs. ProveMulti will return a nil
+// proof set if some of the indices haven't been reached. ProveMulti does not
+// modify the Tree.
+//
+// The returned leaves are the data at each of the sorted proofIndices.
+func (t *Tree) ProveMulti() (merkleRoot []byte, leaves [][]byte, proofSet [][]byte, proofIndices []uint64, numLeaves uint64) {
+	if !t.multiProofTree {
+		panic("wrong usage: can't call ProveMulti on a tree if SetIndices wasn't called")
+	}
+	if t.cachedTree {
+		panic("wrong usage: " + errCachedMultiProof.Error())
+	}
+	proofIndices = append(proofIndices, t.proofIndices...)
+	numLeaves = t.currentIndex
+	merkleRoot = t.Root()
+	if t.head == nil || proofIndices[len(proofIndices)-1] >= numLeaves {
+		return
+	}
+
+	leaves = make([][]byte, len(proofIndices))
+	for i, index := range proofIndices {
+		leaves[i] = t.multiLeaves[index]
+	}
+
+	// the complete subtrees of the stack, combined to form the root, and their
+	// joins with the subtrees on their right
+	stack := make(map[multiNodeID][]byte)
+	start := t.currentIndex
+	var joined []byte
+	for current := t.head; current != nil; current = current.next {
+		start -= 1 << uint(current.height)
+		stack[multiNodeID{current.height, start}] = current.sum
+		if joined == nil {
+			joined = current.sum
+		} else {
+			joined = nodeSum(t.hash, current.sum, joined)
+		}
+		stack[multiNodeID{-1, start}] = joined
+	}
+
+	// the needed sums are given in the order in which the verifier consumes them
+	_, ok := walkMultiProof(t.hash, leaves, proofIndices, numLeaves, func(height int, start uint64) ([]byte, bool) {
+		id := multiNodeID{height, start}
+		sum, ok := t.multiNodes[id]
+		if !ok {
+			sum, ok = stack[id]
+		}
+		proofSet = append(proofSet, sum)
+		return sum, ok
+	})
+	if !ok {
+		// unreachable: all the siblings are recorded while building the tree
+		panic("multi proof: missing sibling")
+	}
+	return
+}
+
+// VerifyMultiProof takes a Merkle root, the data of several leaves, a proof
+// set returned by ProveMulti and the sorted indices of the leaves, and returns
+// true if the leaves are in the Merkle root. False is returned if the input is
+// not consistent.
+func VerifyMultiProof(h hash.Hash, merkleRoot []byte, leaves [][]byte, proofSet [][]byte, proofIndices []uint64, numLeaves uint64) bool {
+	if merkleRoot == nil || len(leaves) == 0 || len(leaves) != len(proofIndices) {
+		return false
+	}
+	for i := range proofIndices {
+		if proofIndices[i] >= numLeaves || (i > 0 && proofIndices[i-1] >= proofIndices[i]) {
+			return false
+		}
+	}
+
+	next := 0
+	root, ok := walkMultiProof(h, leaves, proofIndices, numLeaves, func(int, uint64) ([]byte, bool) {
+		if next >= len(proofSet) {
+			return nil, false
+		}
+		next++
+		return proofSet[next-1], true
+	})
+
+	return ok && next == len(proofSet) && bytes.Equal(root, merkleRoot)
+}
+
+// walkMultiProof computes the Merkle root from the leaves at the sorted
+// indices. The tree of numLeaves leaves is made of complete subtrees of
+// decreasing heights, from left to right, whose roots are combined from the
+// right. Each complete subtree which doesn't contain any of the indices, and
+// each sibling which can't be computed from the leaves, is given by the
+// sibling callback, in order. As in the single proofs, the complete subtrees
+// on the right of the last index are given as a single sum (height -1).
+func walkMultiProof(h hash.Hash, leaves [][]byte, indices []uint64, numLeaves uint64, sibling func(height int, start uint64) ([]byte, bool)) ([]byte, bool) {
+
+	var roots [][]byte
+	var start uint64
+	first := 0
+	for height := bits.Len64(numLeaves) - 1; height >= 0; height-- {
+		size := uint64(1) << uint(height)
+		if numLeaves&size == 0 {
+			continue
+		}
+
+		// indices in [start, start+size)
+		end := first
+		for end < len(indices) && indices[end] < start+size {
+			end++
+		}
+
+		if first == len(indices) {
+			// the remaining subtrees are joined by the prover
+			sum, ok := sibling(-1, start)
+			if !ok {
+				return nil, false
+			}
+			roots = append(roots, sum)
+			break
+		} else if first == end {
+			sum, ok := sibling(height, start)
+			if !ok {
+				return nil, false
+			}
+			roots = append(roots, sum)
+		} else {
+			// positions and sums of the known nodes of the current level
+			positions := make([]uint64, end-first)
+			sums := make([][]byte, end-first)
+			for i := range positions {
+				positions[i] = indices[first+i]
+				sums[i] = leafSum(h, leaves[first+i])
+			}
+			for level := 0; level < height; level++ {
+				n := 0
+				for i := 0; i < len(positions); i++ {
+					p := positions[i]
+					var sum []byte
+					if p%2 == 0 && i+1 < len(positions) && positions[i+1] == p+1 {
+						// both children are known
+						sum = nodeSum(h, sums[i], sums[i+1])
+						i++
+					} else {
+						s, ok := sibling(level, (p^1)<<uint(level))
+						if !ok {
+							return nil, false
+						}
+						if p%2 == 0 {
+							sum = nodeSum(h, sums[i], s)
+						} else {
+							sum = nodeSum(h, s, sums[i])
+						}
+					}
+					positions[n], sums[n] = p/2, sum
+					n++
+				}
+				positions, sums = positions[:n], sums[:n]
+			}
+			roots = append(roots, sums[0])
+		}
+
+		first = end
+		start += size
+	}
+
+	// the smallest subtrees are joined first, as in Tree.Root
+	current := roots[len(roots)-1]
+	for i := len(roots) - 2; i >= 0; i-- {
+		current = nodeSum(h, roots[i], current)
+	}
+	return current, true
+}
+
+// BuildReaderMultiProof returns a proof that the data at several indices is in
+// the merkle tree created by the data in the reader, see ProveMulti. The tree
+// is built once whatever the number of indices. The merkle root, the data at
+// the sorted indices, the proof set, the sorted indices, and the number of
+// leaves in the Merkle tree are all returned. All leaves will we 'segmentSize'
+// bytes except the last leaf, which will not be padded out if there are not
+// enough bytes remaining in the reader.
+func BuildReaderMultiProof(r io.Reader, h hash.Hash, segmentSize int, indices []uint64) (root []byte, leaves [][]byte, proofSet [][]byte, proofIndices []uint64, numLeaves uint64, err error) {
+	tree := New(h)
+	if err = tree.SetIndices(indices...); err != nil {
+		return
+	}
+	if err = tree.ReadAll(r, segmentSize); err != nil {
+		return
+	}
+	root, leaves, proofSet, proofIndices, numLeaves = tree.ProveMulti()
+	if leaves == nil {
+		err = errors.New("some indices were not reached while creating proof")
+		return
+	}
+	return
+}
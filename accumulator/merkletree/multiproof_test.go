@@ -0,0 +1,191 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package merkletree
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"math/rand"
+	"testing"
+)
+
+func TestMultiProof(t *testing.T) {
+	r := rand.New(rand.NewSource(0)) //#nosec G404 weak rng is fine here
+	h := sha256.New()
+
+	for _, numLeaves := range []uint64{1, 2, 3, 7, 8, 13, 64, 100, 257} {
+		data := make([][]byte, numLeaves)
+		for i := range data {
+			data[i] = make([]byte, 1+r.Intn(64))
+			r.Read(data[i])
+		}
+
+		for _, nbIndices := range []int{1, 2, 5, 16} {
+			indices := make([]uint64, nbIndices)
+			for i := range indices {
+				indices[i] = uint64(r.Int63n(int64(numLeaves)))
+			}
+
+			tree := New(h)
+			if err := tree.SetIndices(indices...); err != nil {
+				t.Fatal(err)
+			}
+			for i := range data {
+				tree.Push(data[i])
+			}
+			root, leaves, proofSet, proofIndices, n := tree.ProveMulti()
+			if n != numLeaves || !bytes.Equal(root, tree.Root()) {
+				t.Fatal("wrong root or number of leaves")
+			}
+			for i := range proofIndices {
+				if !bytes.Equal(leaves[i], data[proofIndices[i]]) {
+					t.Fatal("wrong leaf in the proof")
+				}
+			}
+			if !VerifyMultiProof(h, root, leaves, proofSet, proofIndices, numLeaves) {
+				t.Fatalf("verifying a correct multi proof should succeed (%d leaves, indices %v)", numLeaves, proofIndices)
+			}
+
+			// the multi proof is not larger than the single proofs
+			size := 0
+			for _, index := range proofIndices {
+				single := New(h)
+				if err := single.SetIndex(index); err != nil {
+					t.Fatal(err)
+				}
+				for i := range data {
+					single.Push(data[i])
+				}
+				_, ps, _, _ := single.Prove()
+				size += len(ps) - 1
+			}
+			if len(proofSet) > size {
+				t.Fatal("the multi proof should not be larger than the single proofs")
+			}
+
+			// tampered proofs
+			leaves[0] = append(leaves[0], 1)
+			if VerifyMultiProof(h, root, leaves, proofSet, proofIndices, numLeaves) {
+				t.Fatal("verifying a multi proof with a wrong leaf should fail")
+			}
+			leaves[0] = leaves[0][:len(leaves[0])-1]
+			if len(proofSet) > 0 {
+				if VerifyMultiProof(h, root, leaves, proofSet[1:], proofIndices, numLeaves) {
+					t.Fatal("verifying a truncated multi proof should fail")
+				}
+			}
+			if VerifyMultiProof(h, root, leaves, append(proofSet, root), proofIndices, numLeaves) {
+				t.Fatal("verifying a multi proof with extra siblings should fail")
+			}
+			if len(proofIndices) > 1 && VerifyMultiProof(h, root, leaves[1:], proofSet, proofIndices[1:], numLeaves) {
+				t.Fatal("verifying a multi proof with missing leaves should fail")
+			}
+		}
+	}
+}
+
+func TestMultiProofIndexNotReached(t *testing.T) {
+	tree := New(sha256.New())
+	if err := tree.SetIndices(1, 10); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 5; i++ {
+		tree.Push([]byte{byte(i)})
+	}
+	if _, leaves, proofSet, _, _ := tree.ProveMulti(); leaves != nil || proofSet != nil {
+		t.Fatal("the proof should be nil if an index is not reached")
+	}
+}
+
+func TestMultiProofSubTrees(t *testing.T) {
+	h := sha256.New()
+	data := make([][]byte, 16)
+	for i := range data {
+		data[i] = []byte{byte(i)}
+	}
+	subTreeRoot := func(from, to int) []byte {
+		sub := New(h)
+		for i := from; i < to; i++ {
+			sub.Push(data[i])
+		}
+		return sub.Root()
+	}
+
+	// leaves 0..3 and 12..15 are pushed as subtrees
+	tree := New(h)
+	if err := tree.SetIndices(5, 9); err != nil {
+		t.Fatal(err)
+	}
+	if err := tree.PushSubTree(2, subTreeRoot(0, 4)); err != nil {
+		t.Fatal(err)
+	}
+	for i := 4; i < 12; i++ {
+		tree.Push(data[i])
+	}
+	if err := tree.PushSubTree(2, subTreeRoot(12, 16)); err != nil {
+		t.Fatal(err)
+	}
+	root, leaves, proofSet, proofIndices, numLeaves := tree.ProveMulti()
+	if !bytes.Equal(root, subTreeRoot(0, 16)) {
+		t.Fatal("wrong root")
+	}
+	if !VerifyMultiProof(h, root, leaves, proofSet, proofIndices, numLeaves) {
+		t.Fatal("verifying a correct multi proof should succeed")
+	}
+
+	// a subtree containing an index can't be pushed
+	tree = New(h)
+	if err := tree.SetIndices(5); err != nil {
+		t.Fatal(err)
+	}
+	if err := tree.PushSubTree(3, subTreeRoot(0, 8)); err == nil {
+		t.Fatal("pushing a subtree containing an index should fail")
+	}
+
+	// the leaf sums of cached trees are the pushed data
+	tree = &Tree{hash: h, cachedTree: true}
+	if err := tree.SetIndices(5); err == nil {
+		t.Fatal("multi proofs on cached trees should fail")
+	}
+}
+
+func TestBuildReaderMultiProof(t *testing.T) {
+	const segmentSize = 64
+	data := make([]byte, 100*segmentSize+17)
+	rand.New(rand.NewSource(1)).Read(data) //#nosec G404 weak rng is fine here
+	h := sha256.New()
+
+	root, leaves, proofSet, proofIndices, numLeaves, err := BuildReaderMultiProof(bytes.NewReader(data), h, segmentSize, []uint64{100, 3, 42, 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if numLeaves != 101 || len(proofIndices) != 3 {
+		t.Fatal("wrong number of leaves or indices")
+	}
+	expectedRoot, err := ReaderRoot(bytes.NewReader(data), h, segmentSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(root, expectedRoot) {
+		t.Fatal("wrong root")
+	}
+	if !VerifyMultiProof(h, root, leaves, proofSet, proofIndices, numLeaves) {
+		t.Fatal("verifying a correct multi proof should succeed")
+	}
+
+	if _, _, _, _, _, err := BuildReaderMultiProof(bytes.NewReader(data), h, segmentSize, []uint64{101}); err == nil {
+		t.Fatal("building a proof for an index which is not reached should fail")
+	}
+}
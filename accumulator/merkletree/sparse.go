@@ -0,0 +1,478 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package merkletree
+
+import (
+	"bytes"
+	"errors"
+	"hash"
+	"sort"
+)
+
+var (
+	ErrKeySize      = errors.New("the size of the key doesn't match the depth of the tree")
+	ErrKeyExists    = errors.New("the key is already in the tree")
+	ErrKeyNotFound  = errors.New("the key is not in the tree")
+	ErrEmptyValue   = errors.New("the value of a leaf can't be empty")
+	ErrDuplicateKey = errors.New("the keys of a batch proof must be distinct")
+	ErrNoKeys       = errors.New("a batch proof needs at least one key")
+)
+
+// prefixes separating the domains of the leaves and of the internal nodes of a
+// SparseTree
+var (
+	sparseLeafPrefix = []byte{0x00}
+	sparseNodePrefix = []byte{0x01}
+)
+
+// A SparseTree is a keyed Merkle tree of fixed depth, where the leaf at the
+// position given by the bits of the key (most significant bit first, 0 for
+// the left child) stores the value attached to the key.
+//
+// Leaves are hashed as H(0x00 ∥ value), and internal nodes as
+// H(0x01 ∥ left ∥ right), each part being written separately to the hash, so
+// that the hash of a leaf can't be the hash of an internal node (second
+// preimage attack). The hash of an empty leaf is made of zero bytes, so that
+// the hash of an empty subtree of a given height is a known constant. Only the
+// non empty subtrees are stored, so the memory footprint grows in O(depth)
+// per key.
+//
+// With a SNARK friendly hash function such as MiMC or Poseidon2, the values
+// must be encoded as field elements, so that the proofs can be verified in a
+// circuit. The prefixes are then the field elements 0 and 1, as these hash
+// functions left-pad the short inputs to a field element.
+type SparseTree struct {
+	hash  hash.Hash
+	depth int
+
+	// values of the leaves, indexed by key
+	values map[string][]byte
+
+	// non empty nodes, indexed by height and position
+	nodes map[sparseNodeID][]byte
+
+	// empty[i] is the hash of an empty subtree of height i
+	empty [][]byte
+}
+
+// sparseNodeID identifies a node by its height (0 for the leaves), and the key
+// of its leftmost leaf.
+type sparseNodeID struct {
+	height int
+	prefix string
+}
+
+// SparseProof proves that a key is in a SparseTree with a given value
+// (membership), or that it is not in the tree (non membership).
+type SparseProof struct {
+	// Key of the leaf
+	Key []byte
+
+	// Value of the leaf, nil for a proof of non membership
+	Value []byte
+
+	// Siblings of the nodes on the path from the leaf to the root, Siblings[0]
+	// being the sibling of the leaf.
+	Siblings [][]byte
+}
+
+// SparseBatchProof proves the membership or non membership of several keys at
+// once. The siblings which can be computed from the other leaves are omitted.
+type SparseBatchProof struct {
+	// Keys of the leaves, sorted
+	Keys [][]byte
+
+	// Values of the leaves, nil for the keys which are not in the tree
+	Values [][]byte
+
+	// Siblings needed to compute the root, level by level from the leaves to
+	// the root, and from left to right within a level. A nil sibling stands
+	// for an empty subtree.
+	Siblings [][]byte
+}
+
+// NewSparseTree creates a new empty SparseTree of the given depth, whose keys
+// are of size (depth+7)/8 bytes. The provided hash will be used for all hashing
+// operations within the tree. It panics if the depth is not positive.
+func NewSparseTree(h hash.Hash, depth int) *SparseTree {
+	if depth < 1 {
+		panic("the depth of a sparse Merkle tree must be positive")
+	}
+	t := &SparseTree{
+		hash:   h,
+		depth:  depth,
+		values: make(map[string][]byte),
+		nodes:  make(map[sparseNodeID][]byte),
+	}
+	t.empty = emptySubTrees(h, depth)
+	return t
+}
+
+// emptySubTrees returns the hashes of the empty subtrees of height 0 to depth
+func emptySubTrees(h hash.Hash, depth int) [][]byte {
+	res := make([][]byte, depth+1)
+	res[0] = make([]byte, h.Size())
+	for i := 1; i <= depth; i++ {
+		res[i] = sparseNodeSum(h, res[i-1], res[i-1])
+	}
+	return res
+}
+
+// Depth returns the depth of the tree
+func (t *SparseTree) Depth() int {
+	return t.depth
+}
+
+// KeySize returns the size in bytes of the keys
+func (t *SparseTree) KeySize() int {
+	return keySize(t.depth)
+}
+
+func keySize(depth int) int {
+	return (depth + 7) / 8
+}
+
+// Root returns the Merkle root of the tree.
+func (t *SparseTree) Root() []byte {
+	root := t.node(t.depth, nil)
+	return append(root[:0:0], root...)
+}
+
+// Get returns the value attached to key.
+func (t *SparseTree) Get(key []byte) ([]byte, error) {
+	if err := checkKey(key, t.depth); err != nil {
+		return nil, err
+	}
+	value, ok := t.values[string(key)]
+	if !ok {
+		return nil, ErrKeyNotFound
+	}
+	return append(value[:0:0], value...), nil
+}
+
+// Insert adds the leaf (key, value) to the tree. It returns an error if the key
+// is already in the tree.
+func (t *SparseTree) Insert(key, value []byte) error {
+	if err := checkKey(key, t.depth); err != nil {
+		return err
+	}
+	if _, ok := t.values[string(key)]; ok {
+		return ErrKeyExists
+	}
+	return t.set(key, value)
+}
+
+// Update changes the value attached to key. It returns an error if the key is
+// not in the tree.
+func (t *SparseTree) Update(key, value []byte) error {
+	if err := checkKey(key, t.depth); err != nil {
+		return err
+	}
+	if _, ok := t.values[string(key)]; !ok {
+		return ErrKeyNotFound
+	}
+	return t.set(key, value)
+}
+
+// Delete removes the leaf attached to key. It returns an error if the key is not
+// in the tree.
+func (t *SparseTree) Delete(key []byte) error {
+	if err := checkKey(key, t.depth); err != nil {
+		return err
+	}
+	if _, ok := t.values[string(key)]; !ok {
+		return ErrKeyNotFound
+	}
+	delete(t.values, string(key))
+	t.updatePath(key, t.empty[0])
+	return nil
+}
+
+// set sets the value of the leaf at key and updates the path to the root
+func (t *SparseTree) set(key, value []byte) error {
+	if len(value) == 0 {
+		return ErrEmptyValue
+	}
+	t.values[string(key)] = append(value[:0:0], value...)
+	t.updatePath(key, sparseLeafSum(t.hash, value))
+	return nil
+}
+
+// updatePath sets the hash of the leaf at key to sum and recomputes the nodes up
+// to the root. The nodes equal to empty subtrees are not stored.
+func (t *SparseTree) updatePath(key, sum []byte) {
+	prefix := append(key[:0:0], key...)
+	for height := 0; ; height++ {
+		id := sparseNodeID{height, string(prefix)}
+		if bytes.Equal(sum, t.empty[height]) {
+			delete(t.nodes, id)
+		} else {
+			t.nodes[id] = sum
+		}
+		if height == t.depth {
+			return
+		}
+
+		// the sibling shares the prefix up to the bit of this level
+		sibling := t.node(height, flipBit(prefix, t.depth-1-height))
+		if getBit(prefix, t.depth-1-height) == 0 {
+			sum = sparseNodeSum(t.hash, sum, sibling)
+		} else {
+			sum = sparseNodeSum(t.hash, sibling, sum)
+		}
+		prefix = clearBit(prefix, t.depth-1-height)
+	}
+}
+
+// node returns the hash of the node at height whose leftmost leaf is prefix.
+func (t *SparseTree) node(height int, prefix []byte) []byte {
+	if height == t.depth {
+		prefix = make([]byte, keySize(t.depth))
+	}
+	if sum, ok := t.nodes[sparseNodeID{height, string(prefix)}]; ok {
+		return sum
+	}
+	return t.empty[height]
+}
+
+// Prove returns a proof of membership of key if it is in the tree, and a
+// proof of non membership otherwise.
+func (t *SparseTree) Prove(key []byte) (SparseProof, error) {
+	if err := checkKey(key, t.depth); err != nil {
+		return SparseProof{}, err
+	}
+	proof := SparseProof{
+		Key:      append(key[:0:0], key...),
+		Siblings: make([][]byte, t.depth),
+	}
+	if value, ok := t.values[string(key)]; ok {
+		proof.Value = append(value[:0:0], value...)
+	}
+	prefix := append(key[:0:0], key...)
+	for height := 0; height < t.depth; height++ {
+		sibling := t.node(height, flipBit(prefix, t.depth-1-height))
+		proof.Siblings[height] = append(sibling[:0:0], sibling...)
+		prefix = clearBit(prefix, t.depth-1-height)
+	}
+	return proof, nil
+}
+
+// VerifySparseProof returns true if the proof is a valid proof of membership
+// (if proof.Value is not nil) or non membership (if proof.Value is nil) for
+// the sparse Merkle tree of depth len(proof.Siblings) and root merkleRoot.
+func VerifySparseProof(h hash.Hash, merkleRoot []byte, proof SparseProof) bool {
+	depth := len(proof.Siblings)
+	if depth == 0 || checkKey(proof.Key, depth) != nil {
+		return false
+	}
+	var sum []byte
+	if proof.Value == nil {
+		sum = make([]byte, h.Size())
+	} else {
+		if len(proof.Value) == 0 {
+			return false
+		}
+		sum = sparseLeafSum(h, proof.Value)
+	}
+	for height := 0; height < depth; height++ {
+		if getBit(proof.Key, depth-1-height) == 0 {
+			sum = sparseNodeSum(h, sum, proof.Siblings[height])
+		} else {
+			sum = sparseNodeSum(h, proof.Siblings[height], sum)
+		}
+	}
+	return bytes.Equal(sum, merkleRoot)
+}
+
+// ProveBatch returns a proof of membership or non membership of each of the keys.
+func (t *SparseTree) ProveBatch(keys [][]byte) (SparseBatchProof, error) {
+	var proof SparseBatchProof
+	proof.Keys = make([][]byte, len(keys))
+	for i := range keys {
+		if err := checkKey(keys[i], t.depth); err != nil {
+			return SparseBatchProof{}, err
+		}
+		proof.Keys[i] = append(keys[i][:0:0], keys[i]...)
+	}
+	if err := sortKeys(proof.Keys); err != nil {
+		return SparseBatchProof{}, err
+	}
+
+	proof.Values = make([][]byte, len(keys))
+	leaves := make([][]byte, len(keys))
+	for i := range proof.Keys {
+		if value, ok := t.values[string(proof.Keys[i])]; ok {
+			proof.Values[i] = append(value[:0:0], value...)
+		}
+		leaves[i] = t.node(0, proof.Keys[i])
+	}
+
+	// the siblings which are not computed from the leaves are taken from the tree
+	walkBatch(t.hash, t.depth, proof.Keys, leaves, func(height int, prefix []byte) ([]byte, bool) {
+		sum, ok := t.nodes[sparseNodeID{height, string(prefix)}]
+		if !ok {
+			sum = nil
+		}
+		proof.Siblings = append(proof.Siblings, sum)
+		return t.node(height, prefix), true
+	})
+
+	return proof, nil
+}
+
+// VerifySparseBatchProof returns true if the proof is a valid batch proof for
+// the sparse Merkle tree of the given depth and root merkleRoot.
+func VerifySparseBatchProof(h hash.Hash, merkleRoot []byte, depth int, proof SparseBatchProof) bool {
+	if depth < 1 || len(proof.Keys) == 0 || len(proof.Keys) != len(proof.Values) {
+		return false
+	}
+	for i := range proof.Keys {
+		if checkKey(proof.Keys[i], depth) != nil {
+			return false
+		}
+		// the keys are sorted and distinct
+		if i > 0 && bytes.Compare(proof.Keys[i-1], proof.Keys[i]) >= 0 {
+			return false
+		}
+	}
+
+	leaves := make([][]byte, len(proof.Keys))
+	for i := range proof.Values {
+		if proof.Values[i] == nil {
+			leaves[i] = make([]byte, h.Size())
+		} else {
+			if len(proof.Values[i]) == 0 {
+				return false
+			}
+			leaves[i] = sparseLeafSum(h, proof.Values[i])
+		}
+	}
+
+	var empty [][]byte
+	next := 0
+	root, ok := walkBatch(h, depth, proof.Keys, leaves, func(height int, _ []byte) ([]byte, bool) {
+		if next >= len(proof.Siblings) {
+			return nil, false
+		}
+		sibling := proof.Siblings[next]
+		next++
+		if sibling == nil {
+			if empty == nil {
+				empty = emptySubTrees(h, depth)
+			}
+			return empty[height], true
+		}
+		return sibling, true
+	})
+
+	return ok && next == len(proof.Siblings) && bytes.Equal(root, merkleRoot)
+}
+
+// walkBatch computes the root of the tree from the leaves at the sorted keys,
+// level by level. The siblings which are not computed from the leaves are
+// given by the sibling callback, from left to right within a level.
+func walkBatch(h hash.Hash, depth int, keys, leaves [][]byte, sibling func(height int, prefix []byte) ([]byte, bool)) ([]byte, bool) {
+	prefixes := make([][]byte, len(keys))
+	sums := make([][]byte, len(leaves))
+	copy(prefixes, keys)
+	copy(sums, leaves)
+
+	for height := 0; height < depth; height++ {
+		bit := depth - 1 - height
+		nextPrefixes := prefixes[:0]
+		nextSums := sums[:0]
+		for i := 0; i < len(prefixes); i++ {
+			var sum []byte
+			if getBit(prefixes[i], bit) == 0 && i+1 < len(prefixes) && bytes.Equal(prefixes[i+1], flipBit(prefixes[i], bit)) {
+				// both children are known
+				sum = sparseNodeSum(h, sums[i], sums[i+1])
+				i++
+			} else {
+				s, ok := sibling(height, flipBit(prefixes[i], bit))
+				if !ok {
+					return nil, false
+				}
+				if getBit(prefixes[i], bit) == 0 {
+					sum = sparseNodeSum(h, sums[i], s)
+				} else {
+					sum = sparseNodeSum(h, s, sums[i])
+				}
+			}
+			nextPrefixes = append(nextPrefixes, clearBit(prefixes[i], bit))
+			nextSums = append(nextSums, sum)
+		}
+		prefixes, sums = nextPrefixes, nextSums
+	}
+
+	return sums[0], true
+}
+
+// sparseLeafSum returns the hash of the leaf storing value
+func sparseLeafSum(h hash.Hash, value []byte) []byte {
+	return sum(h, sparseLeafPrefix, value)
+}
+
+// sparseNodeSum returns the hash of the internal node whose children are a
+// and b
+func sparseNodeSum(h hash.Hash, a, b []byte) []byte {
+	return sum(h, sparseNodePrefix, a, b)
+}
+
+// sortKeys sorts the keys and checks that they are distinct
+func sortKeys(keys [][]byte) error {
+	if len(keys) == 0 {
+		return ErrNoKeys
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return bytes.Compare(keys[i], keys[j]) < 0
+	})
+	for i := 1; i < len(keys); i++ {
+		if bytes.Equal(keys[i-1], keys[i]) {
+			return ErrDuplicateKey
+		}
+	}
+	return nil
+}
+
+// checkKey checks that the key has the right size, and that the bits beyond
+// the depth are zero.
+func checkKey(key []byte, depth int) error {
+	if len(key) != keySize(depth) {
+		return ErrKeySize
+	}
+	if r := depth % 8; r != 0 && key[len(key)-1]&(0xff>>r) != 0 {
+		return ErrKeySize
+	}
+	return nil
+}
+
+// getBit returns the i-th bit of key, most significant bit first
+func getBit(key []byte, i int) byte {
+	return (key[i/8] >> (7 - i%8)) & 1
+}
+
+// flipBit returns a copy of key with the i-th bit flipped
+func flipBit(key []byte, i int) []byte {
+	res := append(key[:0:0], key...)
+	res[i/8] ^= 1 << (7 - i%8)
+	return res
+}
+
+// clearBit returns a copy of key with the i-th bit set to 0
+func clearBit(key []byte, i int) []byte {
+	res := append(key[:0:0], key...)
+	res[i/8] &^= 1 << (7 - i%8)
+	return res
+}
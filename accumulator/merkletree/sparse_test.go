@@ -0,0 +1,251 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package merkletree
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"hash"
+	"math/rand"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/mimc"
+)
+
+func TestSparseTree(t *testing.T) {
+	for _, depth := range []int{1, 7, 16, 256} {
+		testSparseTree(t, sha256.New(), depth, func(r *rand.Rand) []byte {
+			value := make([]byte, 1+r.Intn(40))
+			r.Read(value)
+			return value
+		})
+	}
+
+	// values are field elements so that proofs can be verified in a circuit
+	testSparseTree(t, mimc.NewMiMC(), 254, func(r *rand.Rand) []byte {
+		var e fr.Element
+		e.SetUint64(r.Uint64())
+		b := e.Bytes()
+		return b[:]
+	})
+}
+
+func randomKey(r *rand.Rand, depth int) []byte {
+	key := make([]byte, keySize(depth))
+	r.Read(key)
+	if rem := depth % 8; rem != 0 {
+		key[len(key)-1] &= ^byte(0xff >> rem)
+	}
+	return key
+}
+
+func testSparseTree(t *testing.T, h hash.Hash, depth int, randomValue func(*rand.Rand) []byte) {
+	r := rand.New(rand.NewSource(int64(depth))) //#nosec G404 weak rng is fine here
+
+	tree := NewSparseTree(h, depth)
+	emptyRoot := tree.Root()
+
+	// insert distinct keys
+	nbKeys := 20
+	if depth < 5 {
+		nbKeys = 1 << depth
+	}
+	keys := make([][]byte, 0, nbKeys)
+	values := make(map[string][]byte)
+	for len(keys) < nbKeys {
+		key := randomKey(r, depth)
+		if _, ok := values[string(key)]; ok {
+			continue
+		}
+		value := randomValue(r)
+		if err := tree.Insert(key, value); err != nil {
+			t.Fatal(err)
+		}
+		keys = append(keys, key)
+		values[string(key)] = value
+	}
+	if err := tree.Insert(keys[0], values[string(keys[0])]); err != ErrKeyExists {
+		t.Fatal("inserting an existing key should fail")
+	}
+
+	// membership proofs
+	root := tree.Root()
+	for _, key := range keys {
+		proof, err := tree.Prove(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(proof.Value, values[string(key)]) {
+			t.Fatal("wrong value in the proof")
+		}
+		if !VerifySparseProof(h, root, proof) {
+			t.Fatal("verifying a correct proof of membership should succeed")
+		}
+		proof.Value = randomValue(r)
+		if VerifySparseProof(h, root, proof) {
+			t.Fatal("verifying a proof with a wrong value should fail")
+		}
+		proof.Value = nil
+		if VerifySparseProof(h, root, proof) {
+			t.Fatal("verifying a proof of non membership of a key in the tree should fail")
+		}
+	}
+
+	// non membership proofs
+	if len(keys) < 1<<depth {
+		var absent []byte
+		for absent == nil {
+			key := randomKey(r, depth)
+			if _, ok := values[string(key)]; !ok {
+				absent = key
+			}
+		}
+		proof, err := tree.Prove(absent)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if proof.Value != nil || !VerifySparseProof(h, root, proof) {
+			t.Fatal("verifying a correct proof of non membership should succeed")
+		}
+		if _, err := tree.Get(absent); err != ErrKeyNotFound {
+			t.Fatal("getting a key which is not in the tree should fail")
+		}
+		if err := tree.Update(absent, randomValue(r)); err != ErrKeyNotFound {
+			t.Fatal("updating a key which is not in the tree should fail")
+		}
+		if err := tree.Delete(absent); err != ErrKeyNotFound {
+			t.Fatal("deleting a key which is not in the tree should fail")
+		}
+
+		// batch proof with a key which is not in the tree
+		batch, err := tree.ProveBatch([][]byte{keys[1], absent, keys[0]})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !VerifySparseBatchProof(h, root, depth, batch) {
+			t.Fatal("verifying a correct batch proof should succeed")
+		}
+	}
+
+	// batch proofs
+	batch, err := tree.ProveBatch(keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !VerifySparseBatchProof(h, root, depth, batch) {
+		t.Fatal("verifying a correct batch proof should succeed")
+	}
+	batch.Values[0] = randomValue(r)
+	if VerifySparseBatchProof(h, root, depth, batch) {
+		t.Fatal("verifying a batch proof with a wrong value should fail")
+	}
+	if _, err := tree.ProveBatch([][]byte{keys[0], keys[0]}); err != ErrDuplicateKey {
+		t.Fatal("a batch proof with duplicate keys should fail")
+	}
+
+	// update
+	value := randomValue(r)
+	if err := tree.Update(keys[0], value); err != nil {
+		t.Fatal(err)
+	}
+	if v, err := tree.Get(keys[0]); err != nil || !bytes.Equal(v, value) {
+		t.Fatal("wrong value after update")
+	}
+	proof, _ := tree.Prove(keys[0])
+	if !VerifySparseProof(h, tree.Root(), proof) || VerifySparseProof(h, root, proof) {
+		t.Fatal("the proof should be valid for the new root only")
+	}
+
+	// the root doesn't depend on the order of the insertions
+	other := NewSparseTree(h, depth)
+	for i := len(keys) - 1; i >= 0; i-- {
+		v, _ := tree.Get(keys[i])
+		if err := other.Insert(keys[i], v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !bytes.Equal(other.Root(), tree.Root()) {
+		t.Fatal("the root should not depend on the order of insertions")
+	}
+
+	// delete everything
+	for _, key := range keys {
+		if err := tree.Delete(key); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !bytes.Equal(tree.Root(), emptyRoot) || len(tree.nodes) != 0 {
+		t.Fatal("the tree should be empty")
+	}
+}
+
+func TestSparseTreeKeys(t *testing.T) {
+	tree := NewSparseTree(sha256.New(), 12)
+	if tree.KeySize() != 2 {
+		t.Fatal("wrong key size")
+	}
+	if err := tree.Insert([]byte{1}, []byte{1}); err != ErrKeySize {
+		t.Fatal("a key of the wrong size should be rejected")
+	}
+	if err := tree.Insert([]byte{1, 1}, []byte{1}); err != ErrKeySize {
+		t.Fatal("a key with bits beyond the depth should be rejected")
+	}
+	if err := tree.Insert([]byte{1, 0x10}, nil); err != ErrEmptyValue {
+		t.Fatal("an empty value should be rejected")
+	}
+}
+
+func TestSparseTreeNoKeys(t *testing.T) {
+	tree := NewSparseTree(sha256.New(), 8)
+	if _, err := tree.ProveBatch(nil); err != ErrNoKeys {
+		t.Fatal("a batch proof without keys should fail")
+	}
+}
+
+func TestSparseTreeDomainSeparation(t *testing.T) {
+	// the children of an internal node can't be passed off as the value of a
+	// leaf, in a proof for a tree of lower depth
+	h := sha256.New()
+	tree := NewSparseTree(h, 2)
+	for i, key := range []byte{0x00, 0x40, 0x80} {
+		if err := tree.Insert([]byte{key}, []byte{byte(i + 1)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	proof, err := tree.Prove([]byte{0x00})
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf0 := sparseLeafSum(h, []byte{1})
+	forged := SparseProof{
+		Key:      []byte{0x00},
+		Value:    append(leaf0, proof.Siblings[0]...),
+		Siblings: proof.Siblings[1:],
+	}
+	if VerifySparseProof(h, tree.Root(), forged) {
+		t.Fatal("an internal node should not verify as a leaf")
+	}
+}
+
+func BenchmarkSparseTreeInsert(b *testing.B) {
+	r := rand.New(rand.NewSource(0)) //#nosec G404 weak rng is fine here
+	tree := NewSparseTree(sha256.New(), 256)
+	value := []byte{1}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = tree.Insert(randomKey(r, 256), value)
+	}
+}
@@ -54,6 +54,15 @@ type Tree struct {
 	proofSet     [][]byte
 	proofTree    bool
 
+	// Helper variables used to construct proofs that the data at several
+	// indices is in the Merkle tree, see SetIndices. multiLeaves stores the
+	// data at the proof indices, and multiNodes the sums of the subtrees which
+	// are siblings of a subtree containing a proof index.
+	proofIndices   []uint64
+	multiLeaves    map[uint64][]byte
+	multiNodes     map[multiNodeID][]byte
+	multiProofTree bool
+
 	// The cachedTree flag indicates that the tree is cached, meaning that
 	// different code is used in 'Push' for creating a new head subtree. Adding
 	// this flag is somewhat gross, but eliminates needing to duplicate the
@@ -209,6 +218,9 @@ func (t *Tree) Push(data []byte) {
 	if t.currentIndex == t.proofIndex {
 		t.proofSet = append(t.proofSet, data)
 	}
+	if t.multiProofTree && t.containsIndex(t.currentIndex, t.currentIndex+1) {
+		t.multiLeaves[t.currentIndex] = data
+	}
 
 	// Hash the data to create a subtree of height 0. The sum of the new node
 	// is going to be the data for cached trees, and is going to be the result
@@ -259,6 +271,9 @@ func (t *Tree) PushSubTree(height int, sum []byte) error {
 		(t.currentIndex < t.proofIndex && t.proofIndex < newIndex)) {
 		return errors.New("the cached tree shouldn't contain the element to prove")
 	}
+	if t.multiProofTree && t.containsIndex(t.currentIndex, newIndex) {
+		return errors.New("the cached tree shouldn't contain the elements to prove")
+	}
 
 	// We can only add the cached tree if its depth is <= the depth of the
 	// current subtree.
@@ -328,6 +343,10 @@ func (t *Tree) SetIndex(i uint64) error {
 // height of the next subTree is the same as the height of the current subTree,
 // the two will be combined into a single subTree of height n+1.
 func (t *Tree) joinAllSubTrees() {
+	// index of the last leaf of the subtree that has just been pushed
+	last := t.currentIndex + 1<<uint(t.head.height) - 1
+	t.recordSubTree(t.head.height, last, t.head.sum)
+
 	for t.head.next != nil && t.head.height == t.head.next.height {
 		// Before combining subtrees, check whether one of the subtree hashes
 		// needs to be added to the proof set. This is going to be true IFF the
@@ -361,5 +380,6 @@ func (t *Tree) joinAllSubTrees() {
 		// Join the two subTrees into one subTree with a greater height. Then
 		// compare the new subTree to the next subTree.
 		t.head = joinSubTrees(t.hash, t.head.next, t.head)
+		t.recordSubTree(t.head.height, last, t.head.sum)
 	}
 }
@@ -0,0 +1,276 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package fri
+
+import (
+	"bytes"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/accumulator/merkletree"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	fiatshamir "github.com/consensys/gnark-crypto/fiat-shamir"
+)
+
+// The batch proof of proximity commits to the codewords of all the polynomials
+// in a single Merkle tree, whose leaf j contains the cosets {pᵢ(xωᵏ)}ₖ of each
+// polynomial pᵢ, where x = gʲ. After the root is bound to the transcript, the
+// verifier sends γ and the prover runs FRI on the random linear combination
+//
+//	f = ∑ᵢ (γ²ⁱ + γ²ⁱ⁺¹X^{n-nᵢ})pᵢ
+//
+// where nᵢ is the size of pᵢ and n the size handled by the iopp. The second
+// term ensures that each pᵢ is close to a polynomial of degree less than nᵢ,
+// and not only of degree less than n. The first step of FRI folds the values of f
+// that the verifier computes from the leaves of the batch tree.
+
+// batchCoefficients returns the coefficients (γ²ⁱ, γ²ⁱ⁺¹) and the shifts n-nᵢ of
+// the linear combination.
+func (s radixTwoFri) batchCoefficients(gamma fr.Element, sizes []uint64) (a, b []fr.Element, shifts []uint64, err error) {
+	a = make([]fr.Element, len(sizes))
+	b = make([]fr.Element, len(sizes))
+	shifts = make([]uint64, len(sizes))
+	var acc fr.Element
+	acc.SetOne()
+	for i := range sizes {
+		if sizes[i] == 0 || sizes[i] > s.degreeBound {
+			return nil, nil, nil, ErrPolynomialSize
+		}
+		shifts[i] = s.degreeBound - sizes[i]
+		a[i].Set(&acc)
+		acc.Mul(&acc, &gamma)
+		b[i].Set(&acc)
+		acc.Mul(&acc, &gamma)
+	}
+	return
+}
+
+// batchLeaves returns the leaves of the Merkle tree committing to all the
+// codewords. The leaf j contains the cosets {pᵢ[j + k*size/arity]}ₖ, i < len(codewords).
+func (s radixTwoFri) batchLeaves(codewords [][]fr.Element) [][]byte {
+	step := &s.steps[0]
+	m := int(step.size) / step.arity
+	res := make([][]byte, m)
+	for j := 0; j < m; j++ {
+		res[j] = make([]byte, 0, len(codewords)*step.arity*fr.Bytes)
+		for i := range codewords {
+			for k := 0; k < step.arity; k++ {
+				b := codewords[i][j+k*m].Bytes()
+				res[j] = append(res[j], b[:]...)
+			}
+		}
+	}
+	return res
+}
+
+// readBatchLeaf reads the cosets of the nbPolynomials polynomials stored in a
+// leaf of the batch tree.
+func (s radixTwoFri) readBatchLeaf(leaf []byte, nbPolynomials int) ([][]fr.Element, error) {
+	arity := s.steps[0].arity
+	if len(leaf) != nbPolynomials*arity*fr.Bytes {
+		return nil, ErrProofShape
+	}
+	res := make([][]fr.Element, nbPolynomials)
+	for i := range res {
+		res[i] = make([]fr.Element, arity)
+		for k := range res[i] {
+			offset := (i*arity + k) * fr.Bytes
+			if err := res[i][k].SetBytesCanonical(leaf[offset : offset+fr.Bytes]); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return res, nil
+}
+
+// batchTranscript returns the transcript of a batch proof, with the root of
+// the batch tree bound to γ, and γ.
+func (s radixTwoFri) batchTranscript(root []byte) (*fiatshamir.Transcript, fr.Element, error) {
+	var gamma fr.Element
+	fs := s.transcript("gamma")
+	if err := fs.Bind("gamma", root); err != nil {
+		return nil, gamma, err
+	}
+	bGamma, err := fs.ComputeChallenge("gamma")
+	if err != nil {
+		return nil, gamma, err
+	}
+	gamma.SetBytes(bGamma)
+	return fs, gamma, nil
+}
+
+// BuildProofOfProximityBatch generates a proof that each of the functions, given
+// as oracles from the verifier point of view, is δ-close to a polynomial of
+// degree less than len(polynomials[i]).
+//
+// The first interaction of each round opens the leaf of the batch tree, so that
+// the proof can be used with OpenBatch and VerifyOpeningBatch.
+func (s radixTwoFri) BuildProofOfProximityBatch(polynomials [][]fr.Element) (ProofOfProximity, error) {
+
+	if len(polynomials) == 0 {
+		return ProofOfProximity{}, ErrPolynomialSize
+	}
+
+	// evaluate the polynomials and commit to them
+	sizes := make([]uint64, len(polynomials))
+	codewords := make([][]fr.Element, len(polynomials))
+	for i := range polynomials {
+		sizes[i] = uint64(len(polynomials[i]))
+		var err error
+		if codewords[i], err = s.codeword(polynomials[i]); err != nil {
+			return ProofOfProximity{}, err
+		}
+	}
+	tree := newMerkleTree(s.h, s.batchLeaves(codewords))
+
+	fs, gamma, err := s.batchTranscript(tree.root())
+	if err != nil {
+		return ProofOfProximity{}, err
+	}
+	a, b, shifts, err := s.batchCoefficients(gamma, sizes)
+	if err != nil {
+		return ProofOfProximity{}, err
+	}
+
+	// compute the linear combination, in canonical basis and on the domain
+	coefficients := make([]fr.Element, s.degreeBound)
+	codeword := make([]fr.Element, s.domain.Cardinality)
+	var t, xShift, gShift fr.Element
+	for i := range polynomials {
+		for j := range polynomials[i] {
+			t.Mul(&polynomials[i][j], &a[i])
+			coefficients[j].Add(&coefficients[j], &t)
+			t.Mul(&polynomials[i][j], &b[i])
+			coefficients[j+int(shifts[i])].Add(&coefficients[j+int(shifts[i])], &t)
+		}
+
+		gShift.Exp(s.domain.Generator, new(big.Int).SetUint64(shifts[i]))
+		xShift.SetOne()
+		for j := range codeword {
+			t.Mul(&xShift, &b[i]).Add(&t, &a[i]).Mul(&t, &codewords[i][j])
+			codeword[j].Add(&codeword[j], &t)
+			xShift.Mul(&xShift, &gShift)
+		}
+	}
+
+	return s.buildProof(fs, codeword, coefficients, &tree)
+}
+
+// VerifyProofOfProximityBatch verifies a proof built with BuildProofOfProximityBatch,
+// sizes[i] being the size of the i-th polynomial.
+func (s radixTwoFri) VerifyProofOfProximityBatch(sizes []uint64, proof ProofOfProximity) error {
+
+	if len(proof.Rounds) == 0 || len(proof.Rounds[0].Interactions) == 0 {
+		return ErrProofShape
+	}
+	fs, gamma, err := s.batchTranscript(proof.Rounds[0].Interactions[0].MerkleRoot)
+	if err != nil {
+		return err
+	}
+	a, b, shifts, err := s.batchCoefficients(gamma, sizes)
+	if err != nil {
+		return err
+	}
+
+	// the coset {xωᵏ} of the linear combination, where x = g^index
+	step := &s.steps[0]
+	m := step.size / uint64(step.arity)
+	readFirstLeaf := func(index uint64, leaf []byte) ([]fr.Element, error) {
+		cosets, err := s.readBatchLeaf(leaf, len(sizes))
+		if err != nil {
+			return nil, err
+		}
+		res := make([]fr.Element, step.arity)
+		var x, xShift, t fr.Element
+		for k := range res {
+			x.Exp(s.domain.Generator, new(big.Int).SetUint64(index+uint64(k)*m))
+			for i := range cosets {
+				xShift.Exp(x, new(big.Int).SetUint64(shifts[i]))
+				t.Mul(&xShift, &b[i]).Add(&t, &a[i]).Mul(&t, &cosets[i][k])
+				res[k].Add(&res[k], &t)
+			}
+		}
+		return res, nil
+	}
+
+	return s.verifyProof(fs, proof, false, readFirstLeaf)
+}
+
+// OpenBatch opens each of the polynomials at gⁱ where i = position. The openings
+// share the same Merkle proof, in the tree committing to all the polynomials.
+func (s radixTwoFri) OpenBatch(polynomials [][]fr.Element, position uint64) ([]OpeningProof, error) {
+
+	// check that position is in the correct range
+	if position >= s.domain.Cardinality {
+		return nil, ErrRangePosition
+	}
+
+	codewords := make([][]fr.Element, len(polynomials))
+	for i := range polynomials {
+		var err error
+		if codewords[i], err = s.codeword(polynomials[i]); err != nil {
+			return nil, err
+		}
+	}
+	t := newMerkleTree(s.h, s.batchLeaves(codewords))
+	m := s.domain.Cardinality / uint64(s.steps[0].arity)
+	mp := t.proof(position % m)
+
+	res := make([]OpeningProof, len(polynomials))
+	for i := range res {
+		res[i].merkleRoot, res[i].ProofSet, res[i].numLeaves = mp.MerkleRoot, mp.ProofSet, mp.numLeaves
+		res[i].index = position % m
+		res[i].ClaimedValue.Set(&codewords[i][position])
+	}
+
+	return res, nil
+}
+
+// VerifyOpeningBatch verifies the openings of the polynomials at gⁱ where
+// i = position, against a proof built with BuildProofOfProximityBatch. The
+// openings must be given in the same order as the polynomials.
+func (s radixTwoFri) VerifyOpeningBatch(position uint64, openingProofs []OpeningProof, pp ProofOfProximity) error {
+
+	if position >= s.domain.Cardinality {
+		return ErrRangePosition
+	}
+	if len(pp.Rounds) == 0 || len(pp.Rounds[0].Interactions) == 0 {
+		return ErrProofShape
+	}
+
+	m := s.domain.Cardinality / uint64(s.steps[0].arity)
+	for i := range openingProofs {
+
+		// check that the merkle roots coincide
+		if !bytes.Equal(openingProofs[i].merkleRoot, pp.Rounds[0].Interactions[0].MerkleRoot) {
+			return ErrMerkleRoot
+		}
+
+		// the leaf contains the cosets of all the polynomials
+		if !merkletree.VerifyProof(s.h, openingProofs[i].merkleRoot, openingProofs[i].ProofSet, position%m, m) {
+			return ErrMerklePath
+		}
+		cosets, err := s.readBatchLeaf(openingProofs[i].ProofSet[0], len(openingProofs))
+		if err != nil {
+			return err
+		}
+		if !cosets[i][position/m].Equal(&openingProofs[i].ClaimedValue) {
+			return ErrMerklePath
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,166 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package fri
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+)
+
+func TestFRIBatch(t *testing.T) {
+
+	const size = 512
+
+	polynomials := [][]fr.Element{
+		randomPolynomial(size, 1),
+		randomPolynomial(300, 2),
+		randomPolynomial(17, 3),
+		randomPolynomial(1, 4),
+	}
+	sizes := []uint64{size, 300, 17, 1}
+
+	for _, arity := range []int{2, 4, 8, 16} {
+		t.Run(fmt.Sprintf("arity=%d", arity), func(t *testing.T) {
+
+			iop := RADIX_2_FRI.New(size, sha256.New(),
+				WithFoldingArity(arity),
+				WithNbQueries(4),
+				WithFinalDegree(7),
+			)
+			proof, err := iop.BuildProofOfProximityBatch(polynomials)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := iop.VerifyProofOfProximityBatch(sizes, proof); err != nil {
+				t.Fatal(err)
+			}
+
+			// the degree bounds are part of the statement
+			wrongSizes := []uint64{size, 300, 16, 1}
+			if err := iop.VerifyProofOfProximityBatch(wrongSizes, proof); err == nil {
+				t.Fatal("verifying with wrong sizes should fail")
+			}
+			if err := iop.VerifyProofOfProximityBatch(sizes[:3], proof); err == nil {
+				t.Fatal("verifying with a wrong number of polynomials should fail")
+			}
+
+			// openings in the batch tree
+			const position = 1234
+			openings, err := iop.OpenBatch(polynomials, position)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := iop.VerifyOpeningBatch(position, openings, proof); err != nil {
+				t.Fatal(err)
+			}
+			if err := iop.VerifyOpeningBatch(position+1, openings, proof); err == nil {
+				t.Fatal("verifying an opening at a wrong position should fail")
+			}
+			openings[2].ClaimedValue.SetOne()
+			if err := iop.VerifyOpeningBatch(position, openings, proof); err == nil {
+				t.Fatal("verifying a wrong claimed value should fail")
+			}
+		})
+	}
+
+	// a polynomial larger than announced can't be committed
+	iop := RADIX_2_FRI.New(size, sha256.New())
+	if _, err := iop.BuildProofOfProximityBatch([][]fr.Element{randomPolynomial(size+1, 1)}); err != ErrPolynomialSize {
+		t.Fatal("a polynomial of too large degree should be rejected")
+	}
+}
+
+func TestDeepQuotient(t *testing.T) {
+
+	const size = 256
+
+	polynomials := [][]fr.Element{
+		randomPolynomial(size, 5),
+		randomPolynomial(size, 6),
+		randomPolynomial(100, 7),
+	}
+
+	// out of domain point and batching challenge
+	var z, gamma fr.Element
+	z.SetRandom()
+	gamma.SetRandom()
+
+	q, ys := DeepQuotientBatch(polynomials, z, gamma)
+	if len(q) != size-1 {
+		t.Fatal("wrong size of the quotient")
+	}
+	for i := range polynomials {
+		if _, y := DeepQuotient(polynomials[i], z); !y.Equal(&ys[i]) {
+			t.Fatal("wrong evaluation")
+		}
+	}
+
+	// the prover commits to the polynomials and proves that the quotient is of low degree
+	iop := RADIX_2_FRI.New(size, sha256.New(), WithNbQueries(2))
+	proofPolynomials, err := iop.BuildProofOfProximityBatch(polynomials)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proofQuotient, err := iop.BuildProofOfProximity(q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := iop.VerifyProofOfProximity(proofQuotient); err != nil {
+		t.Fatal(err)
+	}
+
+	// the verifier computes the value of the quotient at a point of the domain
+	// from the openings of the polynomials
+	const position = 77
+	openings, err := iop.OpenBatch(polynomials, position)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := iop.VerifyOpeningBatch(position, openings, proofPolynomials); err != nil {
+		t.Fatal(err)
+	}
+	px := make([]fr.Element, len(openings))
+	for i := range openings {
+		px[i] = openings[i].ClaimedValue
+	}
+	var x fr.Element
+	x.Exp(iop.(radixTwoFri).domain.Generator, big.NewInt(position))
+	expected, err := DeepQuotientBatchEvaluation(px, ys, x, z, gamma)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	opening, err := iop.Open(q, position)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := iop.VerifyOpening(position, opening, proofQuotient); err != nil {
+		t.Fatal(err)
+	}
+	if !opening.ClaimedValue.Equal(&expected) {
+		t.Fatal("the opening of the quotient doesn't match the openings of the polynomials")
+	}
+
+	// the point should be out of the domain
+	if _, err := DeepQuotientEvaluation(px[0], ys[0], x, x); err != ErrDeepPointInDomain {
+		t.Fatal("a point in the domain should be rejected")
+	}
+}
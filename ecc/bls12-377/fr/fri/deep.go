@@ -0,0 +1,112 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package fri
+
+import (
+	"errors"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+)
+
+// ErrDeepPointInDomain is returned when the point of a DEEP quotient belongs to the evaluation domain
+var ErrDeepPointInDomain = errors.New("the DEEP point should not belong to the evaluation domain")
+
+// DeepQuotient returns y = p(z) and the coefficients of the quotient
+// (p(X) - y)/(X - z), of size len(p)-1, where p is given in canonical basis.
+//
+// This is the DEEP (Domain Extending for Eliminating Pretenders) technique: to
+// prove that p(z) = y for a point z outside of the evaluation domain, the
+// prover shows that the quotient is a polynomial of degree less than len(p)-1,
+// for instance with BuildProofOfProximityBatch. The verifier computes the values
+// of the quotient at the queried points from the openings of p, see
+// DeepQuotientEvaluation.
+func DeepQuotient(p []fr.Element, z fr.Element) ([]fr.Element, fr.Element) {
+	if len(p) == 0 {
+		return nil, fr.Element{}
+	}
+
+	// synthetic division by X - z, the remainder is p(z)
+	q := make([]fr.Element, len(p)-1)
+	var y fr.Element
+	y.Set(&p[len(p)-1])
+	for i := len(p) - 2; i >= 0; i-- {
+		q[i].Set(&y)
+		y.Mul(&y, &z).Add(&y, &p[i])
+	}
+	return q, y
+}
+
+// DeepQuotientEvaluation returns (px - y)/(x - z), the evaluation at x of the
+// DEEP quotient of p at z (see DeepQuotient), where px = p(x) and y = p(z).
+func DeepQuotientEvaluation(px, y, x, z fr.Element) (fr.Element, error) {
+	var num, den fr.Element
+	den.Sub(&x, &z)
+	if den.IsZero() {
+		return fr.Element{}, ErrDeepPointInDomain
+	}
+	num.Sub(&px, &y)
+	den.Inverse(&den)
+	return *num.Mul(&num, &den), nil
+}
+
+// DeepQuotientBatch returns the evaluations yᵢ = pᵢ(z) and the coefficients of the
+// combined quotient ∑ᵢ γⁱ(pᵢ(X) - yᵢ)/(X - z), of size max(len(pᵢ))-1.
+// Opening many polynomials at the same point z then costs a single proof of
+// proximity.
+func DeepQuotientBatch(polynomials [][]fr.Element, z, gamma fr.Element) ([]fr.Element, []fr.Element) {
+	size := 0
+	for i := range polynomials {
+		if len(polynomials[i]) > size {
+			size = len(polynomials[i])
+		}
+	}
+	if size == 0 {
+		return nil, make([]fr.Element, len(polynomials))
+	}
+
+	res := make([]fr.Element, size-1)
+	ys := make([]fr.Element, len(polynomials))
+	var acc, t fr.Element
+	acc.SetOne()
+	for i := range polynomials {
+		var q []fr.Element
+		q, ys[i] = DeepQuotient(polynomials[i], z)
+		for j := range q {
+			t.Mul(&q[j], &acc)
+			res[j].Add(&res[j], &t)
+		}
+		acc.Mul(&acc, &gamma)
+	}
+	return res, ys
+}
+
+// DeepQuotientBatchEvaluation returns ∑ᵢ γⁱ(pxᵢ - yᵢ)/(x - z), the evaluation at x
+// of the combined quotient returned by DeepQuotientBatch, where pxᵢ = pᵢ(x) and
+// yᵢ = pᵢ(z).
+func DeepQuotientBatchEvaluation(px, ys []fr.Element, x, z, gamma fr.Element) (fr.Element, error) {
+	if len(px) != len(ys) {
+		return fr.Element{}, ErrProofShape
+	}
+	var num, acc, t fr.Element
+	acc.SetOne()
+	for i := range px {
+		t.Sub(&px[i], &ys[i]).Mul(&t, &acc)
+		num.Add(&num, &t)
+		acc.Mul(&acc, &gamma)
+	}
+	return DeepQuotientEvaluation(num, fr.Element{}, x, z)
+}
@@ -0,0 +1,81 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package poseidon2
+
+import (
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+)
+
+// Option defines option for altering the behavior of the Poseidon2 sponge.
+// See the descriptions of functions returning instances of this type for
+// particular options.
+type Option func(*spongeConfig)
+
+type spongeConfig struct {
+	rate            int
+	capacity        int
+	domainSeparator []byte
+	byteOrder       fr.ByteOrder
+}
+
+// default options
+func spongeOptions(opts ...Option) spongeConfig {
+	// apply options
+	opt := spongeConfig{
+		rate:      2,
+		capacity:  1,
+		byteOrder: fr.BigEndian,
+	}
+	for _, option := range opts {
+		option(&opt)
+	}
+	return opt
+}
+
+// WithRate sets the number of field elements absorbed per permutation call.
+// Default is 2.
+func WithRate(rate int) Option {
+	return func(opt *spongeConfig) {
+		opt.rate = rate
+	}
+}
+
+// WithCapacity sets the number of field elements of the state which are never
+// directly written nor output. Default is 1.
+func WithCapacity(capacity int) Option {
+	return func(opt *spongeConfig) {
+		opt.capacity = capacity
+	}
+}
+
+// WithDomainSeparation sets a tag which is hashed to a field element and used
+// to initialise the capacity, so that hashes computed for distinct use cases
+// are independent. Default is no tag (the capacity is initialised with zero).
+func WithDomainSeparation(tag []byte) Option {
+	return func(opt *spongeConfig) {
+		opt.domainSeparator = tag
+	}
+}
+
+// WithByteOrder sets the byte order used to decode the input
+// in the Write method and to encode the output in the Sum method.
+// Default is BigEndian.
+func WithByteOrder(byteOrder fr.ByteOrder) Option {
+	return func(opt *spongeConfig) {
+		opt.byteOrder = byteOrder
+	}
+}
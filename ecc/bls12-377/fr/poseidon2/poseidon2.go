@@ -56,10 +56,15 @@ type Hash struct {
 	params parameters
 }
 
-// NewHash returns a new hash instance allowing to apply the poseidon2 permutation
+// NewHash returns a new hash instance allowing to apply the poseidon2 permutation.
+// The width t must be 2, 3 or a multiple of 4. The round keys and, when t ≥ 4,
+// the internal matrix are derived from the seed.
 func NewHash(t, rf, rp int, seed string) Hash {
 	params := parameters{t: t, rF: rf, rP: rp}
 	params.roundKeys = InitRC(seed, rf, rp, t)
+	if t >= 4 {
+		params.diagInternalMatrices = initDiagInternalMatrix(seed, t)
+	}
 	res := Hash{params: params}
 	return res
 }
@@ -85,14 +90,14 @@ func InitRC(seed string, rf, rp, t int) [][]fr.Element {
 			_, _ = hash.Write(rnd)
 		}
 	}
-	for i := 0; i < rp; i++ {
+	for i := rf / 2; i < rf/2+rp; i++ {
 		roundKeys[i] = make([]fr.Element, 1)
 		rnd = hash.Sum(nil)
 		roundKeys[i][0].SetBytes(rnd)
 		hash.Reset()
 		_, _ = hash.Write(rnd)
 	}
-	for i := 0; i < rf/2; i++ {
+	for i := rf/2 + rp; i < rf+rp; i++ {
 		roundKeys[i] = make([]fr.Element, t)
 		for j := 0; j < t; j++ {
 			rnd = hash.Sum(nil)
@@ -180,9 +185,9 @@ func (h *Hash) matMulExternalInPlace(input []fr.Element) {
 		}
 		for i := 0; i < h.params.t/4; i++ {
 			input[4*i].Add(&input[4*i], &tmp[0])
-			input[4*i+1].Add(&input[4*i], &tmp[1])
-			input[4*i+2].Add(&input[4*i], &tmp[2])
-			input[4*i+3].Add(&input[4*i], &tmp[3])
+			input[4*i+1].Add(&input[4*i+1], &tmp[1])
+			input[4*i+2].Add(&input[4*i+2], &tmp[2])
+			input[4*i+3].Add(&input[4*i+3], &tmp[3])
 		}
 	}
 }
@@ -17,6 +17,8 @@
 package poseidon2
 
 import (
+	"bytes"
+	"encoding/hex"
 	"testing"
 
 	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
@@ -65,6 +67,265 @@ func TestExternalMatrix(t *testing.T) {
 
 }
 
+func TestExternalMatrixBlockCirculant(t *testing.T) {
+	// when t=0[4], the external matrix is circ(2M4,M4,..,M4): on the blocks of
+	// 4 elements, the block of the input contributes 2M4 and the others M4.
+	m4 := [4][4]uint64{
+		{5, 4, 1, 1},
+		{7, 6, 3, 1},
+		{1, 1, 5, 4},
+		{3, 1, 7, 6},
+	}
+	for _, width := range []int{8, 12} {
+		h := NewHash(width, 8, 57, "seed")
+		for col := 0; col < width; col++ {
+			input := make([]fr.Element, width)
+			input[col].SetOne()
+			h.matMulExternalInPlace(input)
+			for row := 0; row < width; row++ {
+				var expected fr.Element
+				expected.SetUint64(m4[col%4][row%4])
+				if row/4 == col/4 {
+					expected.Double(&expected)
+				}
+				if !input[row].Equal(&expected) {
+					t.Fatalf("width %d: wrong coefficient (%d, %d)", width, row, col)
+				}
+			}
+		}
+	}
+}
+
+func TestRoundKeys(t *testing.T) {
+	rf, rp, width := 8, 56, 3
+	roundKeys := InitRC("seed", rf, rp, width)
+	for i := range roundKeys {
+		expected := width
+		if i >= rf/2 && i < rf/2+rp {
+			expected = 1
+		}
+		if len(roundKeys[i]) != expected {
+			t.Fatalf("round %d: expected %d keys, got %d", i, expected, len(roundKeys[i]))
+		}
+	}
+}
+
+func TestSponge(t *testing.T) {
+	msg := make([]byte, 5*BlockSize)
+	for i := 0; i < 5; i++ {
+		var e fr.Element
+		e.SetRandom()
+		b := e.Bytes()
+		copy(msg[i*BlockSize:], b[:])
+	}
+
+	h := NewPoseidon2()
+	h.Write(msg)
+	expected := h.Sum(nil)
+
+	// Sum doesn't change the state
+	if !bytes.Equal(expected, h.Sum(nil)) {
+		t.Fatal("Sum modified the state")
+	}
+
+	// writing by chunks gives the same result
+	h.Reset()
+	h.Write(msg[:BlockSize])
+	h.Write(msg[BlockSize:])
+	if !bytes.Equal(expected, h.Sum(nil)) {
+		t.Fatal("hash depends on the way the message is written")
+	}
+
+	// the padding distinguishes messages of distinct lengths
+	h.Reset()
+	h.Write(msg)
+	h.Write(make([]byte, BlockSize))
+	if bytes.Equal(expected, h.Sum(nil)) {
+		t.Fatal("padding collision")
+	}
+
+	// domain separation
+	h = NewPoseidon2(WithDomainSeparation([]byte("tag")))
+	h.Write(msg)
+	if bytes.Equal(expected, h.Sum(nil)) {
+		t.Fatal("domain separation has no effect")
+	}
+
+	// rate 1
+	h = NewPoseidon2(WithRate(1), WithCapacity(1))
+	h.Write(msg)
+	if bytes.Equal(expected, h.Sum(nil)) {
+		t.Fatal("rate has no effect")
+	}
+}
+
+func TestCompress(t *testing.T) {
+	var a, b fr.Element
+	a.SetRandom()
+	b.SetRandom()
+
+	ab := Compress(&a, &b)
+	ba := Compress(&b, &a)
+	if ab.Equal(&ba) {
+		t.Fatal("compression should not be symmetric")
+	}
+
+	aBytes, bBytes := a.Bytes(), b.Bytes()
+	res, err := CompressBytes(aBytes[:], bBytes[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	abBytes := ab.Bytes()
+	if !bytes.Equal(res, abBytes[:]) {
+		t.Fatal("CompressBytes and Compress mismatch")
+	}
+}
+
+func TestSpongeWidths(t *testing.T) {
+	msg := make([]byte, 5*BlockSize)
+	for _, width := range []int{2, 3, 4, 8, 12} {
+		h := NewPoseidon2(WithRate(width-1), WithCapacity(1))
+		if _, err := h.Write(msg); err != nil {
+			t.Fatal(err)
+		}
+		if len(h.Sum(nil)) != BlockSize {
+			t.Fatal("wrong size of the digest")
+		}
+	}
+
+	for _, width := range []int{5, 28} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Fatalf("width %d should not be supported", width)
+				}
+			}()
+			NewPoseidon2(WithRate(width-1), WithCapacity(1))
+		}()
+	}
+}
+
+func TestVectors(t *testing.T) {
+	// regression vectors: the sponges hash the message (1, 2, 3, 4, 5) and the
+	// last one compresses (1, 2).
+	expected := []string{
+		"01e8ffc0f24c65d52140e5dad38d6af301bf8e465d1cae3e41a04adc9e6e464e",
+		"0af79f217c9bb73f9efac2f228d6e3d2ea8d11c1ef31614e43d9e68849208a86",
+		"0013483389f7bb583c56940ed2c118728387f9b9ed753cb8882e71d7e5165ca4",
+		"0209ccf0f5c0597d2b98a6e747125b9c7967e129f1205370bfc07e440fc708ec",
+		"0c0dcdb49c739b6ad8ab96c22b17e871248cfec27736e6c6e1b96693f002d6f3",
+	}
+
+	var msg []byte
+	for i := uint64(1); i <= 5; i++ {
+		var e fr.Element
+		e.SetUint64(i)
+		b := e.Bytes()
+		msg = append(msg, b[:]...)
+	}
+	sponges := [][]Option{
+		nil,
+		{WithDomainSeparation([]byte("tag"))},
+		{WithRate(3), WithCapacity(1)},
+		{WithRate(6), WithCapacity(2)},
+	}
+	for i, opts := range sponges {
+		h := NewPoseidon2(opts...)
+		h.Write(msg)
+		if hex.EncodeToString(h.Sum(nil)) != expected[i] {
+			t.Fatalf("sponge %d: wrong digest %x", i, h.Sum(nil))
+		}
+	}
+
+	var a, b fr.Element
+	a.SetUint64(1)
+	b.SetUint64(2)
+	res := Compress(&a, &b)
+	resBytes := res.Bytes()
+	if hex.EncodeToString(resBytes[:]) != expected[len(sponges)] {
+		t.Fatalf("wrong compression %x", resBytes)
+	}
+}
+
+func TestCharPoly(t *testing.T) {
+	// Cayley-Hamilton: p(A) = 0
+	const n = 5
+	a := make([][]fr.Element, n)
+	pow := make([][]fr.Element, n)
+	res := make([][]fr.Element, n)
+	for i := range a {
+		a[i] = make([]fr.Element, n)
+		for j := range a[i] {
+			a[i][j].SetRandom()
+		}
+		pow[i] = make([]fr.Element, n)
+		pow[i][i].SetOne()
+		res[i] = make([]fr.Element, n)
+	}
+
+	p := charPoly(a)
+	if len(p) != n+1 || !p[n].IsOne() {
+		t.Fatal("the characteristic polynomial should be monic of degree n")
+	}
+	var tmp fr.Element
+	for k := range p {
+		for i := range res {
+			for j := range res[i] {
+				tmp.Mul(&p[k], &pow[i][j])
+				res[i][j].Add(&res[i][j], &tmp)
+			}
+		}
+		pow = matMul(a, pow)
+	}
+	for i := range res {
+		for j := range res[i] {
+			if !res[i][j].IsZero() {
+				t.Fatal("p(A) != 0")
+			}
+		}
+	}
+}
+
+func TestIsIrreducible(t *testing.T) {
+	// x² - n is irreducible if and only if n is not a square
+	var n, s fr.Element
+	for n.Legendre() != -1 {
+		n.SetRandom()
+	}
+	s.SetRandom()
+	s.Square(&s)
+	var one, minusN, minusS fr.Element
+	one.SetOne()
+	minusN.Neg(&n)
+	minusS.Neg(&s)
+	f1 := []fr.Element{minusN, {}, one}
+	f2 := []fr.Element{minusS, {}, one}
+	if !isIrreducible(f1) {
+		t.Fatal("x² - n should be irreducible")
+	}
+	if isIrreducible(f2) {
+		t.Fatal("x² - s² should be reducible")
+	}
+
+	// (x² - n)(x² - ns²) has no root but is reducible
+	minusS.Mul(&minusN, &s)
+	f2 = []fr.Element{minusS, {}, one}
+	if !isIrreducible(f2) {
+		t.Fatal("x² - ns² should be irreducible")
+	}
+	x5 := make([]fr.Element, 6)
+	x5[5].SetOne()
+	f := polyMulMod(f1, f2, x5)
+	if isIrreducible(f) {
+		t.Fatal("(x² - n)(x² - ns²) should be reducible")
+	}
+
+	// the matrix filled with ones is singular
+	if isInternalMatrixSecure(make([]fr.Element, 4)) {
+		t.Fatal("a singular internal matrix should be rejected")
+	}
+}
+
 func BenchmarkPoseidon2(b *testing.B) {
 	h := NewHash(3, 8, 56, "seed")
 	var tmp [3]fr.Element
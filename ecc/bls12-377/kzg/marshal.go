@@ -119,6 +119,7 @@ func (srs *SRS) ReadDump(r io.Reader, maxPkPoints ...int) error {
 	}
 
 	// read the slice
+	srs.Pk.table, srs.Pk.tableG1 = nil, nil
 	srs.Pk.G1, _, err = unsafe.ReadSlice[[]bls12377.G1Affine](r, maxPkPoints...)
 	return err
 }
@@ -149,7 +150,8 @@ func (srs *SRS) WriteRawTo(w io.Writer) (int64, error) {
 
 // ReadFrom decodes ProvingKey data from reader.
 func (pk *ProvingKey) ReadFrom(r io.Reader) (int64, error) {
-	// decode the ProvingKey
+	// decode the ProvingKey, the table of Precompute is for the previous points
+	pk.table, pk.tableG1 = nil, nil
 	dec := bls12377.NewDecoder(r)
 	if err := dec.Decode(&pk.G1); err != nil {
 		return dec.BytesRead(), err
@@ -160,7 +162,8 @@ func (pk *ProvingKey) ReadFrom(r io.Reader) (int64, error) {
 // UnsafeReadFrom decodes ProvingKey data from reader without checking
 // that point are in the correct subgroup.
 func (pk *ProvingKey) UnsafeReadFrom(r io.Reader) (int64, error) {
-	// decode the ProvingKey
+	// decode the ProvingKey, the table of Precompute is for the previous points
+	pk.table, pk.tableG1 = nil, nil
 	dec := bls12377.NewDecoder(r, bls12377.NoSubgroupChecks())
 	if err := dec.Decode(&pk.G1); err != nil {
 		return dec.BytesRead(), err
@@ -0,0 +1,133 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package kzg
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-377"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+)
+
+func TestMpcSetup(t *testing.T) {
+	assert := require.New(t)
+
+	const size = 16
+
+	// a chain of contributions
+	setups := make([]*MpcSetup, 4)
+	var err error
+	setups[0], err = InitMpcSetup(size)
+	assert.NoError(err)
+	var tau fr.Element
+	tau.SetOne()
+	for i := 1; i < len(setups); i++ {
+		var x fr.Element
+		x.SetUint64(uint64(i + 41))
+		setups[i], err = setups[i-1].contribute(x)
+		assert.NoError(err)
+		tau.Mul(&tau, &x)
+	}
+	assert.NoError(VerifyMpcSetup(setups...))
+
+	// the final SRS is the SRS of τ = ∏ x
+	var bTau big.Int
+	tau.BigInt(&bTau)
+	expected, err := NewSRS(size, &bTau)
+	assert.NoError(err)
+	srs := setups[len(setups)-1].SRS()
+	for i := range expected.Pk.G1 {
+		assert.True(expected.Pk.G1[i].Equal(&srs.Pk.G1[i]), "wrong powers of τ")
+	}
+	assert.Equal(expected.Vk, srs.Vk)
+
+	// the SRS can be used to commit and open
+	p := randomPolynomial(size)
+	digest, err := Commit(p, srs.Pk)
+	assert.NoError(err)
+	var point fr.Element
+	point.SetRandom()
+	proof, err := Open(p, point, srs.Pk)
+	assert.NoError(err)
+	assert.NoError(Verify(&digest, &proof, point, srs.Vk))
+
+	// serialization
+	var buf bytes.Buffer
+	_, err = setups[2].WriteTo(&buf)
+	assert.NoError(err)
+	var decoded MpcSetup
+	_, err = decoded.ReadFrom(&buf)
+	assert.NoError(err)
+	assert.True(decoded.equal(setups[2]))
+	assert.NoError(VerifyMpcSetup(setups[0], setups[1], &decoded, setups[3]))
+
+	// a random contribution
+	last, err := setups[len(setups)-1].Contribute()
+	assert.NoError(err)
+	assert.NoError(VerifyMpcSetup(append(setups, last)...))
+}
+
+func TestMpcSetupInvalidContributions(t *testing.T) {
+	assert := require.New(t)
+
+	const size = 8
+	initial, err := InitMpcSetup(size)
+	assert.NoError(err)
+	var x fr.Element
+	x.SetUint64(42)
+	first, err := initial.contribute(x)
+	assert.NoError(err)
+	x.SetUint64(1789)
+	second, err := first.contribute(x)
+	assert.NoError(err)
+
+	// skipping a contribution invalidates the proof of knowledge
+	assert.ErrorIs(VerifyMpcSetup(initial, second), ErrMpcSetupProof)
+
+	// the chain should start with the initial setup
+	assert.Error(VerifyMpcSetup(first, second))
+
+	// replaying a proof with different powers
+	x.SetUint64(2)
+	other, err := first.contribute(x)
+	assert.NoError(err)
+	tampered := *other
+	tampered.Proof = second.Proof
+	assert.ErrorIs(VerifyMpcSetup(initial, first, &tampered), ErrMpcSetupUpdate)
+
+	// inconsistent powers of τ
+	tampered = *second
+	tampered.G1 = make([]bls12377.G1Affine, size)
+	copy(tampered.G1, second.G1)
+	tampered.G1[size-1] = tampered.G1[size-2]
+	assert.ErrorIs(VerifyMpcSetup(initial, first, &tampered), ErrMpcSetupPowers)
+
+	tampered = *second
+	tampered.G2[1] = first.G2[1]
+	assert.ErrorIs(VerifyMpcSetup(initial, first, &tampered), ErrMpcSetupPowers)
+
+	// the contributions should have the same size
+	smaller, err := InitMpcSetup(size / 2)
+	assert.NoError(err)
+	smaller, err = smaller.contribute(x)
+	assert.NoError(err)
+	assert.ErrorIs(VerifyMpcSetup(initial, smaller), ErrMpcSetupSize)
+}
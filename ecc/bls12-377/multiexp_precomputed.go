@@ -0,0 +1,359 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package bls12377
+
+import (
+	"errors"
+	"math"
+	"runtime"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	"github.com/consensys/gnark-crypto/internal/parallel"
+)
+
+// G1MultiExpTable stores shifted copies of the bases of multi-exponentiations,
+// which trade memory for faster MultiExpPrecomputed calls with the same bases.
+//
+// With windows of c bits and a copy every s windows, the table stores the
+// [2ᶜˢʲ]Pᵢ for 0 ⩽ j < ⌈nbChunks/s⌉. The windows s⋅j+k of all the copies share
+// the same buckets, so that a multi-exponentiation needs s bucket reductions
+// and (s-1)⋅c doublings instead of nbChunks of each.
+type G1MultiExpTable struct {
+	c        uint64     // window size
+	stride   int        // number of windows between two copies
+	nbCopies int        // number of copies of each base
+	points   []G1Affine // points[i⋅nbCopies+j] = [2ᶜˢʲ]Pᵢ
+}
+
+// NewG1MultiExpTable precomputes at most nbCopies shifted copies of each
+// of the points, for the multi-exponentiations of MultiExpPrecomputed. The
+// table takes up to nbCopies times the memory of the points; nbCopies = 1
+// doesn't store any shifted copy.
+func NewG1MultiExpTable(points []G1Affine, nbCopies int) (*G1MultiExpTable, error) {
+	if len(points) == 0 {
+		return nil, errors.New("no points to precompute")
+	}
+	if nbCopies < 1 {
+		return nil, errors.New("the number of copies should be at least 1")
+	}
+
+	// implemented msmC methods (the c we use must be in this slice)
+	implementedCs := []uint64{4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
+
+	// approximate cost (in group operations)
+	// cost = stride * (nbPoints * nbCopies + 2^{c})
+	t := &G1MultiExpTable{}
+	minCost := math.MaxFloat64
+	for _, c := range implementedCs {
+		nbChunks := int(computeNbChunks(c))
+		stride := (nbChunks + nbCopies - 1) / nbCopies
+		copies := (nbChunks + stride - 1) / stride
+		cost := float64(stride) * float64(len(points)*copies+(1<<c))
+		if cost < minCost {
+			minCost = cost
+			t.c, t.stride, t.nbCopies = c, stride, copies
+		}
+	}
+
+	// each copy is the previous one doubled c⋅stride times
+	t.points = make([]G1Affine, len(points)*t.nbCopies)
+	nbDoublings := int(t.c) * t.stride
+	parallel.Execute(len(points), func(start, end int) {
+		// convert the copies by blocks of points, with batch inversions
+		const blockSize = 256
+		copies := make([]G1Jac, blockSize*t.nbCopies)
+		for blockStart := start; blockStart < end; blockStart += blockSize {
+			blockEnd := blockStart + blockSize
+			if blockEnd > end {
+				blockEnd = end
+			}
+			block := copies[:(blockEnd-blockStart)*t.nbCopies]
+			for i := blockStart; i < blockEnd; i++ {
+				p := block[(i-blockStart)*t.nbCopies : (i-blockStart+1)*t.nbCopies]
+				p[0].FromAffine(&points[i])
+				for j := 1; j < len(p); j++ {
+					p[j].Set(&p[j-1])
+					for l := 0; l < nbDoublings; l++ {
+						p[j].DoubleAssign()
+					}
+				}
+			}
+			copy(t.points[blockStart*t.nbCopies:], BatchJacobianToAffineG1(block))
+		}
+	})
+
+	return t, nil
+}
+
+// NbPoints returns the number of bases of the table
+func (t *G1MultiExpTable) NbPoints() int {
+	return len(t.points) / t.nbCopies
+}
+
+// MultiExpPrecomputed computes ∑ᵢ[sᵢ]Pᵢ for the first len(scalars) bases Pᵢ of the table.
+//
+// This call return an error if len(scalars) > t.NbPoints() or if provided config is invalid.
+func (p *G1Affine) MultiExpPrecomputed(t *G1MultiExpTable, scalars []fr.Element, config ecc.MultiExpConfig) (*G1Affine, error) {
+	var _p G1Jac
+	if _, err := _p.MultiExpPrecomputed(t, scalars, config); err != nil {
+		return nil, err
+	}
+	p.FromJacobian(&_p)
+	return p, nil
+}
+
+// MultiExpPrecomputed computes ∑ᵢ[sᵢ]Pᵢ for the first len(scalars) bases Pᵢ of the table.
+//
+// This call return an error if len(scalars) > t.NbPoints() or if provided config is invalid.
+func (p *G1Jac) MultiExpPrecomputed(t *G1MultiExpTable, scalars []fr.Element, config ecc.MultiExpConfig) (*G1Jac, error) {
+	n := len(scalars)
+	if n > t.NbPoints() {
+		return nil, errors.New("len(scalars) > number of points of the table")
+	}
+
+	// if nbTasks is not set, use all available CPUs
+	if config.NbTasks <= 0 {
+		config.NbTasks = runtime.NumCPU() * 2
+	} else if config.NbTasks > 1024 {
+		return nil, errors.New("invalid config: config.NbTasks > 1024")
+	}
+
+	digits, _ := partitionScalars(scalars, t.c, config.NbTasks)
+	nbChunks := int(computeNbChunks(t.c))
+	points := t.points[:n*t.nbCopies]
+
+	// each group of windows is split in nbSplits tasks, as long as a task has
+	// more points than buckets
+	nbSplits := (config.NbTasks + t.stride - 1) / t.stride
+	if maxSplits := len(points) >> t.c; nbSplits > maxSplits {
+		nbSplits = maxSplits
+	}
+	if nbSplits < 1 {
+		nbSplits = 1
+	}
+	splitSize := (len(points) + nbSplits - 1) / nbSplits
+
+	// the group k gathers the windows s⋅j+k of the scalars, in the order of the
+	// copies of the table; its weighted bucket sum is sent in chGroups[k]
+	chGroups := make([]chan g1JacExtended, t.stride)
+	for k := range chGroups {
+		chGroups[k] = make(chan g1JacExtended, 1)
+		go func(k int) {
+			groupDigits := make([]uint16, len(points))
+			for i := 0; i < n; i++ {
+				for j := 0; j < t.nbCopies; j++ {
+					if w := j*t.stride + k; w < nbChunks {
+						groupDigits[i*t.nbCopies+j] = digits[w*n+i]
+					}
+				}
+			}
+
+			// the last window may need more buckets
+			c := t.c
+			if k == (nbChunks-1)%t.stride && lastC(t.c) > c {
+				c = lastC(t.c)
+			}
+			stat := chunkStat{nbBucketFilled: splitSize}
+			if stat.nbBucketFilled > 1<<(c-1) {
+				stat.nbBucketFilled = 1 << (c - 1)
+			}
+			processChunk := getChunkProcessorG1(c, stat)
+
+			chSplit := make(chan g1JacExtended, nbSplits)
+			nbTasks := 0
+			for start := 0; start < len(points); start += splitSize {
+				end := start + splitSize
+				if end > len(points) {
+					end = len(points)
+				}
+				go processChunk(uint64(k), chSplit, t.c, points[start:end], groupDigits[start:end], nil)
+				nbTasks++
+			}
+			var total g1JacExtended
+			total.SetInfinity()
+			for ; nbTasks > 0; nbTasks-- {
+				s := <-chSplit
+				total.add(&s)
+			}
+			chGroups[k] <- total
+		}(k)
+	}
+
+	return msmReduceChunkG1Affine(p, int(t.c), chGroups), nil
+}
+
+// G2MultiExpTable stores shifted copies of the bases of multi-exponentiations,
+// which trade memory for faster MultiExpPrecomputed calls with the same bases.
+//
+// With windows of c bits and a copy every s windows, the table stores the
+// [2ᶜˢʲ]Pᵢ for 0 ⩽ j < ⌈nbChunks/s⌉. The windows s⋅j+k of all the copies share
+// the same buckets, so that a multi-exponentiation needs s bucket reductions
+// and (s-1)⋅c doublings instead of nbChunks of each.
+type G2MultiExpTable struct {
+	c        uint64     // window size
+	stride   int        // number of windows between two copies
+	nbCopies int        // number of copies of each base
+	points   []G2Affine // points[i⋅nbCopies+j] = [2ᶜˢʲ]Pᵢ
+}
+
+// NewG2MultiExpTable precomputes at most nbCopies shifted copies of each
+// of the points, for the multi-exponentiations of MultiExpPrecomputed. The
+// table takes up to nbCopies times the memory of the points; nbCopies = 1
+// doesn't store any shifted copy.
+func NewG2MultiExpTable(points []G2Affine, nbCopies int) (*G2MultiExpTable, error) {
+	if len(points) == 0 {
+		return nil, errors.New("no points to precompute")
+	}
+	if nbCopies < 1 {
+		return nil, errors.New("the number of copies should be at least 1")
+	}
+
+	// implemented msmC methods (the c we use must be in this slice)
+	implementedCs := []uint64{4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
+
+	// approximate cost (in group operations)
+	// cost = stride * (nbPoints * nbCopies + 2^{c})
+	t := &G2MultiExpTable{}
+	minCost := math.MaxFloat64
+	for _, c := range implementedCs {
+		nbChunks := int(computeNbChunks(c))
+		stride := (nbChunks + nbCopies - 1) / nbCopies
+		copies := (nbChunks + stride - 1) / stride
+		cost := float64(stride) * float64(len(points)*copies+(1<<c))
+		if cost < minCost {
+			minCost = cost
+			t.c, t.stride, t.nbCopies = c, stride, copies
+		}
+	}
+
+	// each copy is the previous one doubled c⋅stride times
+	t.points = make([]G2Affine, len(points)*t.nbCopies)
+	nbDoublings := int(t.c) * t.stride
+	parallel.Execute(len(points), func(start, end int) {
+		var p G2Jac
+		for i := start; i < end; i++ {
+			t.points[i*t.nbCopies] = points[i]
+			p.FromAffine(&points[i])
+			for j := 1; j < t.nbCopies; j++ {
+				for l := 0; l < nbDoublings; l++ {
+					p.DoubleAssign()
+				}
+				t.points[i*t.nbCopies+j].FromJacobian(&p)
+			}
+		}
+	})
+
+	return t, nil
+}
+
+// NbPoints returns the number of bases of the table
+func (t *G2MultiExpTable) NbPoints() int {
+	return len(t.points) / t.nbCopies
+}
+
+// MultiExpPrecomputed computes ∑ᵢ[sᵢ]Pᵢ for the first len(scalars) bases Pᵢ of the table.
+//
+// This call return an error if len(scalars) > t.NbPoints() or if provided config is invalid.
+func (p *G2Affine) MultiExpPrecomputed(t *G2MultiExpTable, scalars []fr.Element, config ecc.MultiExpConfig) (*G2Affine, error) {
+	var _p G2Jac
+	if _, err := _p.MultiExpPrecomputed(t, scalars, config); err != nil {
+		return nil, err
+	}
+	p.FromJacobian(&_p)
+	return p, nil
+}
+
+// MultiExpPrecomputed computes ∑ᵢ[sᵢ]Pᵢ for the first len(scalars) bases Pᵢ of the table.
+//
+// This call return an error if len(scalars) > t.NbPoints() or if provided config is invalid.
+func (p *G2Jac) MultiExpPrecomputed(t *G2MultiExpTable, scalars []fr.Element, config ecc.MultiExpConfig) (*G2Jac, error) {
+	n := len(scalars)
+	if n > t.NbPoints() {
+		return nil, errors.New("len(scalars) > number of points of the table")
+	}
+
+	// if nbTasks is not set, use all available CPUs
+	if config.NbTasks <= 0 {
+		config.NbTasks = runtime.NumCPU() * 2
+	} else if config.NbTasks > 1024 {
+		return nil, errors.New("invalid config: config.NbTasks > 1024")
+	}
+
+	digits, _ := partitionScalars(scalars, t.c, config.NbTasks)
+	nbChunks := int(computeNbChunks(t.c))
+	points := t.points[:n*t.nbCopies]
+
+	// each group of windows is split in nbSplits tasks, as long as a task has
+	// more points than buckets
+	nbSplits := (config.NbTasks + t.stride - 1) / t.stride
+	if maxSplits := len(points) >> t.c; nbSplits > maxSplits {
+		nbSplits = maxSplits
+	}
+	if nbSplits < 1 {
+		nbSplits = 1
+	}
+	splitSize := (len(points) + nbSplits - 1) / nbSplits
+
+	// the group k gathers the windows s⋅j+k of the scalars, in the order of the
+	// copies of the table; its weighted bucket sum is sent in chGroups[k]
+	chGroups := make([]chan g2JacExtended, t.stride)
+	for k := range chGroups {
+		chGroups[k] = make(chan g2JacExtended, 1)
+		go func(k int) {
+			groupDigits := make([]uint16, len(points))
+			for i := 0; i < n; i++ {
+				for j := 0; j < t.nbCopies; j++ {
+					if w := j*t.stride + k; w < nbChunks {
+						groupDigits[i*t.nbCopies+j] = digits[w*n+i]
+					}
+				}
+			}
+
+			// the last window may need more buckets
+			c := t.c
+			if k == (nbChunks-1)%t.stride && lastC(t.c) > c {
+				c = lastC(t.c)
+			}
+			stat := chunkStat{nbBucketFilled: splitSize}
+			if stat.nbBucketFilled > 1<<(c-1) {
+				stat.nbBucketFilled = 1 << (c - 1)
+			}
+			processChunk := getChunkProcessorG2(c, stat)
+
+			chSplit := make(chan g2JacExtended, nbSplits)
+			nbTasks := 0
+			for start := 0; start < len(points); start += splitSize {
+				end := start + splitSize
+				if end > len(points) {
+					end = len(points)
+				}
+				go processChunk(uint64(k), chSplit, t.c, points[start:end], groupDigits[start:end], nil)
+				nbTasks++
+			}
+			var total g2JacExtended
+			total.SetInfinity()
+			for ; nbTasks > 0; nbTasks-- {
+				s := <-chSplit
+				total.add(&s)
+			}
+			chGroups[k] <- total
+		}(k)
+	}
+
+	return msmReduceChunkG2Affine(p, int(t.c), chGroups), nil
+}
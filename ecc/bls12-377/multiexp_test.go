@@ -309,6 +309,53 @@ func _innerMsmG1Reference(p *G1Jac, points []G1Affine, scalars []fr.Element, con
 	return msmReduceChunkG1Affine(p, int(16), chChunks[:])
 }
 
+func TestMultiExpBatchG1(t *testing.T) {
+	const nbSamples = 200
+
+	samplePoints := make([]G1Affine, nbSamples)
+	var g G1Jac
+	g.Set(&g1Gen)
+	for i := range samplePoints {
+		samplePoints[i].FromJacobian(&g)
+		g.AddAssign(&g1Gen)
+	}
+	samplePoints[rand.N(nbSamples)].SetInfinity() //#nosec G404 weak rng is fine here
+
+	// vectors of scalars of different sizes, one of them empty
+	sizes := []int{nbSamples, 1, 0, 57, nbSamples, 130}
+	scalars := make([][]fr.Element, len(sizes))
+	for i := range scalars {
+		scalars[i] = make([]fr.Element, sizes[i])
+		fillBenchScalars(scalars[i])
+	}
+	scalars[4][3].SetZero()
+
+	for _, nbTasks := range []int{0, 1, 5} {
+		res, err := MultiExpBatchG1(samplePoints, scalars, ecc.MultiExpConfig{NbTasks: nbTasks})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(res) != len(scalars) {
+			t.Fatal("wrong number of results")
+		}
+		for i := range scalars {
+			var expected G1Affine
+			if sizes[i] != 0 {
+				if _, err := expected.MultiExp(samplePoints[:sizes[i]], scalars[i], ecc.MultiExpConfig{}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if !res[i].Equal(&expected) {
+				t.Fatalf("batch msm %d failed with nbTasks=%d", i, nbTasks)
+			}
+		}
+	}
+
+	if _, err := MultiExpBatchG1(samplePoints[:10], scalars, ecc.MultiExpConfig{}); err == nil {
+		t.Fatal("vectors of scalars longer than the points should be rejected")
+	}
+}
+
 func BenchmarkMultiExpG1(b *testing.B) {
 
 	const (
@@ -431,6 +478,35 @@ func BenchmarkManyMultiExpG1Reference(b *testing.B) {
 // Rationale for generating points that are not on the curve is that for large benchmarks, generating
 // a vector of different points can take minutes. Using the same point or subset will bias the benchmark result
 // since bucket additions in extended jacobian coordinates will hit doubling algorithm instead of add.
+func BenchmarkMultiExpBatchG1(b *testing.B) {
+	const (
+		nbSamples = 1 << 14
+		nbVectors = 8
+	)
+
+	samplePoints := make([]G1Affine, nbSamples)
+	fillBenchBasesG1(samplePoints)
+	scalars := make([][]fr.Element, nbVectors)
+	for i := range scalars {
+		scalars[i] = make([]fr.Element, nbSamples)
+		fillBenchScalars(scalars[i])
+	}
+
+	b.Run("sequential", func(b *testing.B) {
+		var testPoint G1Affine
+		for j := 0; j < b.N; j++ {
+			for i := range scalars {
+				testPoint.MultiExp(samplePoints, scalars[i], ecc.MultiExpConfig{})
+			}
+		}
+	})
+	b.Run("batch", func(b *testing.B) {
+		for j := 0; j < b.N; j++ {
+			MultiExpBatchG1(samplePoints, scalars, ecc.MultiExpConfig{})
+		}
+	})
+}
+
 func fillBenchBasesG1(samplePoints []G1Affine) {
 	var r big.Int
 	r.SetString("340444420969191673093399857471996460938405", 10)
@@ -719,6 +795,53 @@ func _innerMsmG2Reference(p *G2Jac, points []G2Affine, scalars []fr.Element, con
 	return msmReduceChunkG2Affine(p, int(16), chChunks[:])
 }
 
+func TestMultiExpBatchG2(t *testing.T) {
+	const nbSamples = 200
+
+	samplePoints := make([]G2Affine, nbSamples)
+	var g G2Jac
+	g.Set(&g2Gen)
+	for i := range samplePoints {
+		samplePoints[i].FromJacobian(&g)
+		g.AddAssign(&g2Gen)
+	}
+	samplePoints[rand.N(nbSamples)].SetInfinity() //#nosec G404 weak rng is fine here
+
+	// vectors of scalars of different sizes, one of them empty
+	sizes := []int{nbSamples, 1, 0, 57, nbSamples, 130}
+	scalars := make([][]fr.Element, len(sizes))
+	for i := range scalars {
+		scalars[i] = make([]fr.Element, sizes[i])
+		fillBenchScalars(scalars[i])
+	}
+	scalars[4][3].SetZero()
+
+	for _, nbTasks := range []int{0, 1, 5} {
+		res, err := MultiExpBatchG2(samplePoints, scalars, ecc.MultiExpConfig{NbTasks: nbTasks})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(res) != len(scalars) {
+			t.Fatal("wrong number of results")
+		}
+		for i := range scalars {
+			var expected G2Affine
+			if sizes[i] != 0 {
+				if _, err := expected.MultiExp(samplePoints[:sizes[i]], scalars[i], ecc.MultiExpConfig{}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if !res[i].Equal(&expected) {
+				t.Fatalf("batch msm %d failed with nbTasks=%d", i, nbTasks)
+			}
+		}
+	}
+
+	if _, err := MultiExpBatchG2(samplePoints[:10], scalars, ecc.MultiExpConfig{}); err == nil {
+		t.Fatal("vectors of scalars longer than the points should be rejected")
+	}
+}
+
 func BenchmarkMultiExpG2(b *testing.B) {
 
 	const (
@@ -841,6 +964,35 @@ func BenchmarkManyMultiExpG2Reference(b *testing.B) {
 // Rationale for generating points that are not on the curve is that for large benchmarks, generating
 // a vector of different points can take minutes. Using the same point or subset will bias the benchmark result
 // since bucket additions in extended jacobian coordinates will hit doubling algorithm instead of add.
+func BenchmarkMultiExpBatchG2(b *testing.B) {
+	const (
+		nbSamples = 1 << 14
+		nbVectors = 8
+	)
+
+	samplePoints := make([]G2Affine, nbSamples)
+	fillBenchBasesG2(samplePoints)
+	scalars := make([][]fr.Element, nbVectors)
+	for i := range scalars {
+		scalars[i] = make([]fr.Element, nbSamples)
+		fillBenchScalars(scalars[i])
+	}
+
+	b.Run("sequential", func(b *testing.B) {
+		var testPoint G2Affine
+		for j := 0; j < b.N; j++ {
+			for i := range scalars {
+				testPoint.MultiExp(samplePoints, scalars[i], ecc.MultiExpConfig{})
+			}
+		}
+	})
+	b.Run("batch", func(b *testing.B) {
+		for j := 0; j < b.N; j++ {
+			MultiExpBatchG2(samplePoints, scalars, ecc.MultiExpConfig{})
+		}
+	})
+}
+
 func fillBenchBasesG2(samplePoints []G2Affine) {
 	var r big.Int
 	r.SetString("340444420969191673093399857471996460938405", 10)
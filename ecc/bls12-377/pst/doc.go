@@ -0,0 +1,24 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+// Package pst provides a commitment scheme for multilinear polynomials,
+// following Papamanthou, Shi and Tamassia, "Signatures of Correct Computation",
+// cf https://eprint.iacr.org/2011/587.pdf.
+//
+// The polynomials are given by their evaluations on the boolean hypercube, as
+// polynomial.MultiLin, and opened at points of 𝔽ᵣⁿ, for instance to turn the
+// sumcheck or GKR protocols into succinct arguments.
+package pst
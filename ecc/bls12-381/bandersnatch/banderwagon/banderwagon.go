@@ -0,0 +1,258 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package banderwagon provides the banderwagon group, the prime order quotient
+// of bandersnatch used by the Ethereum Verkle trees, cf
+// https://hackmd.io/@6iQDuIePQjyYBqDChYw_jg/BJ2-L6Nzc.
+//
+// The points of bandersnatch of the form 2P, which have order r or 2r, are
+// taken modulo the point (0, -1) of order 2, so that (x, y) and (-x, -y) are
+// the same element of banderwagon. This removes the cofactor without any
+// subgroup check, and gives a canonical 32 bytes encoding of the elements.
+package banderwagon
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch/fr"
+	fp "github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/consensys/gnark-crypto/internal/parallel"
+)
+
+// Bytes number of bytes of the encoding of an element
+const Bytes = fp.Bytes
+
+var (
+	ErrInvalidEncoding = errors.New("invalid encoding of a banderwagon element")
+	ErrNotInSubgroup   = errors.New("the point is not in the banderwagon subgroup")
+)
+
+// Element of the banderwagon group, represented by any of the two points of
+// bandersnatch (x, y) and (-x, -y) of its class.
+type Element struct {
+	inner bandersnatch.PointExtended
+}
+
+// Identity returns the neutral element of the group
+func Identity() Element {
+	var res Element
+	res.SetIdentity()
+	return res
+}
+
+// Generator returns the generator of the group, which is the class of the
+// base point of bandersnatch.
+func Generator() Element {
+	base := bandersnatch.GetEdwardsCurve().Base
+	var res Element
+	res.inner.FromAffine(&base)
+	return res
+}
+
+// SetIdentity sets z to the neutral element of the group and returns z
+func (z *Element) SetIdentity() *Element {
+	var identity bandersnatch.PointAffine
+	identity.Y.SetOne()
+	z.inner.FromAffine(&identity)
+	return z
+}
+
+// Set sets z to x and returns z
+func (z *Element) Set(x *Element) *Element {
+	z.inner.Set(&x.inner)
+	return z
+}
+
+// Add sets z to x+y and returns z
+func (z *Element) Add(x, y *Element) *Element {
+	z.inner.Add(&x.inner, &y.inner)
+	return z
+}
+
+// Sub sets z to x-y and returns z
+func (z *Element) Sub(x, y *Element) *Element {
+	var neg bandersnatch.PointExtended
+	neg.Neg(&y.inner)
+	z.inner.Add(&x.inner, &neg)
+	return z
+}
+
+// Neg sets z to -x and returns z
+func (z *Element) Neg(x *Element) *Element {
+	z.inner.Neg(&x.inner)
+	return z
+}
+
+// Double sets z to 2x and returns z
+func (z *Element) Double(x *Element) *Element {
+	z.inner.Double(&x.inner)
+	return z
+}
+
+// ScalarMultiplication sets z to [s]x and returns z
+//
+// The endomorphism of bandersnatch maps (0, -1) to a point of order at most 2,
+// so that the GLV method is correct modulo (0, -1) for both representatives,
+// except for (0, -1) itself where the endomorphism isn't defined.
+func (z *Element) ScalarMultiplication(x *Element, s *fr.Element) *Element {
+	if x.IsIdentity() {
+		return z.SetIdentity()
+	}
+	var bs big.Int
+	s.BigInt(&bs)
+	z.inner.ScalarMultiplication(&x.inner, &bs)
+	return z
+}
+
+// Equal returns true if z and x are the same element of the group, that is if
+// x₁y₂ = x₂y₁ for their representatives. The degenerate points with y = 0 or
+// z = 0, such as the zero value of Element, aren't equal to any element.
+func (z *Element) Equal(x *Element) bool {
+	if z.isDegenerate() || x.isDegenerate() {
+		return false
+	}
+	var l, r fp.Element
+	l.Mul(&z.inner.X, &x.inner.Y)
+	r.Mul(&x.inner.X, &z.inner.Y)
+	return l.Equal(&r)
+}
+
+// IsIdentity returns true if z is the neutral element of the group, (0, 1) or
+// (0, -1).
+func (z *Element) IsIdentity() bool {
+	return z.inner.X.IsZero() && !z.isDegenerate()
+}
+
+// isDegenerate returns true if the y or z coordinate of the representative of
+// z is 0. The points of banderwagon have y ≠ 0, and x = y = 0 would make any
+// element equal to z.
+func (z *Element) isDegenerate() bool {
+	return z.inner.Y.IsZero() || z.inner.Z.IsZero()
+}
+
+// Bytes returns the encoding of z: the big-endian x coordinate of the
+// representative whose y coordinate is lexicographically largest.
+func (z *Element) Bytes() [Bytes]byte {
+	var p bandersnatch.PointAffine
+	p.FromExtended(&z.inner)
+	if !p.Y.LexicographicallyLargest() {
+		p.X.Neg(&p.X)
+	}
+	return p.X.Bytes()
+}
+
+// SetBytes sets z from its encoding, checking that it is canonical and that
+// it is the encoding of an element of the group.
+func (z *Element) SetBytes(buf []byte) error {
+	if len(buf) != Bytes {
+		return ErrInvalidEncoding
+	}
+	x, err := fp.BigEndian.Element((*[Bytes]byte)(buf))
+	if err != nil {
+		return ErrInvalidEncoding
+	}
+
+	// (x, y) is of the form 2P iff 1 - ax² is a square
+	curve := bandersnatch.GetEdwardsCurve()
+	var num, den, one fp.Element
+	one.SetOne()
+	num.Square(&x).Mul(&num, &curve.A).Sub(&one, &num)
+	if num.Legendre() != 1 {
+		return ErrNotInSubgroup
+	}
+
+	// y² = (1 - ax²) / (1 - dx²)
+	den.Square(&x).Mul(&den, &curve.D).Sub(&one, &den)
+	if den.IsZero() {
+		return ErrInvalidEncoding
+	}
+	var p bandersnatch.PointAffine
+	p.X = x
+	p.Y.Div(&num, &den)
+	if p.Y.Sqrt(&p.Y) == nil {
+		return ErrInvalidEncoding
+	}
+	if !p.Y.LexicographicallyLargest() {
+		p.Y.Neg(&p.Y)
+	}
+	z.inner.FromAffine(&p)
+	return nil
+}
+
+// MapToScalarField returns x/y, which doesn't depend on the representative,
+// as an element of the scalar field.
+func (z *Element) MapToScalarField() fr.Element {
+	var q fp.Element
+	q.Div(&z.inner.X, &z.inner.Y)
+	return baseToScalar(&q)
+}
+
+// BatchMapToScalarField sets res[i] to elements[i].MapToScalarField(), with a
+// single inversion.
+func BatchMapToScalarField(res []fr.Element, elements []Element) {
+	if len(res) != len(elements) {
+		panic("len(res) != len(elements)")
+	}
+	y := make([]fp.Element, len(elements))
+	for i := range elements {
+		y[i] = elements[i].inner.Y
+	}
+	yInv := fp.BatchInvert(y)
+	for i := range elements {
+		var q fp.Element
+		q.Mul(&elements[i].inner.X, &yInv[i])
+		res[i] = baseToScalar(&q)
+	}
+}
+
+// baseToScalar reduces an element of the base field modulo the order of the
+// group.
+func baseToScalar(x *fp.Element) fr.Element {
+	b := x.Bytes()
+	var res fr.Element
+	res.SetBytes(b[:])
+	return res
+}
+
+// MultiExp returns ∑ᵢ[sᵢ]Pᵢ
+func MultiExp(points []Element, scalars []fr.Element) (Element, error) {
+	if len(points) != len(scalars) {
+		return Element{}, errors.New("len(points) != len(scalars)")
+	}
+
+	// normalize the points with one inversion
+	z := make([]fp.Element, len(points))
+	for i := range points {
+		z[i] = points[i].inner.Z
+	}
+	zInv := fp.BatchInvert(z)
+	affine := make([]bandersnatch.PointAffine, len(points))
+	bScalars := make([]big.Int, len(scalars))
+	parallel.Execute(len(points), func(start, end int) {
+		for i := start; i < end; i++ {
+			affine[i].X.Mul(&points[i].inner.X, &zInv[i])
+			affine[i].Y.Mul(&points[i].inner.Y, &zInv[i])
+			scalars[i].BigInt(&bScalars[i])
+		}
+	})
+
+	var res Element
+	if _, err := res.inner.MultiExp(affine, bScalars, ecc.MultiExpConfig{}); err != nil {
+		return Element{}, err
+	}
+	return res, nil
+}
@@ -0,0 +1,212 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package banderwagon
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch/fr"
+	fp "github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/stretchr/testify/require"
+)
+
+func randomElement() Element {
+	var s fr.Element
+	s.SetRandom()
+	g := Generator()
+	var res Element
+	res.ScalarMultiplication(&g, &s)
+	return res
+}
+
+// otherRepresentative returns the point (-x, -y) of the class of e
+func otherRepresentative(e Element) Element {
+	var res Element
+	res.inner.X.Neg(&e.inner.X)
+	res.inner.Y.Neg(&e.inner.Y)
+	res.inner.Z = e.inner.Z
+	res.inner.T = e.inner.T
+	return res
+}
+
+func TestEncodingGenerator(t *testing.T) {
+	g := Generator()
+	b := g.Bytes()
+	require.Equal(t, "4a2c7486fd924882bf02c6908de395122843e3e05264d7991e18e7985dad51e9", hex.EncodeToString(b[:]))
+}
+
+func TestEncoding(t *testing.T) {
+	assert := require.New(t)
+
+	identity := Identity()
+	b := identity.Bytes()
+	assert.Equal([Bytes]byte{}, b)
+
+	for i := 0; i < 20; i++ {
+		e := randomElement()
+		b := e.Bytes()
+		var d Element
+		assert.NoError(d.SetBytes(b[:]))
+		assert.True(d.Equal(&e))
+
+		// both representatives have the same encoding
+		other := otherRepresentative(e)
+		assert.True(other.Equal(&e))
+		assert.Equal(b, other.Bytes())
+	}
+
+	// non canonical encoding
+	var d Element
+	assert.ErrorIs(d.SetBytes(fp.Modulus().FillBytes(make([]byte, Bytes))), ErrInvalidEncoding)
+	assert.ErrorIs(d.SetBytes(b[1:]), ErrInvalidEncoding)
+
+	// points which aren't of the form 2P
+	var x fp.Element
+	found := false
+	for i := uint64(1); i < 100; i++ {
+		x.SetUint64(i)
+		b := x.Bytes()
+		if err := d.SetBytes(b[:]); err == ErrNotInSubgroup {
+			found = true
+			break
+		}
+	}
+	assert.True(found)
+}
+
+func TestGroupLaw(t *testing.T) {
+	assert := require.New(t)
+
+	a, b := randomElement(), randomElement()
+	otherA := otherRepresentative(a)
+
+	var s1, s2, d Element
+	s1.Add(&a, &b)
+	s2.Add(&otherA, &b)
+	assert.True(s1.Equal(&s2))
+	d.Sub(&s1, &b)
+	assert.True(d.Equal(&a))
+	d.Sub(&s2, &a)
+	assert.True(d.Equal(&b))
+
+	// the identity represented by (0, -1)
+	d.Sub(&otherA, &a)
+	assert.True(d.IsIdentity())
+
+	// scalar multiplication on both representatives
+	var s fr.Element
+	s.SetRandom()
+	var m1, m2, m3 Element
+	m1.ScalarMultiplication(&a, &s)
+	m2.ScalarMultiplication(&otherA, &s)
+	assert.True(m1.Equal(&m2))
+	m3.ScalarMultiplication(&d, &s)
+	assert.True(m3.IsIdentity())
+
+	// [2]a
+	s.SetUint64(2)
+	m1.ScalarMultiplication(&a, &s)
+	m2.Double(&otherA)
+	assert.True(m1.Equal(&m2))
+	m3.Add(&a, &a)
+	assert.True(m1.Equal(&m3))
+}
+
+func TestDegenerate(t *testing.T) {
+	assert := require.New(t)
+
+	// (0:0:0:0) isn't the identity, nor equal to any element
+	var zero Element
+	identity, g := Identity(), Generator()
+	assert.False(zero.IsIdentity())
+	assert.False(zero.Equal(&identity))
+	assert.False(identity.Equal(&zero))
+	assert.False(zero.Equal(&g))
+	assert.False(zero.Equal(&zero))
+
+	// nor (0:0:1:0)
+	var degenerate Element
+	degenerate.inner.Z.SetOne()
+	assert.False(degenerate.IsIdentity())
+	assert.False(degenerate.Equal(&g))
+	assert.False(g.Equal(&degenerate))
+}
+
+func TestMapToScalarField(t *testing.T) {
+	assert := require.New(t)
+
+	elements := make([]Element, 10)
+	for i := range elements {
+		elements[i] = randomElement()
+	}
+	elements[3] = otherRepresentative(elements[2])
+	elements[5] = Identity()
+
+	res := make([]fr.Element, len(elements))
+	BatchMapToScalarField(res, elements)
+	for i := range elements {
+		expected := elements[i].MapToScalarField()
+		assert.True(expected.Equal(&res[i]))
+	}
+	assert.True(res[2].Equal(&res[3]))
+	assert.True(res[5].IsZero())
+}
+
+func TestMultiExp(t *testing.T) {
+	assert := require.New(t)
+
+	const n = 37
+	points := make([]Element, n)
+	scalars := make([]fr.Element, n)
+	expected := Identity()
+	for i := range points {
+		points[i] = randomElement()
+		if i%3 == 0 {
+			points[i] = otherRepresentative(points[i])
+		}
+		scalars[i].SetRandom()
+		var tmp Element
+		tmp.ScalarMultiplication(&points[i], &scalars[i])
+		expected.Add(&expected, &tmp)
+	}
+	res, err := MultiExp(points, scalars)
+	assert.NoError(err)
+	assert.True(res.Equal(&expected))
+
+	_, err = MultiExp(points[1:], scalars)
+	assert.Error(err)
+
+	// both representatives of the identity, (0, 1) and (0, -1), the latter
+	// being the decoding of 32 zero bytes
+	var zero Element
+	assert.NoError(zero.SetBytes(make([]byte, Bytes)))
+	identities := []Element{Identity(), zero}
+	for i := range identities {
+		assert.True(identities[i].IsIdentity())
+		points[i] = identities[i]
+		points[i+len(identities)] = otherRepresentative(identities[i])
+	}
+	expected = Identity()
+	for i := range points {
+		var tmp Element
+		tmp.ScalarMultiplication(&points[i], &scalars[i])
+		expected.Add(&expected, &tmp)
+	}
+	res, err = MultiExp(points, scalars)
+	assert.NoError(err)
+	assert.True(res.Equal(&expected), "the identity should be neutral in MultiExp")
+	assert.False(res.IsIdentity())
+}
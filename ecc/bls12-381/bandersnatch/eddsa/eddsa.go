@@ -208,25 +208,11 @@ func (pub *PublicKey) Verify(sigBin, message []byte, hFunc hash.Hash) (bool, err
 	}
 
 	// compute H(R, A, M), all parameters in data are in Montgomery form
-
-	hFunc.Reset()
-
-	sigRX := sig.R.X.Bytes()
-	sigRY := sig.R.Y.Bytes()
-	sigAX := pub.A.X.Bytes()
-	sigAY := pub.A.Y.Bytes()
-
-	toWrite := [][]byte{sigRX[:], sigRY[:], sigAX[:], sigAY[:], message}
-	for _, bytes := range toWrite {
-		if _, err := hFunc.Write(bytes); err != nil {
-			return false, err
-		}
+	hramInt, err := computeHRAM(hFunc, &sig.R, &pub.A, message)
+	if err != nil {
+		return false, err
 	}
 
-	var hramInt big.Int
-	hramBin := hFunc.Sum(nil)
-	hramInt.SetBytes(hramBin)
-
 	// lhs = cofactor*S*Base
 	var lhs twistededwards.PointAffine
 	var bCofactor, bs big.Int
@@ -241,7 +227,7 @@ func (pub *PublicKey) Verify(sigBin, message []byte, hFunc hash.Hash) (bool, err
 
 	// rhs = cofactor*(R + H(R,A,M)*A)
 	var rhs twistededwards.PointAffine
-	rhs.ScalarMultiplication(&pub.A, &hramInt).
+	rhs.ScalarMultiplication(&pub.A, hramInt).
 		Add(&rhs, &sig.R).
 		ScalarMultiplication(&rhs, &bCofactor)
 	if !rhs.IsOnCurve() {
@@ -255,3 +241,23 @@ func (pub *PublicKey) Verify(sigBin, message []byte, hFunc hash.Hash) (bool, err
 
 	return true, nil
 }
+
+// computeHRAM returns H(R, A, M) as an integer
+func computeHRAM(hFunc hash.Hash, R, A *twistededwards.PointAffine, message []byte) (*big.Int, error) {
+	hFunc.Reset()
+
+	RX := R.X.Bytes()
+	RY := R.Y.Bytes()
+	AX := A.X.Bytes()
+	AY := A.Y.Bytes()
+
+	toWrite := [][]byte{RX[:], RY[:], AX[:], AY[:], message}
+	for _, bytes := range toWrite {
+		if _, err := hFunc.Write(bytes); err != nil {
+			return nil, err
+		}
+	}
+
+	hramBin := hFunc.Sum(nil)
+	return new(big.Int).SetBytes(hramBin), nil
+}
@@ -35,6 +35,11 @@ func (p *PointProj) scalarMulGLV(p1 *PointProj, scalar *big.Int) *PointProj {
 
 	initOnce.Do(initCurveParams)
 
+	// the endomorphism isn't defined at the neutral element
+	if p1.IsZero() {
+		return p.setInfinity()
+	}
+
 	var table [15]PointProj
 	var res PointProj
 	var k1, k2 fr.Element
@@ -132,6 +137,11 @@ func (p *PointExtended) scalarMulGLV(p1 *PointExtended, scalar *big.Int) *PointE
 
 	initOnce.Do(initCurveParams)
 
+	// the endomorphism isn't defined at the neutral element
+	if p1.IsZero() {
+		return p.setInfinity()
+	}
+
 	var table [15]PointExtended
 	var res PointExtended
 	var k1, k2 fr.Element
@@ -0,0 +1,104 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package ipa
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch/fr"
+	"github.com/consensys/gnark-crypto/internal/parallel"
+)
+
+// Digest commitment of a vector
+type Digest = bandersnatch.PointAffine
+
+// multiExp returns ∑ᵢ[sᵢ]Pᵢ
+func multiExp(points []bandersnatch.PointAffine, scalars []fr.Element) (bandersnatch.PointAffine, error) {
+	bScalars := make([]big.Int, len(scalars))
+	for i := range scalars {
+		scalars[i].BigInt(&bScalars[i])
+	}
+	var res bandersnatch.PointExtended
+	if _, err := res.MultiExp(points, bScalars, ecc.MultiExpConfig{}); err != nil {
+		return bandersnatch.PointAffine{}, err
+	}
+
+	var p bandersnatch.PointAffine
+	p.FromExtended(&res)
+	return p, nil
+}
+
+// foldPoints sets left[i] to left[i] + [x]right[i]
+func foldPoints(left, right []bandersnatch.PointAffine, x fr.Element) {
+	var bX big.Int
+	x.BigInt(&bX)
+	parallel.Execute(len(left), func(start, end int) {
+		var tmp bandersnatch.PointAffine
+		for i := start; i < end; i++ {
+			tmp.ScalarMultiplication(&right[i], &bX)
+			left[i].Add(&left[i], &tmp)
+		}
+	})
+}
+
+// hashToGroup returns n points of the subgroup of prime order derived from
+// seed, with try-and-increment on the compressed encoding of the points, so
+// that their discrete logarithms are unknown.
+func hashToGroup(seed string, n int) ([]bandersnatch.PointAffine, error) {
+	cofactor := bandersnatch.GetEdwardsCurve().Cofactor
+	var bCofactor big.Int
+	cofactor.BigInt(&bCofactor)
+
+	res := make([]bandersnatch.PointAffine, n)
+	var buf [16]byte
+	for i := range res {
+		binary.BigEndian.PutUint64(buf[:8], uint64(i))
+		for counter := uint64(0); ; counter++ {
+			binary.BigEndian.PutUint64(buf[8:], counter)
+			h := sha256.New()
+			h.Write([]byte(seed))
+			h.Write(buf[:])
+
+			var p bandersnatch.PointAffine
+			if _, err := p.SetBytes(h.Sum(nil)); err != nil {
+				return nil, err
+			}
+			if !p.IsOnCurve() {
+				continue
+			}
+			res[i].ScalarMultiplication(&p, &bCofactor)
+			if !res[i].IsZero() {
+				break
+			}
+		}
+	}
+	return res, nil
+}
+
+// isIdentity returns true if p is the neutral element of the group
+func isIdentity(p *bandersnatch.PointAffine) bool {
+	return p.IsZero()
+}
+
+// marshalPoint returns the encoding of p bound in the transcript
+func marshalPoint(p *bandersnatch.PointAffine) []byte {
+	return p.Marshal()
+}
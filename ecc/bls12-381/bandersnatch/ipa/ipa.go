@@ -0,0 +1,322 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package ipa
+
+import (
+	"errors"
+	"hash"
+	"math/bits"
+	"strconv"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch/fr"
+	fiatshamir "github.com/consensys/gnark-crypto/fiat-shamir"
+)
+
+var (
+	ErrSRSSize               = errors.New("the size of the SRS should be a power of 2")
+	ErrInvalidPolynomialSize = errors.New("invalid polynomial size (larger than SRS or == 0)")
+	ErrInvalidVectorSize     = errors.New("the size of the vector should be the size of the SRS")
+	ErrInvalidProofSize      = errors.New("the number of rounds of the proof doesn't match the size of the SRS")
+	ErrVerifyOpeningProof    = errors.New("can't verify opening proof")
+)
+
+// seeds from which the generators are derived
+const (
+	seedG = "gnark-crypto IPA generators G"
+	seedQ = "gnark-crypto IPA generator Q"
+)
+
+// SRS generators of the commitment scheme, which contain no secret.
+type SRS struct {
+	G []bandersnatch.PointAffine // generators of the Pedersen vector commitment
+	Q bandersnatch.PointAffine   // generator used to bind the inner product
+}
+
+// OpeningProof proves that the vector a committed in a digest satisfies
+// ⟨a, b⟩ = ClaimedValue, for a public vector b.
+type OpeningProof struct {
+	// L, R commitments to the cross terms of each round, the i-th round folds
+	// the vectors of size n/2ⁱ in half
+	L, R []bandersnatch.PointAffine
+
+	// A is the vector a after the log₂(n) folds
+	A fr.Element
+
+	// ClaimedValue purported value ⟨a, b⟩
+	ClaimedValue fr.Element
+}
+
+// NewSRS returns the generators for vectors of size up to size, which must be
+// a power of 2. The generators are derived deterministically, so that the
+// SRS of a given size is a prefix of the larger ones.
+func NewSRS(size uint64) (*SRS, error) {
+	if size == 0 || bits.OnesCount64(size) != 1 {
+		return nil, ErrSRSSize
+	}
+	g, err := hashToGroup(seedG, int(size))
+	if err != nil {
+		return nil, err
+	}
+	q, err := hashToGroup(seedQ, 1)
+	if err != nil {
+		return nil, err
+	}
+	return &SRS{G: g, Q: q[0]}, nil
+}
+
+// Commit returns the Pedersen vector commitment ∑ᵢ[aᵢ]Gᵢ, where a are the
+// coefficients of a polynomial or any vector of size at most len(srs.G).
+func Commit(a []fr.Element, srs *SRS) (Digest, error) {
+	if len(a) == 0 || len(a) > len(srs.G) {
+		return Digest{}, ErrInvalidPolynomialSize
+	}
+	return multiExp(srs.G[:len(a)], a)
+}
+
+// Open computes an opening proof of the polynomial p at point, p being given
+// in canonical basis and committed in commitment.
+//
+// * hf is the hash function used for the Fiat Shamir transform
+func Open(p []fr.Element, point fr.Element, commitment *Digest, hf hash.Hash, srs *SRS) (OpeningProof, error) {
+	return OpenInnerProduct(p, powers(point, len(srs.G)), commitment, hf, srs)
+}
+
+// Verify verifies an opening proof at point of a polynomial committed in
+// commitment.
+//
+// * hf is the hash function used for the Fiat Shamir transform
+func Verify(commitment *Digest, proof *OpeningProof, point fr.Element, hf hash.Hash, srs *SRS) error {
+	return VerifyInnerProduct(commitment, proof, powers(point, len(srs.G)), hf, srs)
+}
+
+// OpenInnerProduct computes a proof of ⟨a, b⟩, where a is committed in
+// commitment and b has the size of the SRS. a is padded with zeroes.
+//
+// At each round, with C = ⟨a, G⟩ + [⟨a, b⟩]Q, the prover sends
+//
+//	L = ⟨a_R, G_L⟩ + [⟨a_R, b_L⟩]Q and R = ⟨a_L, G_R⟩ + [⟨a_L, b_R⟩]Q
+//
+// and the vectors are folded in a_L + x⋅a_R, b_L + x⁻¹⋅b_R and G_L + [x⁻¹]G_R,
+// so that the new commitment is C + [x]L + [x⁻¹]R.
+//
+// * hf is the hash function used for the Fiat Shamir transform
+func OpenInnerProduct(a, b []fr.Element, commitment *Digest, hf hash.Hash, srs *SRS) (OpeningProof, error) {
+	n := len(srs.G)
+	if len(a) == 0 || len(a) > n {
+		return OpeningProof{}, ErrInvalidPolynomialSize
+	}
+	if len(b) != n {
+		return OpeningProof{}, ErrInvalidVectorSize
+	}
+
+	_a := make([]fr.Element, n)
+	copy(_a, a)
+	_b := make([]fr.Element, n)
+	copy(_b, b)
+	g := make([]bandersnatch.PointAffine, n)
+	copy(g, srs.G)
+
+	var res OpeningProof
+	res.ClaimedValue = innerProduct(_a, _b)
+
+	// Q is replaced by [w]Q, so that the prover can't cheat on ⟨a, b⟩
+	nbRounds := bits.TrailingZeros(uint(n))
+	fs := newTranscript(hf, nbRounds)
+	w, err := deriveChallenge(fs, "w", marshalPoint(commitment), marshalVector(b), res.ClaimedValue.Marshal())
+	if err != nil {
+		return OpeningProof{}, err
+	}
+
+	res.L = make([]bandersnatch.PointAffine, nbRounds)
+	res.R = make([]bandersnatch.PointAffine, nbRounds)
+	for i := 0; i < nbRounds; i++ {
+		m := len(_a) / 2
+		aL, aR := _a[:m], _a[m:]
+		bL, bR := _b[:m], _b[m:]
+		gL, gR := g[:m], g[m:]
+
+		// L = ⟨a_R, G_L⟩ + [w⟨a_R, b_L⟩]Q, R = ⟨a_L, G_R⟩ + [w⟨a_L, b_R⟩]Q
+		cL := innerProduct(aR, bL)
+		cL.Mul(&cL, &w)
+		if res.L[i], err = multiExp(append(gL[:m:m], srs.Q), append(aR[:m:m], cL)); err != nil {
+			return OpeningProof{}, err
+		}
+		cR := innerProduct(aL, bR)
+		cR.Mul(&cR, &w)
+		if res.R[i], err = multiExp(append(gR[:m:m], srs.Q), append(aL[:m:m], cR)); err != nil {
+			return OpeningProof{}, err
+		}
+
+		x, err := deriveChallenge(fs, roundChallengeID(i), marshalPoint(&res.L[i]), marshalPoint(&res.R[i]))
+		if err != nil {
+			return OpeningProof{}, err
+		}
+		var xInv, t fr.Element
+		xInv.Inverse(&x)
+
+		for j := 0; j < m; j++ {
+			t.Mul(&aR[j], &x)
+			aL[j].Add(&aL[j], &t)
+			t.Mul(&bR[j], &xInv)
+			bL[j].Add(&bL[j], &t)
+		}
+		foldPoints(gL, gR, xInv)
+
+		_a, _b, g = aL, bL, gL
+	}
+	res.A = _a[0]
+
+	return res, nil
+}
+
+// VerifyInnerProduct verifies a proof of ⟨a, b⟩, where a is committed in
+// commitment and b has the size of the SRS.
+//
+// The folds of G and b are the inner products with s, where sᵢ is the product
+// of the x⁻¹ of the rounds selecting the right half for i. The verifier checks
+// with a single multi-scalar multiplication that
+//
+//	C + [w⟨a, b⟩]Q + ∑ᵢ([xᵢ]Lᵢ + [xᵢ⁻¹]Rᵢ) = [A]⟨s, G⟩ + [wA⟨s, b⟩]Q
+//
+// * hf is the hash function used for the Fiat Shamir transform
+func VerifyInnerProduct(commitment *Digest, proof *OpeningProof, b []fr.Element, hf hash.Hash, srs *SRS) error {
+	n := len(srs.G)
+	if len(b) != n {
+		return ErrInvalidVectorSize
+	}
+	nbRounds := bits.TrailingZeros(uint(n))
+	if len(proof.L) != nbRounds || len(proof.R) != nbRounds {
+		return ErrInvalidProofSize
+	}
+
+	// recover the challenges
+	fs := newTranscript(hf, nbRounds)
+	w, err := deriveChallenge(fs, "w", marshalPoint(commitment), marshalVector(b), proof.ClaimedValue.Marshal())
+	if err != nil {
+		return err
+	}
+	x := make([]fr.Element, nbRounds)
+	for i := range x {
+		if x[i], err = deriveChallenge(fs, roundChallengeID(i), marshalPoint(&proof.L[i]), marshalPoint(&proof.R[i])); err != nil {
+			return err
+		}
+	}
+	xInv := fr.BatchInvert(x)
+
+	// s, the first round selects the most significant bit of the index
+	s := make([]fr.Element, n)
+	s[0].SetOne()
+	for i := 0; i < nbRounds; i++ {
+		for j := (1 << i) - 1; j >= 0; j-- {
+			s[2*j+1].Mul(&s[j], &xInv[i])
+			s[2*j] = s[j]
+		}
+	}
+	b0 := innerProduct(s, b)
+
+	// C + [w(⟨a, b⟩ - A⋅b₀)]Q + ∑ᵢ([xᵢ]Lᵢ + [xᵢ⁻¹]Rᵢ) - [A]⟨s, G⟩ == 0
+	points := make([]bandersnatch.PointAffine, 0, 2+2*nbRounds+n)
+	scalars := make([]fr.Element, 0, 2+2*nbRounds+n)
+	var one, cQ fr.Element
+	one.SetOne()
+	cQ.Mul(&proof.A, &b0).
+		Sub(&proof.ClaimedValue, &cQ).
+		Mul(&cQ, &w)
+	points = append(points, *commitment, srs.Q)
+	scalars = append(scalars, one, cQ)
+	points = append(points, proof.L...)
+	scalars = append(scalars, x...)
+	points = append(points, proof.R...)
+	scalars = append(scalars, xInv...)
+	points = append(points, srs.G...)
+	var minusA fr.Element
+	minusA.Neg(&proof.A)
+	for i := range s {
+		s[i].Mul(&s[i], &minusA)
+	}
+	scalars = append(scalars, s...)
+
+	check, err := multiExp(points, scalars)
+	if err != nil {
+		return err
+	}
+	if !isIdentity(&check) {
+		return ErrVerifyOpeningProof
+	}
+	return nil
+}
+
+// newTranscript returns the Fiat Shamir transcript of a proof with nbRounds
+// rounds.
+func newTranscript(hf hash.Hash, nbRounds int) *fiatshamir.Transcript {
+	challenges := make([]string, nbRounds+1)
+	challenges[0] = "w"
+	for i := 0; i < nbRounds; i++ {
+		challenges[i+1] = roundChallengeID(i)
+	}
+	return fiatshamir.NewTranscript(hf, challenges...)
+}
+
+func roundChallengeID(i int) string {
+	return "x" + strconv.Itoa(i)
+}
+
+// deriveChallenge binds data to the challenge name and derives it.
+func deriveChallenge(fs *fiatshamir.Transcript, name string, data ...[]byte) (fr.Element, error) {
+	for i := range data {
+		if err := fs.Bind(name, data[i]); err != nil {
+			return fr.Element{}, err
+		}
+	}
+	b, err := fs.ComputeChallenge(name)
+	if err != nil {
+		return fr.Element{}, err
+	}
+	var res fr.Element
+	res.SetBytes(b)
+	return res, nil
+}
+
+func marshalVector(v []fr.Element) []byte {
+	res := make([]byte, 0, len(v)*fr.Bytes)
+	for i := range v {
+		b := v[i].Bytes()
+		res = append(res, b[:]...)
+	}
+	return res
+}
+
+// innerProduct returns ∑ᵢ aᵢbᵢ
+func innerProduct(a, b []fr.Element) fr.Element {
+	var res, t fr.Element
+	for i := range a {
+		t.Mul(&a[i], &b[i])
+		res.Add(&res, &t)
+	}
+	return res
+}
+
+// powers returns (1, x, x², ..., xⁿ⁻¹)
+func powers(x fr.Element, n int) []fr.Element {
+	res := make([]fr.Element, n)
+	res[0].SetOne()
+	for i := 1; i < n; i++ {
+		res[i].Mul(&res[i-1], &x)
+	}
+	return res
+}
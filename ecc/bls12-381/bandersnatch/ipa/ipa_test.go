@@ -0,0 +1,189 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package ipa
+
+import (
+	"crypto/sha256"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch/fr"
+)
+
+func randomVector(size int) []fr.Element {
+	res := make([]fr.Element, size)
+	for i := range res {
+		res[i].SetRandom()
+	}
+	return res
+}
+
+// eval returns p(point)
+func eval(p []fr.Element, point fr.Element) fr.Element {
+	var res fr.Element
+	for i := len(p) - 1; i >= 0; i-- {
+		res.Mul(&res, &point).Add(&res, &p[i])
+	}
+	return res
+}
+
+func TestNewSRS(t *testing.T) {
+	assert := require.New(t)
+
+	srs, err := NewSRS(16)
+	assert.NoError(err)
+	for i := range srs.G {
+		assert.True(srs.G[i].IsOnCurve(), "generator not on the curve")
+		assert.False(srs.G[i].Equal(&srs.Q), "generators should be distinct")
+		for j := 0; j < i; j++ {
+			assert.False(srs.G[i].Equal(&srs.G[j]), "generators should be distinct")
+		}
+	}
+
+	// the SRS is a prefix of the larger ones
+	larger, err := NewSRS(32)
+	assert.NoError(err)
+	assert.Equal(srs.G, larger.G[:16])
+	assert.Equal(srs.Q, larger.Q)
+
+	_, err = NewSRS(24)
+	assert.ErrorIs(err, ErrSRSSize)
+}
+
+func TestOpen(t *testing.T) {
+	assert := require.New(t)
+
+	const size = 32
+	srs, err := NewSRS(size)
+	assert.NoError(err)
+
+	for _, n := range []int{size, 11, 1} {
+		p := randomVector(n)
+		digest, err := Commit(p, srs)
+		assert.NoError(err)
+
+		var point fr.Element
+		point.SetRandom()
+		proof, err := Open(p, point, &digest, sha256.New(), srs)
+		assert.NoError(err)
+		expected := eval(p, point)
+		assert.True(expected.Equal(&proof.ClaimedValue), "wrong claimed value")
+		assert.NoError(Verify(&digest, &proof, point, sha256.New(), srs))
+
+		// wrong point
+		var wrongPoint fr.Element
+		wrongPoint.SetRandom()
+		assert.ErrorIs(Verify(&digest, &proof, wrongPoint, sha256.New(), srs), ErrVerifyOpeningProof)
+
+		// wrong claimed value
+		wrongProof := proof
+		wrongProof.ClaimedValue.Double(&proof.ClaimedValue)
+		assert.ErrorIs(Verify(&digest, &wrongProof, point, sha256.New(), srs), ErrVerifyOpeningProof)
+
+		// wrong final value
+		wrongProof = proof
+		wrongProof.A.Double(&proof.A)
+		assert.ErrorIs(Verify(&digest, &wrongProof, point, sha256.New(), srs), ErrVerifyOpeningProof)
+
+		// wrong cross term
+		wrongProof = proof
+		wrongProof.L = make([]Digest, len(proof.L))
+		copy(wrongProof.L, proof.L)
+		wrongProof.L[0] = proof.R[0]
+		assert.ErrorIs(Verify(&digest, &wrongProof, point, sha256.New(), srs), ErrVerifyOpeningProof)
+
+		// truncated proof
+		wrongProof = proof
+		wrongProof.L = proof.L[1:]
+		assert.ErrorIs(Verify(&digest, &wrongProof, point, sha256.New(), srs), ErrInvalidProofSize)
+	}
+
+	_, err = Commit(randomVector(size+1), srs)
+	assert.ErrorIs(err, ErrInvalidPolynomialSize)
+}
+
+func TestOpenInnerProduct(t *testing.T) {
+	assert := require.New(t)
+
+	const size = 16
+	srs, err := NewSRS(size)
+	assert.NoError(err)
+
+	a := randomVector(size)
+	b := randomVector(size)
+	digest, err := Commit(a, srs)
+	assert.NoError(err)
+
+	proof, err := OpenInnerProduct(a, b, &digest, sha256.New(), srs)
+	assert.NoError(err)
+	assert.NoError(VerifyInnerProduct(&digest, &proof, b, sha256.New(), srs))
+
+	// wrong commitment or vector
+	other, err := Commit(b, srs)
+	assert.NoError(err)
+	assert.ErrorIs(VerifyInnerProduct(&other, &proof, b, sha256.New(), srs), ErrVerifyOpeningProof)
+	b[3].SetOne()
+	assert.ErrorIs(VerifyInnerProduct(&digest, &proof, b, sha256.New(), srs), ErrVerifyOpeningProof)
+
+	_, err = OpenInnerProduct(a, b[1:], &digest, sha256.New(), srs)
+	assert.ErrorIs(err, ErrInvalidVectorSize)
+}
+
+func BenchmarkOpen(b *testing.B) {
+	const size = 256
+	srs, err := NewSRS(size)
+	if err != nil {
+		b.Fatal(err)
+	}
+	p := randomVector(size)
+	digest, err := Commit(p, srs)
+	if err != nil {
+		b.Fatal(err)
+	}
+	var point fr.Element
+	point.SetRandom()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = Open(p, point, &digest, sha256.New(), srs)
+	}
+}
+
+func BenchmarkVerify(b *testing.B) {
+	const size = 256
+	srs, err := NewSRS(size)
+	if err != nil {
+		b.Fatal(err)
+	}
+	p := randomVector(size)
+	digest, err := Commit(p, srs)
+	if err != nil {
+		b.Fatal(err)
+	}
+	var point fr.Element
+	point.SetRandom()
+	proof, err := Open(p, point, &digest, sha256.New(), srs)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = Verify(&digest, &proof, point, sha256.New(), srs)
+	}
+}
@@ -0,0 +1,218 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package bandersnatch
+
+import (
+	"errors"
+	"math/big"
+	"math/bits"
+	"runtime"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/internal/parallel"
+)
+
+// MultiExp computes the multi-exponentiation ∑_{i=0}^{len(points)-1} [scalars[i]]points[i]
+// and assigns the result to p in extended coordinates.
+//
+// It uses the bucket method of Pippenger with signed digits: the scalars are
+// split in c-bit windows (chunks), the chunks are processed in parallel and,
+// if there are more tasks than chunks, the points are split among the tasks too.
+//
+// Each scalar is first split with the GLV decomposition into two scalars of
+// half size, for the point and its image by the endomorphism. As for
+// ScalarMultiplication, the scalars are reduced modulo the order of the curve
+// and the points must be in the subgroup of prime order, except for the points
+// (0, ±1) of order at most 2.
+func (p *PointExtended) MultiExp(points []PointAffine, scalars []big.Int, config ecc.MultiExpConfig) (*PointExtended, error) {
+	nbPoints := len(points)
+	if nbPoints != len(scalars) {
+		return nil, errors.New("len(points) != len(scalars)")
+	}
+
+	// if nbTasks is not set, use all available CPUs
+	if config.NbTasks <= 0 {
+		config.NbTasks = runtime.NumCPU() * 2
+	} else if config.NbTasks > 1024 {
+		return nil, errors.New("invalid config: config.NbTasks > 1024")
+	}
+
+	if nbPoints == 0 {
+		return p.setInfinity(), nil
+	}
+
+	// step 1
+	// we make the scalars non-negative by negating the points, in extended
+	// coordinates for the bucket additions.
+	// Each scalar s is split in s = k₀ + k₁λ, for the points P and ϕ(P).
+	initOnce.Do(initCurveParams)
+	nbPoints *= 2
+	extPoints := make([]PointExtended, nbPoints)
+	absScalars := make([]big.Int, nbPoints)
+	parallel.Execute(len(points), func(start, end int) {
+		var s big.Int
+		for i := start; i < end; i++ {
+			extPoints[2*i].FromAffine(&points[i])
+			if points[i].X.IsZero() {
+				// (0, 1) and (0, -1) are of order at most 2, and the
+				// endomorphism isn't defined at these points: [s](0, ±1) only
+				// depends on the parity of s.
+				absScalars[2*i].SetUint64(uint64(scalars[i].Bit(0)))
+				extPoints[2*i+1].setInfinity()
+				continue
+			}
+			s.Mod(&scalars[i], &curveParams.Order)
+			k := ecc.SplitScalar(&s, &curveParams.glvBasis)
+			extPoints[2*i+1].phi(&extPoints[2*i])
+			for j := 0; j < 2; j++ {
+				absScalars[2*i+j].Abs(&k[j])
+				if k[j].Sign() == -1 {
+					extPoints[2*i+j].Neg(&extPoints[2*i+j])
+				}
+			}
+		}
+	}, config.NbTasks)
+
+	nbBits := 0
+	for i := range absScalars {
+		if l := absScalars[i].BitLen(); l > nbBits {
+			nbBits = l
+		}
+	}
+	if nbBits == 0 {
+		return p.setInfinity(), nil
+	}
+
+	// step 2
+	// we pick the window size c minimizing the number of additions, and split
+	// the scalars in signed digits in [-2^{c-1}, 2^{c-1}]
+	c := bestCMultiExp(nbPoints, nbBits)
+	nbChunks := (nbBits + c) / c // the last digit may carry
+	digits := make([]int32, nbChunks*nbPoints)
+	parallel.Execute(nbPoints, func(start, end int) {
+		for i := start; i < end; i++ {
+			signedDigits(digits, &absScalars[i], i, nbPoints, c)
+		}
+	}, config.NbTasks)
+
+	// step 3
+	// each task accumulates the points of a split in the buckets of a chunk
+	nbSplits := 1
+	if nbChunks < config.NbTasks {
+		nbSplits = (config.NbTasks + nbChunks - 1) / nbChunks
+		// each split should have at least as many points as buckets
+		if maxSplits := nbPoints >> (c - 1); nbSplits > maxSplits {
+			nbSplits = max(maxSplits, 1)
+		}
+	}
+	splitSize := (nbPoints + nbSplits - 1) / nbSplits
+	results := make([]PointExtended, nbChunks*nbSplits)
+	parallel.Execute(len(results), func(start, end int) {
+		buckets := make([]PointExtended, 1<<(c-1))
+		for t := start; t < end; t++ {
+			chunk, split := t/nbSplits, t%nbSplits
+			from := split * splitSize
+			to := min(from+splitSize, nbPoints)
+			processChunkExtended(&results[t], buckets, extPoints[from:to], digits[chunk*nbPoints+from:chunk*nbPoints+to])
+		}
+	}, config.NbTasks)
+
+	// step 4
+	// reduce the chunks: p = ∑ 2^{c*chunk} results[chunk]
+	var res PointExtended
+	res.setInfinity()
+	for chunk := nbChunks - 1; chunk >= 0; chunk-- {
+		if chunk != nbChunks-1 {
+			for j := 0; j < c; j++ {
+				res.Double(&res)
+			}
+		}
+		for split := 0; split < nbSplits; split++ {
+			res.Add(&res, &results[chunk*nbSplits+split])
+		}
+	}
+
+	return p.Set(&res), nil
+}
+
+// bestCMultiExp returns the window size c in [2, 16] minimizing the number of
+// additions of a multi-exponentiation of nbPoints scalars of nbBits bits.
+func bestCMultiExp(nbPoints, nbBits int) int {
+	bestC, bestCost := 2, -1
+	for c := 2; c <= 16; c++ {
+		nbChunks := (nbBits + c) / c
+		cost := nbChunks * (nbPoints + (1 << c))
+		if bestCost < 0 || cost < bestCost {
+			bestC, bestCost = c, cost
+		}
+	}
+	return bestC
+}
+
+// signedDigits writes the digits of s in base 2^c, in [-2^{c-1}, 2^{c-1}], to
+// digits[chunk*nbPoints+i].
+func signedDigits(digits []int32, s *big.Int, i, nbPoints, c int) {
+	words := s.Bits()
+	nbChunks := len(digits) / nbPoints
+	mask := big.Word(1)<<c - 1
+	carry := int32(0)
+	for chunk := 0; chunk < nbChunks; chunk++ {
+		pos := chunk * c
+		w, shift := pos/bits.UintSize, pos%bits.UintSize
+		var d big.Word
+		if w < len(words) {
+			d = words[w] >> shift
+			if shift+c > bits.UintSize && w+1 < len(words) {
+				d |= words[w+1] << (bits.UintSize - shift)
+			}
+		}
+		digit := int32(d&mask) + carry
+		carry = 0
+		if digit > 1<<(c-1) {
+			digit -= 1 << c
+			carry = 1
+		}
+		digits[chunk*nbPoints+i] = digit
+	}
+}
+
+// processChunkExtended sets res to ∑ [digits[i]]points[i], with the
+// 2^{c-1} buckets.
+func processChunkExtended(res *PointExtended, buckets []PointExtended, points []PointExtended, digits []int32) {
+	for i := range buckets {
+		buckets[i].setInfinity()
+	}
+
+	var neg PointExtended
+	for i, digit := range digits {
+		if digit > 0 {
+			buckets[digit-1].Add(&buckets[digit-1], &points[i])
+		} else if digit < 0 {
+			neg.Neg(&points[i])
+			buckets[-digit-1].Add(&buckets[-digit-1], &neg)
+		}
+	}
+
+	// ∑ (k+1)*buckets[k] = ∑_k ∑_{j ≥ k} buckets[j]
+	var runningSum PointExtended
+	runningSum.setInfinity()
+	res.setInfinity()
+	for k := len(buckets) - 1; k >= 0; k-- {
+		runningSum.Add(&runningSum, &buckets[k])
+		res.Add(res, &runningSum)
+	}
+}
@@ -0,0 +1,158 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package bandersnatch
+
+import (
+	"fmt"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+)
+
+// multiExpNaive returns ∑ [scalars[i]]points[i] with one scalar multiplication per point.
+func multiExpNaive(points []PointAffine, scalars []big.Int) PointExtended {
+	var res, tmp PointExtended
+	res.setInfinity()
+	for i := range points {
+		tmp.FromAffine(&points[i])
+		tmp.ScalarMultiplication(&tmp, &scalars[i])
+		res.Add(&res, &tmp)
+	}
+	return res
+}
+
+// multiExpInputs returns n multiples of the base point, with some neutral
+// elements, and random scalars with some negative or zero ones.
+func multiExpInputs(n int, r *rand.Rand) ([]PointAffine, []big.Int) {
+	params := GetEdwardsCurve()
+	points := make([]PointAffine, n)
+	scalars := make([]big.Int, n)
+	for i := 0; i < n; i++ {
+		points[i].ScalarMultiplication(&params.Base, big.NewInt(int64(i+1)))
+		scalars[i].Rand(r, &params.Order)
+		switch i % 7 {
+		case 3:
+			scalars[i].Neg(&scalars[i])
+		case 5:
+			scalars[i].SetUint64(0)
+		case 6:
+			points[i].X.SetZero()
+			points[i].Y.SetOne()
+		}
+	}
+	return points, scalars
+}
+
+func TestMultiExp(t *testing.T) {
+	t.Parallel()
+	r := rand.New(rand.NewSource(0)) //#nosec G404 weak rng is fine here
+
+	sizes := []int{1, 2, 5, 31, 200}
+	if !testing.Short() {
+		sizes = append(sizes, 1500)
+	}
+	for _, n := range sizes {
+		points, scalars := multiExpInputs(n, r)
+		expected := multiExpNaive(points, scalars)
+
+		for _, nbTasks := range []int{1, 3, 16, 0} {
+			var res PointExtended
+			if _, err := res.MultiExp(points, scalars, ecc.MultiExpConfig{NbTasks: nbTasks}); err != nil {
+				t.Fatal(err)
+			}
+			if !res.Equal(&expected) {
+				t.Fatalf("MultiExp of %d points with %d tasks doesn't match the naive sum", n, nbTasks)
+			}
+		}
+	}
+
+	// duplicated points, and opposite ones in the same buckets
+	points, scalars := multiExpInputs(4, r)
+	points = append(points, points[0], points[1])
+	points[5].Neg(&points[5])
+	scalars = append(scalars, scalars[0], scalars[1])
+	expected := multiExpNaive(points, scalars)
+	var res PointExtended
+	res.MultiExp(points, scalars, ecc.MultiExpConfig{})
+	if !res.Equal(&expected) {
+		t.Fatal("MultiExp with duplicated points doesn't match the naive sum")
+	}
+
+	// the points of order at most 2, (0, 1) and (0, -1), with random positive
+	// and negative scalars: [s](0, -1) is (0, -1) if s is odd, and the neutral
+	// element otherwise
+	params := GetEdwardsCurve()
+	var zero, order2 PointAffine
+	zero.X.SetZero()
+	zero.Y.SetOne()
+	order2.X.SetZero()
+	order2.Y.SetOne().Neg(&order2.Y)
+	for i := 0; i < 8; i++ {
+		points := []PointAffine{params.Base, zero, order2}
+		scalars := make([]big.Int, 3)
+		for j := range scalars {
+			scalars[j].Rand(r, &params.Order)
+		}
+		if i%2 == 1 {
+			scalars[2].Neg(&scalars[2])
+		}
+		var expected, tmp PointExtended
+		expected.FromAffine(&params.Base)
+		expected.ScalarMultiplication(&expected, &scalars[0])
+		if scalars[2].Bit(0) == 1 {
+			tmp.FromAffine(&order2)
+			expected.Add(&expected, &tmp)
+		}
+		if _, err := res.MultiExp(points, scalars, ecc.MultiExpConfig{}); err != nil {
+			t.Fatal(err)
+		}
+		if !res.Equal(&expected) {
+			t.Fatal("MultiExp with the points of order at most 2 doesn't match the expected sum")
+		}
+	}
+
+	// errors
+	if _, err := res.MultiExp(points[1:], scalars, ecc.MultiExpConfig{}); err == nil {
+		t.Fatal("expected an error for len(points) != len(scalars)")
+	}
+	if _, err := res.MultiExp(points, scalars, ecc.MultiExpConfig{NbTasks: 1025}); err == nil {
+		t.Fatal("expected an error for too many tasks")
+	}
+}
+
+func BenchmarkMultiExp(b *testing.B) {
+	r := rand.New(rand.NewSource(0)) //#nosec G404 weak rng is fine here
+	const maxLog = 14
+	points, scalars := multiExpInputs(1<<maxLog, r)
+
+	var res PointExtended
+	for log := 8; log <= maxLog; log += 3 {
+		n := 1 << log
+		b.Run(fmt.Sprintf("%d points", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				res.MultiExp(points[:n], scalars[:n], ecc.MultiExpConfig{})
+			}
+		})
+		b.Run(fmt.Sprintf("%d points (naive)", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				multiExpNaive(points[:n], scalars[:n])
+			}
+		})
+	}
+}
@@ -0,0 +1,179 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verkle
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"sync"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch/banderwagon"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch/fr"
+	fp "github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+const (
+	NodeWidth = 256 // number of children of an internal node, and of values of a leaf
+	StemSize  = 31  // number of bytes of a stem
+	KeySize   = 32  // number of bytes of a key
+	ValueSize = 32  // number of bytes of a value
+
+	nbRounds = 8 // log₂(NodeWidth), number of rounds of the inner product argument
+)
+
+// crsSeed seed from which the generators are derived
+const crsSeed = "eth_verkle_oct_2021"
+
+// config generators of the commitments and precomputations for the
+// polynomials in evaluation form on the domain {0, ..., 255}
+type config struct {
+	crs [NodeWidth]banderwagon.Element
+	q   banderwagon.Element // generator binding the inner product
+
+	// weights[i] = A'(i) = ∏_{j≠i}(i-j), where A(X) = ∏ⱼ(X-j)
+	weights, invWeights [NodeWidth]fr.Element
+
+	// invDomain[i] = 1/i, for 0 < i < NodeWidth
+	invDomain [NodeWidth]fr.Element
+}
+
+var (
+	configOnce sync.Once
+	_config    config
+)
+
+func getConfig() *config {
+	configOnce.Do(initConfig)
+	return &_config
+}
+
+func initConfig() {
+	copy(_config.crs[:], generateCRS(NodeWidth))
+	_config.q = banderwagon.Generator()
+
+	for i := 0; i < NodeWidth; i++ {
+		var iFr, jFr, t fr.Element
+		iFr.SetUint64(uint64(i))
+		_config.weights[i].SetOne()
+		for j := 0; j < NodeWidth; j++ {
+			if j == i {
+				continue
+			}
+			jFr.SetUint64(uint64(j))
+			t.Sub(&iFr, &jFr)
+			_config.weights[i].Mul(&_config.weights[i], &t)
+		}
+	}
+	invWeights := fr.BatchInvert(_config.weights[:])
+	copy(_config.invWeights[:], invWeights)
+
+	for i := 1; i < NodeWidth; i++ {
+		_config.invDomain[i].SetUint64(uint64(i))
+	}
+	invDomain := fr.BatchInvert(_config.invDomain[:])
+	copy(_config.invDomain[:], invDomain)
+}
+
+// generateCRS returns the n first elements x of banderwagon, in order, such
+// that the encoding of x is sha256(seed ‖ i) mod p for a counter i on 8 bytes
+// big-endian.
+func generateCRS(n int) []banderwagon.Element {
+	res := make([]banderwagon.Element, 0, n)
+	var counter [8]byte
+	for i := uint64(0); len(res) < n; i++ {
+		binary.BigEndian.PutUint64(counter[:], i)
+		h := sha256.New()
+		h.Write([]byte(crsSeed))
+		h.Write(counter[:])
+
+		var x fp.Element
+		x.SetBytes(h.Sum(nil))
+		b := x.Bytes()
+		var e banderwagon.Element
+		if err := e.SetBytes(b[:]); err != nil {
+			continue
+		}
+		res = append(res, e)
+	}
+	return res
+}
+
+// commit returns ∑ᵢ[vᵢ]Gᵢ
+func (c *config) commit(values []fr.Element) banderwagon.Element {
+	res, err := banderwagon.MultiExp(c.crs[:len(values)], values)
+	if err != nil {
+		panic(err) // the sizes are checked by the callers
+	}
+	return res
+}
+
+// lagrangeCoefficients returns (Lᵢ(z))ᵢ, where Lᵢ is the Lagrange polynomial
+// of i on the domain, so that p(z) = ∑ᵢ p(i)Lᵢ(z):
+//
+//	Lᵢ(z) = A(z) / (A'(i)(z-i))
+func (c *config) lagrangeCoefficients(z fr.Element) []fr.Element {
+	res := make([]fr.Element, NodeWidth)
+	if z.IsUint64() && z.Uint64() < NodeWidth {
+		res[z.Uint64()].SetOne()
+		return res
+	}
+
+	var az, iFr fr.Element
+	az.SetOne()
+	for i := range res {
+		iFr.SetUint64(uint64(i))
+		res[i].Sub(&z, &iFr)
+		az.Mul(&az, &res[i])
+		res[i].Mul(&res[i], &c.weights[i])
+	}
+	res = fr.BatchInvert(res)
+	for i := range res {
+		res[i].Mul(&res[i], &az)
+	}
+	return res
+}
+
+// divideOnDomain returns (f(X) - f(m)) / (X - m) in evaluation form. Its
+// evaluation at m is f'(m) = -∑_{i≠m} A'(m)/A'(i) ⋅ (f(i) - f(m))/(i - m).
+func (c *config) divideOnDomain(m uint8, f []fr.Element) []fr.Element {
+	q := make([]fr.Element, NodeWidth)
+	var t fr.Element
+	for i := 0; i < NodeWidth; i++ {
+		if i == int(m) {
+			continue
+		}
+		// 1 / (i - m)
+		if i > int(m) {
+			t = c.invDomain[i-int(m)]
+		} else {
+			t.Neg(&c.invDomain[int(m)-i])
+		}
+		q[i].Sub(&f[i], &f[m]).Mul(&q[i], &t)
+
+		t.Mul(&c.weights[m], &c.invWeights[i]).Mul(&t, &q[i])
+		q[m].Sub(&q[m], &t)
+	}
+	return q
+}
+
+// innerProduct returns ∑ᵢ aᵢbᵢ
+func innerProduct(a, b []fr.Element) fr.Element {
+	var res, t fr.Element
+	for i := range a {
+		t.Mul(&a[i], &b[i])
+		res.Add(&res, &t)
+	}
+	return res
+}
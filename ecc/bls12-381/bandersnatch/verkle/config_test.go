@@ -0,0 +1,98 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verkle
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch/fr"
+	"github.com/stretchr/testify/require"
+)
+
+func randomEvaluations() []fr.Element {
+	res := make([]fr.Element, NodeWidth)
+	for i := range res {
+		res[i].SetRandom()
+	}
+	return res
+}
+
+// TestCRS checks the generators against the ones of the specification
+func TestCRS(t *testing.T) {
+	assert := require.New(t)
+
+	c := getConfig()
+	b := c.crs[0].Bytes()
+	assert.Equal("01587ad1336675eb912550ec2a28eb8923b824b490dd2ba82e48f14590a298a0", hex.EncodeToString(b[:]))
+
+	h := sha256.New()
+	for i := range c.crs {
+		b := c.crs[i].Bytes()
+		h.Write(b[:])
+	}
+	assert.Equal("1fcaea10bf24f750200e06fa473c76ff0468007291fa548e2d99f09ba9256fdb", hex.EncodeToString(h.Sum(nil)))
+}
+
+func TestLagrangeCoefficients(t *testing.T) {
+	assert := require.New(t)
+	c := getConfig()
+
+	// on the domain
+	var z fr.Element
+	z.SetUint64(42)
+	b := c.lagrangeCoefficients(z)
+	for i := range b {
+		assert.Equal(i == 42, b[i].IsOne())
+	}
+
+	// a polynomial of degree 1: f(i) = 3i + 5
+	f := make([]fr.Element, NodeWidth)
+	for i := range f {
+		f[i].SetUint64(uint64(3*i + 5))
+	}
+	z.SetRandom()
+	b = c.lagrangeCoefficients(z)
+	var expected fr.Element
+	expected.SetUint64(3).Mul(&expected, &z)
+	var five fr.Element
+	five.SetUint64(5)
+	expected.Add(&expected, &five)
+	y := innerProduct(f, b)
+	assert.True(y.Equal(&expected))
+}
+
+func TestDivideOnDomain(t *testing.T) {
+	assert := require.New(t)
+	c := getConfig()
+
+	f := randomEvaluations()
+	var z fr.Element
+	z.SetRandom()
+	b := c.lagrangeCoefficients(z)
+	fz := innerProduct(f, b)
+
+	// q(z)(z - m) = f(z) - f(m)
+	for _, m := range []uint8{0, 1, 77, 255} {
+		q := c.divideOnDomain(m, f)
+		qz := innerProduct(q, b)
+		var mFr, l, r fr.Element
+		mFr.SetUint64(uint64(m))
+		l.Sub(&z, &mFr).Mul(&l, &qz)
+		r.Sub(&fz, &f[m])
+		assert.True(l.Equal(&r), "wrong quotient for m=%d", m)
+	}
+}
@@ -0,0 +1,38 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package verkle provides the Verkle trees of Ethereum, cf EIP-6800 and
+// https://notes.ethereum.org/@vbuterin/verkle_tree_eip.
+//
+// A key of 32 bytes is split in a stem of 31 bytes and a suffix. The tree has
+// internal nodes of width 256, indexed by the bytes of the stems, and leaves
+// (the extension nodes) holding the 256 values of a stem. A node commits to a
+// vector of 256 elements of the scalar field of banderwagon, seen as the
+// evaluations of a polynomial on {0, ..., 255}, with a Pedersen commitment
+// ∑ᵢ[vᵢ]Gᵢ. The generators are derived from the seed "eth_verkle_oct_2021".
+//
+//   - an internal node commits to the maps to the scalar field of the
+//     commitments of its children, 0 for the empty ones.
+//   - a leaf of stem s commits to (1, s, C₁, C₂, 0, ...), where C₁ and C₂ commit
+//     to the values of the suffixes 0-127 and 128-255: the value of the suffix
+//     i is split in two elements of 128 bits, at 2i and 2i+1 mod 256, the low
+//     one being marked with 2¹²⁸ to distinguish a zero value from an absent
+//     one.
+//
+// A proof of the values of a set of keys opens all the commitments on their
+// paths with a single multiproof, that aggregates the openings into an inner
+// product argument on banderwagon, cf https://dankradfeist.de/ethereum/2021/06/18/pcs-multiproofs.html.
+// The transcript and the serialization of the proofs are the ones of the
+// specification.
+package verkle
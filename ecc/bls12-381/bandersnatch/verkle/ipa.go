@@ -0,0 +1,163 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verkle
+
+import (
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch/banderwagon"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch/fr"
+	"github.com/consensys/gnark-crypto/internal/parallel"
+)
+
+// IPAProof proves the evaluation at a point of a polynomial in evaluation form
+// committed with the generators of the tree, with the inner product argument
+// of the specification. The protocol is the one of the ipa package, which
+// can't be reused here: it works on bandersnatch points, and its transcript
+// and encoding differ from the ones of the specification.
+type IPAProof struct {
+	// L, R commitments to the cross terms of each round
+	L, R [nbRounds]banderwagon.Element
+
+	// A is the vector of evaluations after the log₂(n) folds
+	A fr.Element
+}
+
+// createIPAProof proves that the polynomial of evaluations a, committed in
+// commitment, evaluates to ⟨a, b⟩ at z, where b are the Lagrange coefficients
+// at z.
+func createIPAProof(tr *transcript, c *config, commitment *banderwagon.Element, a []fr.Element, z fr.Element) IPAProof {
+	tr.domainSep("ipa")
+
+	_a := make([]fr.Element, NodeWidth)
+	copy(_a, a)
+	b := c.lagrangeCoefficients(z)
+	g := make([]banderwagon.Element, NodeWidth)
+	copy(g, c.crs[:])
+	y := innerProduct(_a, b)
+
+	tr.appendPoint(commitment, "C")
+	tr.appendScalar(&z, "input point")
+	tr.appendScalar(&y, "output point")
+	w := tr.challengeScalar("w")
+	var q banderwagon.Element
+	q.ScalarMultiplication(&c.q, &w)
+
+	var res IPAProof
+	for i := 0; i < nbRounds; i++ {
+		m := len(_a) / 2
+		aL, aR := _a[:m], _a[m:]
+		bL, bR := b[:m], b[m:]
+		gL, gR := g[:m], g[m:]
+
+		// L = ⟨a_R, G_L⟩ + [⟨a_R, b_L⟩]Q, R = ⟨a_L, G_R⟩ + [⟨a_L, b_R⟩]Q
+		zL := innerProduct(aR, bL)
+		zR := innerProduct(aL, bR)
+		var err error
+		if res.L[i], err = banderwagon.MultiExp(append(gL[:m:m], q), append(aR[:m:m], zL)); err != nil {
+			panic(err) // the sizes match
+		}
+		if res.R[i], err = banderwagon.MultiExp(append(gR[:m:m], q), append(aL[:m:m], zR)); err != nil {
+			panic(err)
+		}
+
+		tr.appendPoint(&res.L[i], "L")
+		tr.appendPoint(&res.R[i], "R")
+		x := tr.challengeScalar("x")
+		var xInv, t fr.Element
+		xInv.Inverse(&x)
+
+		// a_L + x⋅a_R, b_L + x⁻¹⋅b_R, G_L + [x⁻¹]G_R
+		for j := 0; j < m; j++ {
+			t.Mul(&aR[j], &x)
+			aL[j].Add(&aL[j], &t)
+			t.Mul(&bR[j], &xInv)
+			bL[j].Add(&bL[j], &t)
+		}
+		parallel.Execute(m, func(start, end int) {
+			var tmp banderwagon.Element
+			for j := start; j < end; j++ {
+				tmp.ScalarMultiplication(&gR[j], &xInv)
+				gL[j].Add(&gL[j], &tmp)
+			}
+		})
+
+		_a, b, g = aL, bL, gL
+	}
+	res.A = _a[0]
+
+	return res
+}
+
+// checkIPAProof verifies that the polynomial committed in commitment evaluates
+// to y at z. As in the ipa package, the folds of the generators and of the
+// Lagrange coefficients are the inner products with s, where sᵢ is the
+// product of the x⁻¹ of the rounds selecting the right half for i, and the
+// verifier checks that
+//
+//	C + [w(y - A⋅⟨s, b⟩)]Q + ∑ᵢ([xᵢ]Lᵢ + [xᵢ⁻¹]Rᵢ) - [A]⟨s, G⟩ = 0
+func checkIPAProof(tr *transcript, c *config, commitment *banderwagon.Element, proof *IPAProof, z, y fr.Element) bool {
+	tr.domainSep("ipa")
+
+	b := c.lagrangeCoefficients(z)
+	tr.appendPoint(commitment, "C")
+	tr.appendScalar(&z, "input point")
+	tr.appendScalar(&y, "output point")
+	w := tr.challengeScalar("w")
+
+	x := make([]fr.Element, nbRounds)
+	for i := range x {
+		tr.appendPoint(&proof.L[i], "L")
+		tr.appendPoint(&proof.R[i], "R")
+		x[i] = tr.challengeScalar("x")
+	}
+	xInv := fr.BatchInvert(x)
+
+	// s, the first round selects the most significant bit of the index
+	s := make([]fr.Element, NodeWidth)
+	s[0].SetOne()
+	for i := 0; i < nbRounds; i++ {
+		for j := (1 << i) - 1; j >= 0; j-- {
+			s[2*j+1].Mul(&s[j], &xInv[i])
+			s[2*j] = s[j]
+		}
+	}
+	b0 := innerProduct(s, b)
+
+	points := make([]banderwagon.Element, 0, 2+2*nbRounds+NodeWidth)
+	scalars := make([]fr.Element, 0, 2+2*nbRounds+NodeWidth)
+	var one, cQ fr.Element
+	one.SetOne()
+	cQ.Mul(&proof.A, &b0).
+		Sub(&y, &cQ).
+		Mul(&cQ, &w)
+	points = append(points, *commitment, c.q)
+	scalars = append(scalars, one, cQ)
+	points = append(points, proof.L[:]...)
+	scalars = append(scalars, x...)
+	points = append(points, proof.R[:]...)
+	scalars = append(scalars, xInv...)
+	points = append(points, c.crs[:]...)
+	var minusA fr.Element
+	minusA.Neg(&proof.A)
+	for i := range s {
+		s[i].Mul(&s[i], &minusA)
+	}
+	scalars = append(scalars, s...)
+
+	check, err := banderwagon.MultiExp(points, scalars)
+	if err != nil {
+		return false
+	}
+	return check.IsIdentity()
+}
@@ -0,0 +1,166 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verkle
+
+import (
+	"encoding/binary"
+	"io"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch/banderwagon"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch/fr"
+)
+
+// WriteTo writes the proof in the binary encoding of the specification: the
+// other stems, the extension statuses and the commitments, each list being
+// prefixed by its length on 4 bytes little-endian, followed by the multiproof.
+func (proof *Proof) WriteTo(w io.Writer) (int64, error) {
+	buf := make([]byte, 0, 12+len(proof.OtherStems)*StemSize+len(proof.DepthExtensionPresent)+len(proof.CommitmentsByPath)*banderwagon.Bytes)
+
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(len(proof.OtherStems)))
+	for i := range proof.OtherStems {
+		buf = append(buf, proof.OtherStems[i][:]...)
+	}
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(len(proof.DepthExtensionPresent)))
+	buf = append(buf, proof.DepthExtensionPresent...)
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(len(proof.CommitmentsByPath)))
+	for i := range proof.CommitmentsByPath {
+		b := proof.CommitmentsByPath[i].Bytes()
+		buf = append(buf, b[:]...)
+	}
+
+	n, err := w.Write(buf)
+	if err != nil {
+		return int64(n), err
+	}
+	m, err := proof.Multiproof.WriteTo(w)
+	return int64(n) + m, err
+}
+
+// ReadFrom reads a proof written by WriteTo
+func (proof *Proof) ReadFrom(r io.Reader) (int64, error) {
+	var n int64
+	readLength := func() (int, error) {
+		var b [4]byte
+		m, err := io.ReadFull(r, b[:])
+		n += int64(m)
+		return int(binary.LittleEndian.Uint32(b[:])), err
+	}
+
+	// the lists are read element by element, so that a corrupted length
+	// doesn't allocate
+	l, err := readLength()
+	if err != nil {
+		return n, err
+	}
+	proof.OtherStems = nil
+	for i := 0; i < l; i++ {
+		var stem [StemSize]byte
+		m, err := io.ReadFull(r, stem[:])
+		n += int64(m)
+		if err != nil {
+			return n, err
+		}
+		proof.OtherStems = append(proof.OtherStems, stem)
+	}
+
+	if l, err = readLength(); err != nil {
+		return n, err
+	}
+	proof.DepthExtensionPresent = nil
+	for i := 0; i < l; i++ {
+		var b [1]byte
+		m, err := io.ReadFull(r, b[:])
+		n += int64(m)
+		if err != nil {
+			return n, err
+		}
+		proof.DepthExtensionPresent = append(proof.DepthExtensionPresent, b[0])
+	}
+
+	if l, err = readLength(); err != nil {
+		return n, err
+	}
+	proof.CommitmentsByPath = nil
+	for i := 0; i < l; i++ {
+		var c banderwagon.Element
+		m, err := readElement(r, &c)
+		n += m
+		if err != nil {
+			return n, err
+		}
+		proof.CommitmentsByPath = append(proof.CommitmentsByPath, c)
+	}
+
+	m, err := proof.Multiproof.ReadFrom(r)
+	return n + m, err
+}
+
+// WriteTo writes D, the L and R of the inner product argument, and its final
+// scalar A in little-endian.
+func (proof *MultiProof) WriteTo(w io.Writer) (int64, error) {
+	buf := make([]byte, 0, (2+2*nbRounds)*banderwagon.Bytes)
+	points := make([]banderwagon.Element, 0, 1+2*nbRounds)
+	points = append(points, proof.D)
+	points = append(points, proof.IPA.L[:]...)
+	points = append(points, proof.IPA.R[:]...)
+	for i := range points {
+		b := points[i].Bytes()
+		buf = append(buf, b[:]...)
+	}
+	var a [fr.Bytes]byte
+	fr.LittleEndian.PutElement(&a, proof.IPA.A)
+	buf = append(buf, a[:]...)
+
+	n, err := w.Write(buf)
+	return int64(n), err
+}
+
+// ReadFrom reads a multiproof written by WriteTo
+func (proof *MultiProof) ReadFrom(r io.Reader) (int64, error) {
+	var n int64
+	points := make([]*banderwagon.Element, 0, 1+2*nbRounds)
+	points = append(points, &proof.D)
+	for i := range proof.IPA.L {
+		points = append(points, &proof.IPA.L[i])
+	}
+	for i := range proof.IPA.R {
+		points = append(points, &proof.IPA.R[i])
+	}
+	for i := range points {
+		m, err := readElement(r, points[i])
+		n += m
+		if err != nil {
+			return n, err
+		}
+	}
+
+	var a [fr.Bytes]byte
+	m, err := io.ReadFull(r, a[:])
+	n += int64(m)
+	if err != nil {
+		return n, err
+	}
+	proof.IPA.A, err = fr.LittleEndian.Element(&a)
+	return n, err
+}
+
+func readElement(r io.Reader, e *banderwagon.Element) (int64, error) {
+	var b [banderwagon.Bytes]byte
+	m, err := io.ReadFull(r, b[:])
+	if err != nil {
+		return int64(m), err
+	}
+	return int64(m), e.SetBytes(b[:])
+}
@@ -0,0 +1,159 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verkle
+
+import (
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch/banderwagon"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch/fr"
+)
+
+// MultiProof proves the evaluations yᵢ = fᵢ(zᵢ) of polynomials committed in
+// Cᵢ, with zᵢ in the domain.
+//
+// With a challenge r, the prover commits to
+//
+//	g(X) = ∑ᵢ rⁱ(fᵢ(X) - yᵢ)/(X - zᵢ)
+//
+// in D, and with a challenge t proves with an inner product argument that
+// h(X) - g(X), where h(X) = ∑ᵢ rⁱfᵢ(X)/(t - zᵢ), evaluates at t to
+// ∑ᵢ rⁱyᵢ/(t - zᵢ). The verifier computes the commitment ∑ᵢ [rⁱ/(t - zᵢ)]Cᵢ
+// of h.
+type MultiProof struct {
+	IPA IPAProof
+	D   banderwagon.Element
+}
+
+// createMultiProof proves that fs[i] evaluates at zs[i] to fs[i][zs[i]]
+func createMultiProof(tr *transcript, c *config, cs []*banderwagon.Element, fs [][]fr.Element, zs []uint8) MultiProof {
+	tr.domainSep("multiproof")
+	for i := range cs {
+		tr.appendPoint(cs[i], "C")
+		var z fr.Element
+		z.SetUint64(uint64(zs[i]))
+		tr.appendScalar(&z, "z")
+		tr.appendScalar(&fs[i][zs[i]], "y")
+	}
+	r := tr.challengeScalar("r")
+
+	// the polynomials are aggregated by evaluation point: ∑_{zᵢ=z} rⁱfᵢ
+	var aggregated [NodeWidth][]fr.Element
+	var rPow, t fr.Element
+	rPow.SetOne()
+	for i := range fs {
+		z := zs[i]
+		if aggregated[z] == nil {
+			aggregated[z] = make([]fr.Element, NodeWidth)
+		}
+		for j := range fs[i] {
+			t.Mul(&fs[i][j], &rPow)
+			aggregated[z][j].Add(&aggregated[z][j], &t)
+		}
+		rPow.Mul(&rPow, &r)
+	}
+
+	g := make([]fr.Element, NodeWidth)
+	for z := range aggregated {
+		if aggregated[z] == nil {
+			continue
+		}
+		q := c.divideOnDomain(uint8(z), aggregated[z])
+		for j := range g {
+			g[j].Add(&g[j], &q[j])
+		}
+	}
+
+	var res MultiProof
+	res.D = c.commit(g)
+	tr.appendPoint(&res.D, "D")
+	tPoint := tr.challengeScalar("t")
+
+	// h(X) = ∑_z 1/(t - z) ∑_{zᵢ=z} rⁱfᵢ(X)
+	h := make([]fr.Element, NodeWidth)
+	for z := range aggregated {
+		if aggregated[z] == nil {
+			continue
+		}
+		var den fr.Element
+		den.SetUint64(uint64(z))
+		den.Sub(&tPoint, &den).Inverse(&den)
+		for j := range h {
+			t.Mul(&aggregated[z][j], &den)
+			h[j].Add(&h[j], &t)
+		}
+	}
+
+	e := c.commit(h)
+	tr.appendPoint(&e, "E")
+
+	var eMinusD banderwagon.Element
+	eMinusD.Sub(&e, &res.D)
+	for j := range h {
+		h[j].Sub(&h[j], &g[j])
+	}
+	res.IPA = createIPAProof(tr, c, &eMinusD, h, tPoint)
+
+	return res
+}
+
+// checkMultiProof verifies that the polynomials committed in cs[i] evaluate at
+// zs[i] to ys[i].
+func checkMultiProof(tr *transcript, c *config, proof *MultiProof, cs []*banderwagon.Element, ys []fr.Element, zs []uint8) bool {
+	tr.domainSep("multiproof")
+	for i := range cs {
+		tr.appendPoint(cs[i], "C")
+		var z fr.Element
+		z.SetUint64(uint64(zs[i]))
+		tr.appendScalar(&z, "z")
+		tr.appendScalar(&ys[i], "y")
+	}
+	r := tr.challengeScalar("r")
+	tr.appendPoint(&proof.D, "D")
+	t := tr.challengeScalar("t")
+
+	// rⁱ/(t - zᵢ)
+	coeffs := make([]fr.Element, len(cs))
+	for i := range coeffs {
+		coeffs[i].SetUint64(uint64(zs[i]))
+		coeffs[i].Sub(&t, &coeffs[i])
+	}
+	coeffs = fr.BatchInvert(coeffs)
+	var rPow fr.Element
+	rPow.SetOne()
+	for i := range coeffs {
+		coeffs[i].Mul(&coeffs[i], &rPow)
+		rPow.Mul(&rPow, &r)
+	}
+
+	// E = ∑ᵢ [rⁱ/(t - zᵢ)]Cᵢ and h(t) - g(t) = ∑ᵢ rⁱyᵢ/(t - zᵢ)
+	points := make([]banderwagon.Element, len(cs))
+	for i := range cs {
+		points[i] = *cs[i]
+	}
+	e, err := banderwagon.MultiExp(points, coeffs)
+	if err != nil {
+		return false
+	}
+	var y fr.Element
+	for i := range ys {
+		var tmp fr.Element
+		tmp.Mul(&coeffs[i], &ys[i])
+		y.Add(&y, &tmp)
+	}
+	tr.appendPoint(&e, "E")
+
+	var eMinusD banderwagon.Element
+	eMinusD.Sub(&e, &proof.D)
+	return checkIPAProof(tr, c, &eMinusD, &proof.IPA, t, y)
+}
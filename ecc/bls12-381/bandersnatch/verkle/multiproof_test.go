@@ -0,0 +1,93 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verkle
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch/banderwagon"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch/fr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIPA(t *testing.T) {
+	assert := require.New(t)
+	c := getConfig()
+
+	a := randomEvaluations()
+	commitment := c.commit(a)
+	var z fr.Element
+	z.SetRandom()
+	y := innerProduct(a, c.lagrangeCoefficients(z))
+
+	proof := createIPAProof(newTranscript("test"), c, &commitment, a, z)
+	assert.True(checkIPAProof(newTranscript("test"), c, &commitment, &proof, z, y))
+
+	// wrong transcript, value or proof
+	assert.False(checkIPAProof(newTranscript("other"), c, &commitment, &proof, z, y))
+	var wrongY fr.Element
+	wrongY.Double(&y)
+	assert.False(checkIPAProof(newTranscript("test"), c, &commitment, &proof, z, wrongY))
+	wrongProof := proof
+	wrongProof.L[3] = proof.R[3]
+	assert.False(checkIPAProof(newTranscript("test"), c, &commitment, &wrongProof, z, y))
+}
+
+func TestMultiProof(t *testing.T) {
+	assert := require.New(t)
+	c := getConfig()
+
+	// several openings of the same polynomial, and at the same point
+	const n = 5
+	fs := make([][]fr.Element, n)
+	cs := make([]*banderwagon.Element, n)
+	for i := range fs {
+		fs[i] = randomEvaluations()
+		commitment := c.commit(fs[i])
+		cs[i] = &commitment
+	}
+	fs = append(fs, fs[0], fs[1])
+	cs = append(cs, cs[0], cs[1])
+	zs := []uint8{0, 3, 3, 255, 128, 1, 0}
+	ys := make([]fr.Element, len(fs))
+	for i := range ys {
+		ys[i] = fs[i][zs[i]]
+	}
+
+	proof := createMultiProof(newTranscript("test"), c, cs, fs, zs)
+	assert.True(checkMultiProof(newTranscript("test"), c, &proof, cs, ys, zs))
+
+	// wrong evaluation
+	ys[2].SetOne()
+	assert.False(checkMultiProof(newTranscript("test"), c, &proof, cs, ys, zs))
+	ys[2] = fs[2][zs[2]]
+
+	// wrong point
+	zs[4]++
+	assert.False(checkMultiProof(newTranscript("test"), c, &proof, cs, ys, zs))
+	zs[4]--
+
+	// serialization
+	var buf bytes.Buffer
+	written, err := proof.WriteTo(&buf)
+	assert.NoError(err)
+	assert.Equal(int64((2+2*nbRounds)*banderwagon.Bytes), written)
+	var read MultiProof
+	nRead, err := read.ReadFrom(&buf)
+	assert.NoError(err)
+	assert.Equal(written, nRead)
+	assert.True(checkMultiProof(newTranscript("test"), c, &read, cs, ys, zs))
+}
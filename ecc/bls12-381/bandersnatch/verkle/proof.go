@@ -0,0 +1,491 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verkle
+
+import (
+	"bytes"
+	"errors"
+	"sort"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch/banderwagon"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch/fr"
+)
+
+var (
+	ErrNoKeys        = errors.New("no keys to prove")
+	ErrNbValues      = errors.New("the number of values should be the number of keys")
+	ErrInvalidProof  = errors.New("invalid proof")
+	ErrVerifyProof   = errors.New("can't verify the multiproof")
+	ErrDuplicatedKey = errors.New("a key has two different values")
+)
+
+// extension statuses of a stem in a proof, in the 3 least significant bits of
+// its byte, the 5 others being the depth of the stem in the tree
+const (
+	extStatusAbsentEmpty = 0 // the stem ends in an empty node
+	extStatusAbsentOther = 1 // the stem ends in a leaf of another stem
+	extStatusPresent     = 2 // the stem ends in its leaf
+)
+
+// transcriptLabel label of the transcript of the proofs
+const transcriptLabel = "vt"
+
+// Proof proves the values, possibly absent, of a set of keys in a tree of a
+// given root commitment.
+type Proof struct {
+	// OtherStems stems of the leaves proving the absence of the stems of keys
+	// which end in them, in the order of the paths
+	OtherStems [][StemSize]byte
+
+	// DepthExtensionPresent depth and extension status of each stem of the
+	// sorted keys
+	DepthExtensionPresent []byte
+
+	// CommitmentsByPath commitments of the nodes on the paths of the keys,
+	// the root excepted, sorted by path. The path of C₁ and C₂ is the path of
+	// their leaf followed by 2 or 3.
+	CommitmentsByPath []banderwagon.Element
+
+	// Multiproof opening of all the commitments
+	Multiproof MultiProof
+}
+
+// proofElements openings of the commitments of a proof, in the order in which
+// they are bound in the transcript.
+//
+// An internal node opens its commitment at the index of each child on the
+// paths, and then its children are visited in order. A leaf opens its
+// commitment at 0 and 1, at 2 and 3 if a key has a suffix in C₁ and C₂, and
+// then C₁ or C₂ at the two positions of each suffix.
+type proofElements struct {
+	cs []*banderwagon.Element
+	zs []uint8
+	ys []fr.Element
+	fs [][]fr.Element // the polynomials, computed by the prover only
+
+	prover     bool
+	byPath     map[string]*banderwagon.Element
+	extStatus  []byte
+	otherStems [][StemSize]byte
+}
+
+func newProofElements(prover bool) *proofElements {
+	return &proofElements{prover: prover, byPath: make(map[string]*banderwagon.Element)}
+}
+
+func (pe *proofElements) add(c *banderwagon.Element, z uint8, y fr.Element, f []fr.Element) {
+	pe.cs = append(pe.cs, c)
+	pe.zs = append(pe.zs, z)
+	pe.ys = append(pe.ys, y)
+	if pe.prover {
+		pe.fs = append(pe.fs, f)
+	}
+}
+
+// commitments returns the commitments sorted by path, the root excepted
+func (pe *proofElements) commitments() []banderwagon.Element {
+	paths := make([]string, 0, len(pe.byPath))
+	for path := range pe.byPath {
+		if path != "" {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	res := make([]banderwagon.Element, len(paths))
+	for i := range paths {
+		res[i] = *pe.byPath[paths[i]]
+	}
+	return res
+}
+
+func (n *internalNode) proofItems(keys [][]byte, pe *proofElements) {
+	pe.byPath[string(keys[0][:n.depth])] = &n.commitment
+
+	groups := groupKeys(keys, n.depth)
+	var f []fr.Element
+	if pe.prover {
+		f = n.evaluations()
+	}
+	for _, group := range groups {
+		i := group[0][n.depth]
+		var y fr.Element
+		if n.children[i] != nil {
+			y = n.children[i].commit().MapToScalarField()
+		}
+		pe.add(&n.commitment, i, y, f)
+	}
+
+	for _, group := range groups {
+		switch child := n.children[group[0][n.depth]].(type) {
+		case nil:
+			for range stems(group) {
+				pe.extStatus = append(pe.extStatus, extStatusAbsentEmpty|byte(n.depth+1)<<3)
+			}
+		case *internalNode:
+			child.proofItems(group, pe)
+		case *leafNode:
+			child.proofItems(group, pe)
+		}
+	}
+}
+
+func (n *leafNode) proofItems(keys [][]byte, pe *proofElements) {
+	pe.byPath[string(n.stem[:n.depth])] = &n.commitment
+
+	var f []fr.Element
+	if pe.prover {
+		f = n.evaluations()
+	}
+	var one fr.Element
+	one.SetOne()
+	pe.add(&n.commitment, 0, one, f)
+	pe.add(&n.commitment, 1, stemToScalar(n.stem[:]), f)
+
+	var hasC [2]bool
+	for _, key := range keys {
+		if n.hasStem(key) {
+			hasC[key[StemSize]/128] = true
+		}
+	}
+	for h := range hasC {
+		if hasC[h] {
+			pe.add(&n.commitment, uint8(2+h), n.suffixCommitment(h).MapToScalarField(), f)
+		}
+	}
+
+	present := false
+	for _, stem := range stems(keys) {
+		if n.hasStem(stem) {
+			present = true
+			pe.extStatus = append(pe.extStatus, extStatusPresent|byte(n.depth)<<3)
+		} else {
+			pe.extStatus = append(pe.extStatus, extStatusAbsentOther|byte(n.depth)<<3)
+		}
+	}
+	if !present {
+		pe.otherStems = append(pe.otherStems, n.stem)
+	}
+
+	var suffixF [2][]fr.Element
+	for _, key := range keys {
+		if !n.hasStem(key) {
+			continue
+		}
+		suffix := key[StemSize]
+		h := int(suffix / 128)
+		if pe.prover && suffixF[h] == nil {
+			suffixF[h] = n.suffixEvaluations(h)
+		}
+		c := n.suffixCommitment(h)
+		lo, hi := valueToScalars(n.values[suffix])
+		slot := 2 * (suffix % 128)
+		pe.add(c, slot, lo, suffixF[h])
+		pe.add(c, slot+1, hi, suffixF[h])
+		pe.byPath[string(n.stem[:n.depth])+string([]byte{byte(2 + h)})] = c
+	}
+}
+
+func (n *leafNode) hasStem(key []byte) bool {
+	return bytes.Equal(n.stem[:], key[:StemSize])
+}
+
+// suffixCommitment returns C₁ if h = 0, C₂ if h = 1
+func (n *leafNode) suffixCommitment(h int) *banderwagon.Element {
+	if h == 0 {
+		return &n.c1
+	}
+	return &n.c2
+}
+
+// groupKeys splits sorted keys in groups of same byte at depth
+func groupKeys(keys [][]byte, depth int) [][][]byte {
+	var res [][][]byte
+	start := 0
+	for i := 1; i <= len(keys); i++ {
+		if i == len(keys) || keys[i][depth] != keys[start][depth] {
+			res = append(res, keys[start:i])
+			start = i
+		}
+	}
+	return res
+}
+
+// stems returns the distinct stems of sorted keys
+func stems(keys [][]byte) [][]byte {
+	var res [][]byte
+	for _, key := range keys {
+		if len(res) == 0 || !bytes.Equal(res[len(res)-1], key[:StemSize]) {
+			res = append(res, key[:StemSize])
+		}
+	}
+	return res
+}
+
+// Prove returns a proof of the values of keys in the tree, and these values,
+// nil for the absent keys. It updates the commitments of the tree.
+func (t *Tree) Prove(keys [][]byte) (*Proof, [][]byte, error) {
+	if len(keys) == 0 {
+		return nil, nil, ErrNoKeys
+	}
+	values := make([][]byte, len(keys))
+	for i := range keys {
+		var err error
+		if values[i], err = t.Get(keys[i]); err != nil {
+			return nil, nil, err
+		}
+	}
+	sorted, _, err := sortKeys(keys, values)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	t.Commit()
+	pe := newProofElements(true)
+	t.root.proofItems(sorted, pe)
+
+	proof := &Proof{
+		OtherStems:            pe.otherStems,
+		DepthExtensionPresent: pe.extStatus,
+		CommitmentsByPath:     pe.commitments(),
+		Multiproof:            createMultiProof(newTranscript(transcriptLabel), getConfig(), pe.cs, pe.fs, pe.zs),
+	}
+	return proof, values, nil
+}
+
+// Verify verifies a proof that the keys have the given values in the tree of
+// root commitment root, a nil value meaning that the key is absent.
+//
+// The verifier rebuilds the part of the tree on the paths of the keys from
+// the extension statuses and the commitments of the proof, and checks the
+// openings of the commitments with the multiproof.
+func Verify(proof *Proof, root *banderwagon.Element, keys, values [][]byte) error {
+	if len(keys) == 0 {
+		return ErrNoKeys
+	}
+	if len(keys) != len(values) {
+		return ErrNbValues
+	}
+	for i := range keys {
+		if len(keys[i]) != KeySize {
+			return ErrInvalidKeySize
+		}
+		if values[i] != nil && len(values[i]) != ValueSize {
+			return ErrInvalidValueSize
+		}
+	}
+	keys, values, err := sortKeys(keys, values)
+	if err != nil {
+		return err
+	}
+
+	// the paths of the commitments
+	_stems := stems(keys)
+	if len(proof.DepthExtensionPresent) != len(_stems) {
+		return ErrInvalidProof
+	}
+	paths := make(map[string]*banderwagon.Element)
+	for i, stem := range _stems {
+		depth, status := int(proof.DepthExtensionPresent[i]>>3), proof.DepthExtensionPresent[i]&7
+		if depth < 1 || depth > StemSize || status > extStatusPresent {
+			return ErrInvalidProof
+		}
+		for d := 1; d < depth; d++ {
+			paths[string(stem[:d])] = nil
+		}
+		if status != extStatusAbsentEmpty {
+			paths[string(stem[:depth])] = nil
+		}
+	}
+	for _, key := range keys {
+		i := sort.Search(len(_stems), func(i int) bool { return bytes.Compare(_stems[i], key[:StemSize]) >= 0 })
+		depth := int(proof.DepthExtensionPresent[i] >> 3)
+		if proof.DepthExtensionPresent[i]&7 == extStatusPresent {
+			paths[string(key[:depth])+string([]byte{2 + key[StemSize]/128})] = nil
+		}
+	}
+	if len(paths) != len(proof.CommitmentsByPath) {
+		return ErrInvalidProof
+	}
+	sortedPaths := make([]string, 0, len(paths))
+	for path := range paths {
+		sortedPaths = append(sortedPaths, path)
+	}
+	sort.Strings(sortedPaths)
+	commitments := make([]banderwagon.Element, len(proof.CommitmentsByPath))
+	copy(commitments, proof.CommitmentsByPath)
+	for i, path := range sortedPaths {
+		paths[path] = &commitments[i]
+	}
+
+	// the partial tree
+	rootNode := &internalNode{commitment: *root}
+	internal := func(path []byte) (*internalNode, error) {
+		n := rootNode
+		for d := 1; d <= len(path); d++ {
+			switch child := n.children[path[d-1]].(type) {
+			case nil:
+				next := &internalNode{depth: d, commitment: *paths[string(path[:d])]}
+				n.children[path[d-1]] = next
+				n = next
+			case *internalNode:
+				n = child
+			default:
+				return nil, ErrInvalidProof
+			}
+		}
+		return n, nil
+	}
+	newLeaf := func(stem []byte, depth int) *leafNode {
+		leaf := &leafNode{depth: depth, commitment: *paths[string(stem[:depth])]}
+		copy(leaf.stem[:], stem)
+		if c := paths[string(stem[:depth])+"\x02"]; c != nil {
+			leaf.c1 = *c
+		}
+		if c := paths[string(stem[:depth])+"\x03"]; c != nil {
+			leaf.c2 = *c
+		}
+		return leaf
+	}
+
+	// the leaves of the present stems first, as they prove the absence of the
+	// other stems ending in them
+	for i, stem := range _stems {
+		depth := int(proof.DepthExtensionPresent[i] >> 3)
+		if proof.DepthExtensionPresent[i]&7 != extStatusPresent {
+			continue
+		}
+		parent, err := internal(stem[:depth-1])
+		if err != nil {
+			return err
+		}
+		if parent.children[stem[depth-1]] != nil {
+			return ErrInvalidProof
+		}
+		parent.children[stem[depth-1]] = newLeaf(stem, depth)
+	}
+	for i, key := range keys {
+		if values[i] == nil {
+			continue
+		}
+		j := sort.Search(len(_stems), func(j int) bool { return bytes.Compare(_stems[j], key[:StemSize]) >= 0 })
+		if proof.DepthExtensionPresent[j]&7 != extStatusPresent {
+			return ErrInvalidProof
+		}
+	}
+
+	nbOtherStems := 0
+	var empty [][]byte
+	for i, stem := range _stems {
+		depth := int(proof.DepthExtensionPresent[i] >> 3)
+		status := proof.DepthExtensionPresent[i] & 7
+		if status == extStatusPresent {
+			continue
+		}
+		parent, err := internal(stem[:depth-1])
+		if err != nil {
+			return err
+		}
+		if status == extStatusAbsentEmpty {
+			empty = append(empty, stem[:depth])
+			continue
+		}
+		switch child := parent.children[stem[depth-1]].(type) {
+		case nil:
+			if nbOtherStems >= len(proof.OtherStems) {
+				return ErrInvalidProof
+			}
+			other := proof.OtherStems[nbOtherStems][:]
+			nbOtherStems++
+			if !bytes.Equal(other[:depth], stem[:depth]) || bytes.Equal(other, stem) {
+				return ErrInvalidProof
+			}
+			parent.children[stem[depth-1]] = newLeaf(other, depth)
+		case *leafNode:
+			if child.depth != depth {
+				return ErrInvalidProof
+			}
+		default:
+			return ErrInvalidProof
+		}
+	}
+	if nbOtherStems != len(proof.OtherStems) {
+		return ErrInvalidProof
+	}
+	for _, path := range empty {
+		parent, err := internal(path[:len(path)-1])
+		if err != nil {
+			return err
+		}
+		if parent.children[path[len(path)-1]] != nil {
+			return ErrInvalidProof
+		}
+	}
+
+	// the values of the present keys
+	for i, key := range keys {
+		if values[i] == nil {
+			continue
+		}
+		n := rootNode
+		for {
+			child := n.children[key[n.depth]]
+			if leaf, ok := child.(*leafNode); ok {
+				leaf.values[key[StemSize]] = values[i]
+				break
+			}
+			n = child.(*internalNode)
+		}
+	}
+
+	// the openings, which must follow the structure of the proof
+	pe := newProofElements(false)
+	rootNode.proofItems(keys, pe)
+	if !bytes.Equal(pe.extStatus, proof.DepthExtensionPresent) || len(pe.byPath)-1 != len(commitments) {
+		return ErrInvalidProof
+	}
+	if !checkMultiProof(newTranscript(transcriptLabel), getConfig(), &proof.Multiproof, pe.cs, pe.ys, pe.zs) {
+		return ErrVerifyProof
+	}
+	return nil
+}
+
+// sortKeys returns the sorted keys without duplicates, and their values
+func sortKeys(keys, values [][]byte) ([][]byte, [][]byte, error) {
+	for i := range keys {
+		if len(keys[i]) != KeySize {
+			return nil, nil, ErrInvalidKeySize
+		}
+	}
+	indices := make([]int, len(keys))
+	for i := range indices {
+		indices[i] = i
+	}
+	sort.SliceStable(indices, func(i, j int) bool {
+		return bytes.Compare(keys[indices[i]], keys[indices[j]]) < 0
+	})
+	sortedKeys := make([][]byte, 0, len(keys))
+	sortedValues := make([][]byte, 0, len(keys))
+	for _, i := range indices {
+		if n := len(sortedKeys); n > 0 && bytes.Equal(sortedKeys[n-1], keys[i]) {
+			if !bytes.Equal(sortedValues[n-1], values[i]) || (sortedValues[n-1] == nil) != (values[i] == nil) {
+				return nil, nil, ErrDuplicatedKey
+			}
+			continue
+		}
+		sortedKeys = append(sortedKeys, keys[i])
+		sortedValues = append(sortedValues, values[i])
+	}
+	return sortedKeys, sortedValues, nil
+}
@@ -0,0 +1,90 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command goverkle prints the vectors of TestVectors, computed with go-verkle
+// v0.2.2 (github.com/ethereum/go-verkle, tree.go and proof_ipa.go): the root
+// commitment of the tree and the sha256 of the serialized multi proofs.
+//
+// Run it from this directory with "go run ."; the proofs are serialized as
+// Proof.WriteTo does.
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+
+	verkle "github.com/ethereum/go-verkle"
+)
+
+func key(suffix byte, prefix ...byte) []byte {
+	res := make([]byte, 32)
+	copy(res, prefix)
+	res[31] = suffix
+	return res
+}
+
+func value(b byte) []byte {
+	res := make([]byte, 32)
+	for i := range res {
+		res[i] = b + byte(i)
+	}
+	return res
+}
+
+func main() {
+	root := verkle.New()
+	keys := [][]byte{key(0), key(1), key(200), key(0, 1), key(0, 1, 2), key(0, 1, 2, 3), key(5, 0xff)}
+	for i := range keys {
+		if err := root.Insert(keys[i], value(byte(i)), nil); err != nil {
+			panic(err)
+		}
+	}
+	c := root.Commit()
+	cb := c.Bytes()
+	fmt.Println("root", hex.EncodeToString(cb[:]))
+
+	for _, toProve := range [][][]byte{
+		{keys[2], keys[0], keys[4]},
+		{key(3), key(250)},
+		{key(0, 3)},
+		{key(0, 0xff, 1), key(5, 0xff)},
+		{key(0, 0xff, 1)},
+		append([][]byte{key(0, 3), key(9, 0, 1, 2, 4), key(3), key(0, 0xff, 1)}, keys...),
+	} {
+		proof, _, _, _, err := verkle.MakeVerkleMultiProof(root, nil, toProve, nil)
+		if err != nil {
+			panic(err)
+		}
+		var buf bytes.Buffer
+		binary.Write(&buf, binary.LittleEndian, uint32(len(proof.PoaStems)))
+		for _, s := range proof.PoaStems {
+			buf.Write(s)
+		}
+		binary.Write(&buf, binary.LittleEndian, uint32(len(proof.ExtStatus)))
+		buf.Write(proof.ExtStatus)
+		binary.Write(&buf, binary.LittleEndian, uint32(len(proof.Cs)))
+		for _, c := range proof.Cs {
+			b := c.Bytes()
+			buf.Write(b[:])
+		}
+		if err := proof.Multipoint.Write(&buf); err != nil {
+			panic(err)
+		}
+		h := sha256.Sum256(buf.Bytes())
+		fmt.Println("proof", hex.EncodeToString(h[:]))
+	}
+}
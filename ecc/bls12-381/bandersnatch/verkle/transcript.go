@@ -0,0 +1,71 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verkle
+
+import (
+	"crypto/sha256"
+	"hash"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch/banderwagon"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch/fr"
+)
+
+// transcript Fiat Shamir transcript of the specification, which differs from
+// the one of the fiat-shamir package: the labels are hashed with the data, the
+// scalars are little-endian, and the state is reset to the challenge once it
+// is derived.
+type transcript struct {
+	state hash.Hash
+}
+
+func newTranscript(label string) *transcript {
+	t := &transcript{state: sha256.New()}
+	t.domainSep(label)
+	return t
+}
+
+// domainSep binds a label separating the parts of the protocol
+func (t *transcript) domainSep(label string) {
+	t.state.Write([]byte(label))
+}
+
+func (t *transcript) appendScalar(s *fr.Element, label string) {
+	var b [fr.Bytes]byte
+	fr.LittleEndian.PutElement(&b, *s)
+	t.state.Write([]byte(label))
+	t.state.Write(b[:])
+}
+
+func (t *transcript) appendPoint(p *banderwagon.Element, label string) {
+	b := p.Bytes()
+	t.state.Write([]byte(label))
+	t.state.Write(b[:])
+}
+
+// challengeScalar derives a challenge from the state, interpreted in little-
+// endian and reduced, and resets the state to it.
+func (t *transcript) challengeScalar(label string) fr.Element {
+	t.domainSep(label)
+	h := t.state.Sum(nil)
+	for i, j := 0, len(h)-1; i < j; i, j = i+1, j-1 {
+		h[i], h[j] = h[j], h[i]
+	}
+	var res fr.Element
+	res.SetBytes(h)
+
+	t.state.Reset()
+	t.appendScalar(&res, label)
+	return res
+}
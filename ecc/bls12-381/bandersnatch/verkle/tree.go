@@ -0,0 +1,218 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verkle
+
+import (
+	"errors"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch/banderwagon"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch/fr"
+)
+
+var (
+	ErrInvalidKeySize   = errors.New("the size of a key should be 32 bytes")
+	ErrInvalidValueSize = errors.New("the size of a value should be 32 bytes")
+)
+
+// Tree Verkle tree, whose commitments are updated lazily by Commit
+type Tree struct {
+	root *internalNode
+}
+
+// node internal node or leaf, nil being the empty node
+type node interface {
+	// commit updates the commitment of the node if it was modified, and
+	// returns it
+	commit() *banderwagon.Element
+}
+
+type internalNode struct {
+	children   [NodeWidth]node
+	depth      int // number of bytes of the stems indexing the node
+	commitment banderwagon.Element
+	dirty      bool
+}
+
+// leafNode extension node holding the values of a stem
+type leafNode struct {
+	stem       [StemSize]byte
+	values     [NodeWidth][]byte // nil for absent values
+	depth      int
+	commitment banderwagon.Element
+	c1, c2     banderwagon.Element
+	dirty      bool
+}
+
+// New returns an empty tree
+func New() *Tree {
+	root := &internalNode{}
+	root.commitment.SetIdentity()
+	return &Tree{root: root}
+}
+
+// Insert sets the value of key, inserting it if it isn't in the tree.
+func (t *Tree) Insert(key, value []byte) error {
+	if len(key) != KeySize {
+		return ErrInvalidKeySize
+	}
+	if len(value) != ValueSize {
+		return ErrInvalidValueSize
+	}
+	var stem [StemSize]byte
+	copy(stem[:], key)
+	v := make([]byte, ValueSize)
+	copy(v, value)
+
+	n := t.root
+	for {
+		n.dirty = true
+		i := stem[n.depth]
+		switch child := n.children[i].(type) {
+		case nil:
+			leaf := &leafNode{stem: stem, depth: n.depth + 1, dirty: true}
+			leaf.values[key[StemSize]] = v
+			n.children[i] = leaf
+			return nil
+		case *internalNode:
+			n = child
+		case *leafNode:
+			if child.stem == stem {
+				child.values[key[StemSize]] = v
+				child.dirty = true
+				return nil
+			}
+			// the stems share at least one more byte: the leaf moves down
+			// to a new internal node, whose commitment is computed lazily
+			branch := &internalNode{depth: n.depth + 1}
+			child.depth++
+			branch.children[child.stem[branch.depth]] = child
+			n.children[i] = branch
+			n = branch
+		}
+	}
+}
+
+// Get returns the value of key, or nil if it isn't in the tree.
+func (t *Tree) Get(key []byte) ([]byte, error) {
+	if len(key) != KeySize {
+		return nil, ErrInvalidKeySize
+	}
+	n := t.root
+	for {
+		switch child := n.children[key[n.depth]].(type) {
+		case nil:
+			return nil, nil
+		case *internalNode:
+			n = child
+		case *leafNode:
+			if string(child.stem[:]) != string(key[:StemSize]) || child.values[key[StemSize]] == nil {
+				return nil, nil
+			}
+			res := make([]byte, ValueSize)
+			copy(res, child.values[key[StemSize]])
+			return res, nil
+		}
+	}
+}
+
+// Commit returns the commitment of the root of the tree, updating the
+// commitments of the nodes modified since the last call.
+func (t *Tree) Commit() banderwagon.Element {
+	return *t.root.commit()
+}
+
+func (n *internalNode) commit() *banderwagon.Element {
+	if n.dirty {
+		values := n.evaluations()
+		n.commitment = getConfig().commit(values)
+		n.dirty = false
+	}
+	return &n.commitment
+}
+
+// evaluations returns the maps to the scalar field of the commitments of the
+// children, which must be up to date.
+func (n *internalNode) evaluations() []fr.Element {
+	var points []banderwagon.Element
+	var indices []int
+	for i, child := range n.children {
+		if child != nil {
+			points = append(points, *child.commit())
+			indices = append(indices, i)
+		}
+	}
+	hashes := make([]fr.Element, len(points))
+	banderwagon.BatchMapToScalarField(hashes, points)
+	res := make([]fr.Element, NodeWidth)
+	for i := range indices {
+		res[indices[i]] = hashes[i]
+	}
+	return res
+}
+
+func (n *leafNode) commit() *banderwagon.Element {
+	if n.dirty {
+		c := getConfig()
+		n.c1 = c.commit(n.suffixEvaluations(0))
+		n.c2 = c.commit(n.suffixEvaluations(1))
+		n.commitment = c.commit(n.evaluations())
+		n.dirty = false
+	}
+	return &n.commitment
+}
+
+// evaluations returns (1, stem, C₁, C₂, 0, ...), which requires C₁ and C₂ to
+// be up to date.
+func (n *leafNode) evaluations() []fr.Element {
+	res := make([]fr.Element, NodeWidth)
+	res[0].SetOne()
+	res[1] = stemToScalar(n.stem[:])
+	banderwagon.BatchMapToScalarField(res[2:4], []banderwagon.Element{n.c1, n.c2})
+	return res
+}
+
+// suffixEvaluations returns the values of the suffixes 128⋅half to
+// 128⋅half+127 committed in C₁ or C₂.
+func (n *leafNode) suffixEvaluations(half int) []fr.Element {
+	res := make([]fr.Element, NodeWidth)
+	for i := 0; i < NodeWidth/2; i++ {
+		res[2*i], res[2*i+1] = valueToScalars(n.values[NodeWidth/2*half+i])
+	}
+	return res
+}
+
+// stemToScalar interprets the stem in little-endian
+func stemToScalar(stem []byte) fr.Element {
+	var b [fr.Bytes]byte
+	copy(b[:], stem)
+	res, _ := fr.LittleEndian.Element(&b) // 31 bytes are always canonical
+	return res
+}
+
+// valueToScalars returns the low and high 16 bytes of the value in
+// little-endian, the low one being marked with 2¹²⁸. An absent value is (0, 0).
+func valueToScalars(value []byte) (lo, hi fr.Element) {
+	if value == nil {
+		return
+	}
+	var b [fr.Bytes]byte
+	copy(b[:16], value[:16])
+	b[16] = 1
+	lo, _ = fr.LittleEndian.Element(&b)
+	b = [fr.Bytes]byte{}
+	copy(b[:16], value[16:])
+	hi, _ = fr.LittleEndian.Element(&b)
+	return
+}
@@ -0,0 +1,295 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verkle
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch/banderwagon"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch/fr"
+	"github.com/stretchr/testify/require"
+)
+
+// key returns a key whose first bytes are prefix and whose suffix is suffix
+func key(suffix byte, prefix ...byte) []byte {
+	res := make([]byte, KeySize)
+	copy(res, prefix)
+	res[StemSize] = suffix
+	return res
+}
+
+func value(b byte) []byte {
+	res := make([]byte, ValueSize)
+	for i := range res {
+		res[i] = b + byte(i)
+	}
+	return res
+}
+
+func TestInsertGet(t *testing.T) {
+	assert := require.New(t)
+
+	tree := New()
+	empty := tree.Commit()
+	assert.True(empty.IsIdentity())
+
+	keys := [][]byte{key(0), key(1), key(200), key(0, 1), key(0, 1, 2), key(0, 1, 2, 3), key(5, 0xff)}
+	for i := range keys {
+		assert.NoError(tree.Insert(keys[i], value(byte(i))))
+	}
+	for i := range keys {
+		v, err := tree.Get(keys[i])
+		assert.NoError(err)
+		assert.Equal(value(byte(i)), v)
+	}
+	v, err := tree.Get(key(0, 1, 2, 4))
+	assert.NoError(err)
+	assert.Nil(v)
+	v, err = tree.Get(key(2))
+	assert.NoError(err)
+	assert.Nil(v)
+
+	// the commitment doesn't depend on the order of the insertions
+	c := tree.Commit()
+	other := New()
+	for i := len(keys) - 1; i >= 0; i-- {
+		assert.NoError(other.Insert(keys[i], value(byte(i))))
+	}
+	otherC := other.Commit()
+	assert.True(c.Equal(&otherC))
+
+	// update, and lazy commitment
+	assert.NoError(tree.Insert(keys[3], value(42)))
+	updated := tree.Commit()
+	assert.False(updated.Equal(&c))
+	assert.NoError(other.Insert(keys[3], value(42)))
+	otherC = other.Commit()
+	assert.True(updated.Equal(&otherC))
+
+	// a zero value differs from an absent value
+	assert.NoError(tree.Insert(key(1, 0xff), make([]byte, ValueSize)))
+	withZero := tree.Commit()
+	assert.False(withZero.Equal(&updated))
+
+	assert.ErrorIs(tree.Insert(keys[0][1:], value(0)), ErrInvalidKeySize)
+	assert.ErrorIs(tree.Insert(keys[0], value(0)[1:]), ErrInvalidValueSize)
+}
+
+// TestCommitment checks the commitment of a tree with a single leaf against
+// its definition.
+func TestCommitment(t *testing.T) {
+	assert := require.New(t)
+	c := getConfig()
+
+	k := key(130, 7, 8)
+	v := value(3)
+	tree := New()
+	assert.NoError(tree.Insert(k, v))
+
+	// C₂ commits to the value at 2⋅(130-128), C₁ is empty
+	suffixValues := make([]fr.Element, NodeWidth)
+	suffixValues[4], suffixValues[5] = valueToScalars(v)
+	var b [fr.Bytes]byte
+	copy(b[:16], v[:16])
+	b[16] = 1
+	lo, err := fr.LittleEndian.Element(&b)
+	assert.NoError(err)
+	assert.True(lo.Equal(&suffixValues[4]))
+	c2 := c.commit(suffixValues)
+
+	leafValues := make([]fr.Element, NodeWidth)
+	leafValues[0].SetOne()
+	leafValues[1] = stemToScalar(k[:StemSize])
+	leafValues[3] = c2.MapToScalarField()
+	leaf := c.commit(leafValues)
+
+	rootValues := make([]fr.Element, NodeWidth)
+	rootValues[7] = leaf.MapToScalarField()
+	expected := c.commit(rootValues)
+	root := tree.Commit()
+	assert.True(expected.Equal(&root))
+}
+
+func TestProof(t *testing.T) {
+	assert := require.New(t)
+
+	tree := New()
+	keys := [][]byte{key(0), key(1), key(200), key(0, 1), key(0, 1, 2), key(0, 1, 2, 3), key(5, 0xff)}
+	for i := range keys {
+		assert.NoError(tree.Insert(keys[i], value(byte(i))))
+	}
+	root := tree.Commit()
+
+	cases := [][][]byte{
+		// present keys, in any order
+		{keys[2], keys[0], keys[4]},
+		// absent suffix of a present stem
+		{key(3), key(250)},
+		// absent stem ending in an empty node
+		{key(0, 3)},
+		// absent stems ending in a leaf of another stem
+		{key(0, 0xff, 1), key(0, 0xff, 2)},
+		// absent and present stems ending in the same leaf
+		{key(0, 0xff, 1), key(5, 0xff)},
+		// a mix of everything
+		append([][]byte{key(0, 3), key(9, 0, 1, 2, 4), key(3), key(0, 0xff, 1)}, keys...),
+	}
+	for i, keysToProve := range cases {
+		proof, values, err := tree.Prove(keysToProve)
+		assert.NoError(err, "case %d", i)
+		for j := range keysToProve {
+			v, err := tree.Get(keysToProve[j])
+			assert.NoError(err)
+			assert.Equal(v, values[j])
+		}
+		assert.NoError(Verify(proof, &root, keysToProve, values), "case %d", i)
+
+		// serialization
+		var buf bytes.Buffer
+		written, err := proof.WriteTo(&buf)
+		assert.NoError(err)
+		var read Proof
+		nRead, err := read.ReadFrom(&buf)
+		assert.NoError(err)
+		assert.Equal(written, nRead)
+		assert.NoError(Verify(&read, &root, keysToProve, values), "case %d", i)
+
+		// wrong value
+		wrongValues := make([][]byte, len(values))
+		copy(wrongValues, values)
+		if wrongValues[0] == nil {
+			wrongValues[0] = value(0)
+		} else {
+			wrongValues[0] = nil
+		}
+		assert.Error(Verify(proof, &root, keysToProve, wrongValues), "case %d", i)
+
+		// wrong root
+		wrongRoot := banderwagon.Generator()
+		assert.Error(Verify(proof, &wrongRoot, keysToProve, values), "case %d", i)
+	}
+
+	// wrong value of the same size
+	proof, values, err := tree.Prove(keys[:1])
+	assert.NoError(err)
+	values[0][31]++
+	assert.ErrorIs(Verify(proof, &root, keys[:1], values), ErrVerifyProof)
+
+	// wrong key
+	other := key(0)
+	other[30] = 1
+	assert.Error(Verify(proof, &root, [][]byte{other}, values))
+
+	// wrong structure
+	proof, values, err = tree.Prove([][]byte{key(0, 0xff, 1)})
+	assert.NoError(err)
+	proof.DepthExtensionPresent[0]++
+	assert.Error(Verify(proof, &root, [][]byte{key(0, 0xff, 1)}, values))
+
+	_, _, err = tree.Prove(nil)
+	assert.ErrorIs(err, ErrNoKeys)
+}
+
+// TestVectors checks the root commitment and the serialized proofs against the
+// ones of go-verkle v0.2.2, the reference implementation of EIP-6800. The
+// vectors are printed by testdata/goverkle, with "go run ." in that directory.
+func TestVectors(t *testing.T) {
+	assert := require.New(t)
+
+	tree := New()
+	keys := [][]byte{key(0), key(1), key(200), key(0, 1), key(0, 1, 2), key(0, 1, 2, 3), key(5, 0xff)}
+	for i := range keys {
+		assert.NoError(tree.Insert(keys[i], value(byte(i))))
+	}
+	root := tree.Commit()
+	b := root.Bytes()
+	assert.Equal("63c62c5ae4c495123028b6d220ec33eacb05b9231d053c9483cf50d226278890", hex.EncodeToString(b[:]))
+
+	// sha256 of the serialized proofs
+	vectors := []struct {
+		keys  [][]byte
+		proof string
+	}{
+		{[][]byte{keys[2], keys[0], keys[4]}, "46bc0222983f553cda088f9a9d23dabdae18399d54bcf90a62b0c154113a9214"},
+		{[][]byte{key(3), key(250)}, "190d466423f8f5d694f4838d962c53e23629922bd2af7480658022c7e4392eab"},
+		{[][]byte{key(0, 3)}, "87a3ab19d2566cf095835f9b47a1ea42db88329c211044e6f1584fc515df563a"},
+		{[][]byte{key(0, 0xff, 1), key(5, 0xff)}, "1cb34b27955dd08abaa7cbc7a6d044ee243645380d28defb31633770e82f07dc"},
+		{[][]byte{key(0, 0xff, 1)}, "34add5f2900bf909ecbddc24bb00d0e9d28567c29c005998dc86813c2b9b1f1c"},
+		{append([][]byte{key(0, 3), key(9, 0, 1, 2, 4), key(3), key(0, 0xff, 1)}, keys...), "8af181661dc941cd1f77d95a941eb26850642101851ebdb51c9df33251109542"},
+	}
+	for i, v := range vectors {
+		proof, values, err := tree.Prove(v.keys)
+		assert.NoError(err)
+		assert.NoError(Verify(proof, &root, v.keys, values))
+		h := sha256.New()
+		_, err = proof.WriteTo(h)
+		assert.NoError(err)
+		assert.Equal(v.proof, hex.EncodeToString(h.Sum(nil)), "case %d", i)
+	}
+}
+
+func TestProofForgedIdentity(t *testing.T) {
+	assert := require.New(t)
+
+	tree := New()
+	keys := [][]byte{key(0), key(1), key(0, 1)}
+	for i := range keys {
+		assert.NoError(tree.Insert(keys[i], value(byte(i))))
+	}
+	root := tree.Commit()
+	proof, values, err := tree.Prove(keys[:1])
+	assert.NoError(err)
+
+	// the decoding of 32 zero bytes is (0, -1), a representative of the
+	// identity at which the endomorphism of bandersnatch isn't defined
+	var zero banderwagon.Element
+	assert.NoError(zero.SetBytes(make([]byte, banderwagon.Bytes)))
+	proof.Multiproof.IPA.L[0] = zero
+	values[0][31]++
+	assert.Error(Verify(proof, &root, keys[:1], values))
+}
+
+func TestProofEmptyTree(t *testing.T) {
+	assert := require.New(t)
+
+	tree := New()
+	root := tree.Commit()
+	keys := [][]byte{key(0), key(0, 1)}
+	proof, values, err := tree.Prove(keys)
+	assert.NoError(err)
+	assert.Equal([][]byte{nil, nil}, values)
+	assert.Empty(proof.CommitmentsByPath)
+	assert.NoError(Verify(proof, &root, keys, values))
+}
+
+func BenchmarkProve(b *testing.B) {
+	tree := New()
+	keys := make([][]byte, 1000)
+	for i := range keys {
+		keys[i] = make([]byte, KeySize)
+		_, _ = rand.Read(keys[i])
+		_ = tree.Insert(keys[i], keys[i])
+	}
+	tree.Commit()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _, _ = tree.Prove(keys[:16])
+	}
+}
@@ -0,0 +1,313 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package minpk
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"hash"
+	"io"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/consensys/gnark-crypto/signature"
+	"golang.org/x/crypto/hkdf"
+)
+
+const (
+	sizeFr         = fr.Bytes
+	sizePublicKey  = bls12381.SizeOfG1AffineCompressed
+	sizePrivateKey = sizeFr + sizePublicKey
+	sizeSignature  = bls12381.SizeOfG2AffineCompressed
+
+	// keyGenSaltSeed is the initial salt of KeyGen
+	keyGenSaltSeed = "BLS-SIG-KEYGEN-SALT-"
+
+	// DST is the domain separation tag used to hash messages to G2
+	DST = "BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_"
+	// DSTPop is the domain separation tag used to hash public keys to G2 in
+	// proofs of possession
+	DSTPop = "BLS_POP_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_"
+)
+
+var (
+	ErrShortIKM         = errors.New("input keying material must be at least 32 bytes long")
+	ErrInvalidPublicKey = errors.New("invalid public key: infinity or not in the prime subgroup")
+	ErrEmptyInput       = errors.New("no signatures or public keys to aggregate")
+	ErrLengthMismatch   = errors.New("number of public keys and messages mismatch")
+)
+
+// PublicKey represents a BLS public key
+type PublicKey struct {
+	A bls12381.G1Affine
+}
+
+// PrivateKey represents a BLS private key
+type PrivateKey struct {
+	PublicKey PublicKey
+	scalar    [sizeFr]byte // secret scalar, in big Endian
+}
+
+// Signature represents a BLS signature
+type Signature struct {
+	S bls12381.G2Affine
+}
+
+// KeyGen derives a private key from the secret input keying material ikm
+// (at least 32 bytes) and the optional keyInfo, as in the IETF draft, section 2.3:
+//
+//	salt = "BLS-SIG-KEYGEN-SALT-"
+//	while SK == 0:
+//		salt = SHA-256(salt)
+//		PRK = HKDF-Extract(salt, ikm || I2OSP(0, 1))
+//		OKM = HKDF-Expand(PRK, keyInfo || I2OSP(L, 2), L)
+//		SK = OS2IP(OKM) mod r
+//
+// where L = ceil((3 * ceil(log2(r))) / 16).
+func KeyGen(ikm, keyInfo []byte) (*PrivateKey, error) {
+	if len(ikm) < 32 {
+		return nil, ErrShortIKM
+	}
+	const L = (3*fr.Bits + 15) / 16
+
+	secret := make([]byte, len(ikm)+1)
+	copy(secret, ikm)
+	info := make([]byte, len(keyInfo)+2)
+	copy(info, keyInfo)
+	info[len(keyInfo)] = byte(L >> 8)
+	info[len(keyInfo)+1] = byte(L)
+
+	salt := []byte(keyGenSaltSeed)
+	okm := make([]byte, L)
+	sk := new(big.Int)
+	for sk.Sign() == 0 {
+		h := sha256.Sum256(salt)
+		salt = h[:]
+		prk := hkdf.Extract(sha256.New, secret, salt)
+		if _, err := io.ReadFull(hkdf.Expand(sha256.New, prk, info), okm); err != nil {
+			return nil, err
+		}
+		sk.SetBytes(okm).Mod(sk, fr.Modulus())
+	}
+
+	privateKey := new(PrivateKey)
+	sk.FillBytes(privateKey.scalar[:])
+	privateKey.PublicKey.A.ScalarMultiplicationBase(sk)
+	return privateKey, nil
+}
+
+// GenerateKey generates a public and private key pair, using 32 bytes read
+// from rand as input keying material for KeyGen.
+func GenerateKey(rand io.Reader) (*PrivateKey, error) {
+	ikm := make([]byte, 32)
+	if _, err := io.ReadFull(rand, ikm); err != nil {
+		return nil, err
+	}
+	return KeyGen(ikm, nil)
+}
+
+// Equal compares 2 public keys
+func (pub *PublicKey) Equal(x signature.PublicKey) bool {
+	xx, ok := x.(*PublicKey)
+	if !ok {
+		return false
+	}
+	bpk := pub.Bytes()
+	bxx := xx.Bytes()
+	return subtle.ConstantTimeCompare(bpk, bxx) == 1
+}
+
+// Public returns the public key associated to the private key.
+func (privKey *PrivateKey) Public() signature.PublicKey {
+	var pub PublicKey
+	pub.A.Set(&privKey.PublicKey.A)
+	return &pub
+}
+
+// IsValid returns true if the public key is not the point at infinity and
+// lies in the prime order subgroup (KeyValidate in the IETF draft).
+func (pub *PublicKey) IsValid() bool {
+	return !pub.A.IsInfinity() && pub.A.IsInSubGroup()
+}
+
+// hashToPoint hashes the message to G2. If hFunc is not nil, the
+// message is first hashed with hFunc.
+func hashToPoint(message, dst []byte, hFunc hash.Hash) (bls12381.G2Affine, error) {
+	if hFunc != nil {
+		hFunc.Reset()
+		if _, err := hFunc.Write(message); err != nil {
+			return bls12381.G2Affine{}, err
+		}
+		message = hFunc.Sum(nil)
+	}
+	return bls12381.HashToG2(message, dst)
+}
+
+// sign computes sk ⋅ H(message)
+func (privKey *PrivateKey) sign(message, dst []byte, hFunc hash.Hash) ([]byte, error) {
+	Q, err := hashToPoint(message, dst, hFunc)
+	if err != nil {
+		return nil, err
+	}
+	var sig Signature
+	sig.S.ScalarMultiplication(&Q, new(big.Int).SetBytes(privKey.scalar[:]))
+	return sig.Bytes(), nil
+}
+
+// Sign performs the BLS signature
+//
+// Q = hash_to_G2(m)
+// signature = sk ⋅ Q
+//
+// If hFunc is not provided, the message is directly hashed to the curve,
+// as in the IETF draft. Else, the message is first hashed with hFunc.
+func (privKey *PrivateKey) Sign(message []byte, hFunc hash.Hash) ([]byte, error) {
+	return privKey.sign(message, []byte(DST), hFunc)
+}
+
+// PopProve returns a proof of possession of the private key, that is a
+// signature of the public key with the DSTPop domain separation tag.
+func (privKey *PrivateKey) PopProve() ([]byte, error) {
+	return privKey.sign(privKey.PublicKey.Bytes(), []byte(DSTPop), nil)
+}
+
+// verify checks e(pk, Q) == e(g, sig) where Q = H(message)
+func (pub *PublicKey) verify(sigBin, message, dst []byte, hFunc hash.Hash) (bool, error) {
+	if !pub.IsValid() {
+		return false, ErrInvalidPublicKey
+	}
+	var sig Signature
+	if _, err := sig.SetBytes(sigBin); err != nil {
+		return false, err
+	}
+	Q, err := hashToPoint(message, dst, hFunc)
+	if err != nil {
+		return false, err
+	}
+	return pairingCheck([]bls12381.G1Affine{pub.A}, []bls12381.G2Affine{Q}, &sig.S)
+}
+
+// Verify validates the BLS signature
+//
+// e(pk, hash_to_G2(m)) ?= e(g, signature)
+//
+// If hFunc is not provided, the message is directly hashed to the curve,
+// as in the IETF draft. Else, the message is first hashed with hFunc.
+func (pub *PublicKey) Verify(sigBin, message []byte, hFunc hash.Hash) (bool, error) {
+	return pub.verify(sigBin, message, []byte(DST), hFunc)
+}
+
+// PopVerify checks a proof of possession of the private key associated to pub.
+func (pub *PublicKey) PopVerify(proof []byte) (bool, error) {
+	return pub.verify(proof, pub.Bytes(), []byte(DSTPop), nil)
+}
+
+// AggregateSignatures returns the sum of the signatures.
+func AggregateSignatures(signatures [][]byte) ([]byte, error) {
+	if len(signatures) == 0 {
+		return nil, ErrEmptyInput
+	}
+	var acc bls12381.G2Jac
+	for i := range signatures {
+		var sig Signature
+		if _, err := sig.SetBytes(signatures[i]); err != nil {
+			return nil, err
+		}
+		acc.AddMixed(&sig.S)
+	}
+	var res Signature
+	res.S.FromJacobian(&acc)
+	return res.Bytes(), nil
+}
+
+// AggregatePublicKeys returns the sum of the public keys.
+func AggregatePublicKeys(publicKeys []*PublicKey) (*PublicKey, error) {
+	if len(publicKeys) == 0 {
+		return nil, ErrEmptyInput
+	}
+	var acc bls12381.G1Jac
+	for i := range publicKeys {
+		if !publicKeys[i].IsValid() {
+			return nil, ErrInvalidPublicKey
+		}
+		acc.AddMixed(&publicKeys[i].A)
+	}
+	var res PublicKey
+	res.A.FromJacobian(&acc)
+	return &res, nil
+}
+
+// FastAggregateVerify verifies an aggregated signature of a single message
+// by several signers, that is
+//
+// e(pk_1 + ... + pk_n, hash_to_G2(m)) ?= e(g, signature)
+//
+// The proofs of possession of the public keys must have been checked
+// beforehand.
+func FastAggregateVerify(publicKeys []*PublicKey, message, sigBin []byte, hFunc hash.Hash) (bool, error) {
+	aggPk, err := AggregatePublicKeys(publicKeys)
+	if err != nil {
+		return false, err
+	}
+	return aggPk.Verify(sigBin, message, hFunc)
+}
+
+// AggregateVerify verifies an aggregated signature of the messages[i] by
+// publicKeys[i], that is
+//
+// e(pk_1, hash_to_G2(m_1)) ⋯ e(pk_n, hash_to_G2(m_n)) ?= e(g, signature)
+//
+// The proofs of possession of the public keys must have been checked
+// beforehand.
+func AggregateVerify(publicKeys []*PublicKey, messages [][]byte, sigBin []byte, hFunc hash.Hash) (bool, error) {
+	if len(publicKeys) == 0 {
+		return false, ErrEmptyInput
+	}
+	if len(publicKeys) != len(messages) {
+		return false, ErrLengthMismatch
+	}
+	var sig Signature
+	if _, err := sig.SetBytes(sigBin); err != nil {
+		return false, err
+	}
+	pks := make([]bls12381.G1Affine, len(publicKeys))
+	Qs := make([]bls12381.G2Affine, len(publicKeys))
+	for i := range publicKeys {
+		if !publicKeys[i].IsValid() {
+			return false, ErrInvalidPublicKey
+		}
+		pks[i].Set(&publicKeys[i].A)
+		Q, err := hashToPoint(messages[i], []byte(DST), hFunc)
+		if err != nil {
+			return false, err
+		}
+		Qs[i].Set(&Q)
+	}
+	return pairingCheck(pks, Qs, &sig.S)
+}
+
+// pairingCheck returns true if e(pks[0], Qs[0]) ⋯ e(pks[n-1], Qs[n-1]) == e(g, sig)
+func pairingCheck(pks []bls12381.G1Affine, Qs []bls12381.G2Affine, sig *bls12381.G2Affine) (bool, error) {
+	_, _, g1, _ := bls12381.Generators()
+	var gNeg bls12381.G1Affine
+	gNeg.Neg(&g1)
+	P := append(pks, gNeg)
+	Q := append(Qs, *sig)
+	return bls12381.PairingCheck(P, Q)
+}
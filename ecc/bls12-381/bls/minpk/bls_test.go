@@ -0,0 +1,269 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package minpk
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/leanovate/gopter"
+	"github.com/leanovate/gopter/prop"
+)
+
+func TestBLS(t *testing.T) {
+
+	t.Parallel()
+	parameters := gopter.DefaultTestParameters()
+	parameters.MinSuccessfulTests = 10
+	properties := gopter.NewProperties(parameters)
+
+	properties.Property("[BLS12-381] test the signing and verification", prop.ForAll(
+		func() bool {
+
+			privKey, _ := GenerateKey(rand.Reader)
+			publicKey := privKey.PublicKey
+
+			msg := []byte("testing BLS")
+			hFunc := sha256.New()
+			sig, _ := privKey.Sign(msg, hFunc)
+			flag, _ := publicKey.Verify(sig, msg, hFunc)
+
+			return flag
+		},
+	))
+
+	properties.Property("[BLS12-381] test the signing and verification (no pre-hash)", prop.ForAll(
+		func() bool {
+
+			privKey, _ := GenerateKey(rand.Reader)
+			publicKey := privKey.PublicKey
+
+			msg := []byte("testing BLS")
+			sig, _ := privKey.Sign(msg, nil)
+			flag, _ := publicKey.Verify(sig, msg, nil)
+			wrongFlag, _ := publicKey.Verify(sig, []byte("wrong message"), nil)
+
+			return flag && !wrongFlag
+		},
+	))
+
+	properties.Property("[BLS12-381] test the proof of possession", prop.ForAll(
+		func() bool {
+
+			privKey, _ := GenerateKey(rand.Reader)
+			publicKey := privKey.PublicKey
+
+			proof, _ := privKey.PopProve()
+			flag, _ := publicKey.PopVerify(proof)
+
+			// a signature of the public key is not a valid proof of possession
+			sig, _ := privKey.Sign(publicKey.Bytes(), nil)
+			wrongFlag, _ := publicKey.PopVerify(sig)
+
+			return flag && !wrongFlag
+		},
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+}
+
+func TestAggregation(t *testing.T) {
+	t.Parallel()
+
+	const n = 4
+	privKeys := make([]*PrivateKey, n)
+	publicKeys := make([]*PublicKey, n)
+	for i := 0; i < n; i++ {
+		privKeys[i], _ = GenerateKey(rand.Reader)
+		publicKeys[i] = &privKeys[i].PublicKey
+	}
+
+	t.Run("FastAggregateVerify", func(t *testing.T) {
+		msg := []byte("testing BLS aggregation")
+		sigs := make([][]byte, n)
+		for i := 0; i < n; i++ {
+			sigs[i], _ = privKeys[i].Sign(msg, nil)
+		}
+		aggSig, err := AggregateSignatures(sigs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok, err := FastAggregateVerify(publicKeys, msg, aggSig, nil); err != nil || !ok {
+			t.Fatal("aggregated signature should verify", err)
+		}
+		if ok, _ := FastAggregateVerify(publicKeys[1:], msg, aggSig, nil); ok {
+			t.Fatal("aggregated signature should not verify with a missing public key")
+		}
+	})
+
+	t.Run("AggregateVerify", func(t *testing.T) {
+		msgs := make([][]byte, n)
+		sigs := make([][]byte, n)
+		for i := 0; i < n; i++ {
+			msgs[i] = []byte{byte(i)}
+			sigs[i], _ = privKeys[i].Sign(msgs[i], nil)
+		}
+		aggSig, err := AggregateSignatures(sigs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok, err := AggregateVerify(publicKeys, msgs, aggSig, nil); err != nil || !ok {
+			t.Fatal("aggregated signature should verify", err)
+		}
+		msgs[0], msgs[1] = msgs[1], msgs[0]
+		if ok, _ := AggregateVerify(publicKeys, msgs, aggSig, nil); ok {
+			t.Fatal("aggregated signature should not verify with swapped messages")
+		}
+	})
+
+	t.Run("EmptyInput", func(t *testing.T) {
+		if _, err := AggregateSignatures(nil); err != ErrEmptyInput {
+			t.Fatal("expected ErrEmptyInput")
+		}
+		if _, err := AggregatePublicKeys(nil); err != ErrEmptyInput {
+			t.Fatal("expected ErrEmptyInput")
+		}
+	})
+}
+
+func TestKeyGen(t *testing.T) {
+	if _, err := KeyGen(make([]byte, 31), nil); err != ErrShortIKM {
+		t.Fatal("expected ErrShortIKM")
+	}
+
+	ikm := make([]byte, 32)
+	sk1, err := KeyGen(ikm, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sk2, err := KeyGen(ikm, []byte("info"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sk1.PublicKey.Equal(&sk2.PublicKey) {
+		t.Fatal("key info should change the derived key")
+	}
+}
+
+// KeyGen test vectors from EIP-2333 (derive_master_SK), which matches KeyGen
+// of the IETF draft with an empty key info.
+func TestKeyGenVectors(t *testing.T) {
+	vectors := []struct {
+		seed, sk string
+	}{
+		{
+			seed: "c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04",
+			sk:   "6083874454709270928345386274498605044986640685124978867557563392430687146096",
+		},
+		{
+			seed: "3141592653589793238462643383279502884197169399375105820974944592",
+			sk:   "29757020647961307431480504535336562678282505419141012933316116377660817309383",
+		},
+	}
+	for _, v := range vectors {
+		seed, err := hex.DecodeString(v.seed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		privKey, err := KeyGen(seed, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if new(big.Int).SetBytes(privKey.scalar[:]).String() != v.sk {
+			t.Fatal("wrong private key")
+		}
+	}
+}
+
+// Test vectors from the Ethereum consensus specs, which use the
+// BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_ ciphersuite.
+func TestSignVectors(t *testing.T) {
+	vectors := []struct {
+		sk, pk, msg, sig string
+	}{
+		{
+			sk:  "263dbd792f5b1be47ed85f8938c0f29586af0d3ac7b977f21c278fe1462040e3",
+			pk:  "a491d1b0ecd9bb917989f0e74f0dea0422eac4a873e5e2644f368dffb9a6e20fd6e10c1b77654d067c0618f6e5a7f79a",
+			msg: "0000000000000000000000000000000000000000000000000000000000000000",
+			sig: "b6ed936746e01f8ecf281f020953fbf1f01debd5657c4a383940b020b26507f6076334f91e2366c96e9ab279fb5158090352ea1c5b0c9274504f4f0e7053af24802e51e4568d164fe986834f41e55c8e850ce1f98458c0cfc9ab380b55285a55",
+		},
+		{
+			sk:  "263dbd792f5b1be47ed85f8938c0f29586af0d3ac7b977f21c278fe1462040e3",
+			pk:  "a491d1b0ecd9bb917989f0e74f0dea0422eac4a873e5e2644f368dffb9a6e20fd6e10c1b77654d067c0618f6e5a7f79a",
+			msg: "5656565656565656565656565656565656565656565656565656565656565656",
+			sig: "882730e5d03f6b42c3abc26d3372625034e1d871b65a8a6b900a56dae22da98abbe1b68f85e49fe7652a55ec3d0591c20767677e33e5cbb1207315c41a9ac03be39c2e7668edc043d6cb1d9fd93033caa8a1c5b0e84bedaeb6c64972503a43eb",
+		},
+	}
+	for _, v := range vectors {
+		skBin, _ := hex.DecodeString(v.sk)
+		pkBin, _ := hex.DecodeString(v.pk)
+		msg, _ := hex.DecodeString(v.msg)
+
+		var privKey PrivateKey
+		if _, err := privKey.SetBytes(append(pkBin, skBin...)); err != nil {
+			t.Fatal(err)
+		}
+		sig, err := privKey.Sign(msg, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hex.EncodeToString(sig) != v.sig {
+			t.Fatal("wrong signature")
+		}
+		if ok, err := privKey.PublicKey.Verify(sig, msg, nil); err != nil || !ok {
+			t.Fatal("signature should verify", err)
+		}
+	}
+}
+
+func TestInvalidPublicKey(t *testing.T) {
+	var publicKey PublicKey // point at infinity
+	privKey, _ := GenerateKey(rand.Reader)
+	sig, _ := privKey.Sign([]byte("msg"), nil)
+	if _, err := publicKey.Verify(sig, []byte("msg"), nil); err != ErrInvalidPublicKey {
+		t.Fatal("expected ErrInvalidPublicKey")
+	}
+}
+
+// ------------------------------------------------------------
+// benches
+
+func BenchmarkSignBLS(b *testing.B) {
+
+	privKey, _ := GenerateKey(rand.Reader)
+
+	msg := []byte("benchmarking BLS sign()")
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		privKey.Sign(msg, nil)
+	}
+}
+
+func BenchmarkVerifyBLS(b *testing.B) {
+
+	privKey, _ := GenerateKey(rand.Reader)
+	msg := []byte("benchmarking BLS sign()")
+	sig, _ := privKey.Sign(msg, nil)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		privKey.PublicKey.Verify(sig, msg, nil)
+	}
+}
@@ -0,0 +1,228 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package minsig
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/leanovate/gopter"
+	"github.com/leanovate/gopter/prop"
+)
+
+func TestBLS(t *testing.T) {
+
+	t.Parallel()
+	parameters := gopter.DefaultTestParameters()
+	parameters.MinSuccessfulTests = 10
+	properties := gopter.NewProperties(parameters)
+
+	properties.Property("[BLS12-381] test the signing and verification", prop.ForAll(
+		func() bool {
+
+			privKey, _ := GenerateKey(rand.Reader)
+			publicKey := privKey.PublicKey
+
+			msg := []byte("testing BLS")
+			hFunc := sha256.New()
+			sig, _ := privKey.Sign(msg, hFunc)
+			flag, _ := publicKey.Verify(sig, msg, hFunc)
+
+			return flag
+		},
+	))
+
+	properties.Property("[BLS12-381] test the signing and verification (no pre-hash)", prop.ForAll(
+		func() bool {
+
+			privKey, _ := GenerateKey(rand.Reader)
+			publicKey := privKey.PublicKey
+
+			msg := []byte("testing BLS")
+			sig, _ := privKey.Sign(msg, nil)
+			flag, _ := publicKey.Verify(sig, msg, nil)
+			wrongFlag, _ := publicKey.Verify(sig, []byte("wrong message"), nil)
+
+			return flag && !wrongFlag
+		},
+	))
+
+	properties.Property("[BLS12-381] test the proof of possession", prop.ForAll(
+		func() bool {
+
+			privKey, _ := GenerateKey(rand.Reader)
+			publicKey := privKey.PublicKey
+
+			proof, _ := privKey.PopProve()
+			flag, _ := publicKey.PopVerify(proof)
+
+			// a signature of the public key is not a valid proof of possession
+			sig, _ := privKey.Sign(publicKey.Bytes(), nil)
+			wrongFlag, _ := publicKey.PopVerify(sig)
+
+			return flag && !wrongFlag
+		},
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+}
+
+func TestAggregation(t *testing.T) {
+	t.Parallel()
+
+	const n = 4
+	privKeys := make([]*PrivateKey, n)
+	publicKeys := make([]*PublicKey, n)
+	for i := 0; i < n; i++ {
+		privKeys[i], _ = GenerateKey(rand.Reader)
+		publicKeys[i] = &privKeys[i].PublicKey
+	}
+
+	t.Run("FastAggregateVerify", func(t *testing.T) {
+		msg := []byte("testing BLS aggregation")
+		sigs := make([][]byte, n)
+		for i := 0; i < n; i++ {
+			sigs[i], _ = privKeys[i].Sign(msg, nil)
+		}
+		aggSig, err := AggregateSignatures(sigs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok, err := FastAggregateVerify(publicKeys, msg, aggSig, nil); err != nil || !ok {
+			t.Fatal("aggregated signature should verify", err)
+		}
+		if ok, _ := FastAggregateVerify(publicKeys[1:], msg, aggSig, nil); ok {
+			t.Fatal("aggregated signature should not verify with a missing public key")
+		}
+	})
+
+	t.Run("AggregateVerify", func(t *testing.T) {
+		msgs := make([][]byte, n)
+		sigs := make([][]byte, n)
+		for i := 0; i < n; i++ {
+			msgs[i] = []byte{byte(i)}
+			sigs[i], _ = privKeys[i].Sign(msgs[i], nil)
+		}
+		aggSig, err := AggregateSignatures(sigs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok, err := AggregateVerify(publicKeys, msgs, aggSig, nil); err != nil || !ok {
+			t.Fatal("aggregated signature should verify", err)
+		}
+		msgs[0], msgs[1] = msgs[1], msgs[0]
+		if ok, _ := AggregateVerify(publicKeys, msgs, aggSig, nil); ok {
+			t.Fatal("aggregated signature should not verify with swapped messages")
+		}
+	})
+
+	t.Run("EmptyInput", func(t *testing.T) {
+		if _, err := AggregateSignatures(nil); err != ErrEmptyInput {
+			t.Fatal("expected ErrEmptyInput")
+		}
+		if _, err := AggregatePublicKeys(nil); err != ErrEmptyInput {
+			t.Fatal("expected ErrEmptyInput")
+		}
+	})
+}
+
+func TestKeyGen(t *testing.T) {
+	if _, err := KeyGen(make([]byte, 31), nil); err != ErrShortIKM {
+		t.Fatal("expected ErrShortIKM")
+	}
+
+	ikm := make([]byte, 32)
+	sk1, err := KeyGen(ikm, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sk2, err := KeyGen(ikm, []byte("info"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sk1.PublicKey.Equal(&sk2.PublicKey) {
+		t.Fatal("key info should change the derived key")
+	}
+}
+
+// KeyGen test vectors from EIP-2333 (derive_master_SK), which matches KeyGen
+// of the IETF draft with an empty key info.
+func TestKeyGenVectors(t *testing.T) {
+	vectors := []struct {
+		seed, sk string
+	}{
+		{
+			seed: "c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04",
+			sk:   "6083874454709270928345386274498605044986640685124978867557563392430687146096",
+		},
+		{
+			seed: "3141592653589793238462643383279502884197169399375105820974944592",
+			sk:   "29757020647961307431480504535336562678282505419141012933316116377660817309383",
+		},
+	}
+	for _, v := range vectors {
+		seed, err := hex.DecodeString(v.seed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		privKey, err := KeyGen(seed, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if new(big.Int).SetBytes(privKey.scalar[:]).String() != v.sk {
+			t.Fatal("wrong private key")
+		}
+	}
+}
+
+func TestInvalidPublicKey(t *testing.T) {
+	var publicKey PublicKey // point at infinity
+	privKey, _ := GenerateKey(rand.Reader)
+	sig, _ := privKey.Sign([]byte("msg"), nil)
+	if _, err := publicKey.Verify(sig, []byte("msg"), nil); err != ErrInvalidPublicKey {
+		t.Fatal("expected ErrInvalidPublicKey")
+	}
+}
+
+// ------------------------------------------------------------
+// benches
+
+func BenchmarkSignBLS(b *testing.B) {
+
+	privKey, _ := GenerateKey(rand.Reader)
+
+	msg := []byte("benchmarking BLS sign()")
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		privKey.Sign(msg, nil)
+	}
+}
+
+func BenchmarkVerifyBLS(b *testing.B) {
+
+	privKey, _ := GenerateKey(rand.Reader)
+	msg := []byte("benchmarking BLS sign()")
+	sig, _ := privKey.Sign(msg, nil)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		privKey.PublicKey.Verify(sig, msg, nil)
+	}
+}
@@ -0,0 +1,132 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package fri
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	// DefaultBlowup is the default blowup factor ρ = size_code_word/size_polynomial
+	DefaultBlowup = 8
+
+	// DefaultFoldingArity is the default number of entries folded at each step
+	DefaultFoldingArity = 2
+
+	// DefaultNbQueries is the default number of queries of the verifier
+	DefaultNbQueries = 1
+)
+
+// ErrInvalidParameters is raised (through a panic) by IOPP.New when the options are not supported
+var ErrInvalidParameters = errors.New("fri: invalid parameters")
+
+// Option defines option for altering the parameters of the IOPP.
+// See the descriptions of functions returning instances of this type for
+// particular options.
+type Option func(*friConfig)
+
+type friConfig struct {
+	blowup      int
+	arity       int
+	nbQueries   int
+	powBits     int
+	finalDegree int
+}
+
+// WithBlowup sets the blowup factor ρ = size_code_word/size_polynomial, that is the
+// inverse of the rate of the Reed Solomon code. It must be a power of 2, at least 2.
+// Default is DefaultBlowup.
+func WithBlowup(blowup int) Option {
+	return func(opt *friConfig) {
+		opt.blowup = blowup
+	}
+}
+
+// WithFoldingArity sets the number of entries folded at each step, that is the
+// polynomial P is written ∑ᵢ XⁱPᵢ(X^arity) and folded into ∑ᵢ βⁱPᵢ(X). It must be
+// 2, 4, 8 or 16. Default is DefaultFoldingArity.
+//
+// The last step may use a smaller arity if the degree of the polynomial is not a
+// power of the arity.
+func WithFoldingArity(arity int) Option {
+	return func(opt *friConfig) {
+		opt.arity = arity
+	}
+}
+
+// WithNbQueries sets the number of queries of the verifier. Each query opens one
+// coset per folding step. Default is DefaultNbQueries.
+func WithNbQueries(nbQueries int) Option {
+	return func(opt *friConfig) {
+		opt.nbQueries = nbQueries
+	}
+}
+
+// WithProofOfWork requires the prover to grind a nonce such that the hash of the
+// transcript and the nonce starts with nbBits zero bits, before deriving the
+// queries. It adds roughly nbBits bits of security for the cost of 2^nbBits hashes
+// for the prover. Must be between 0 and 32, default is 0 (no grinding).
+func WithProofOfWork(nbBits int) Option {
+	return func(opt *friConfig) {
+		opt.powBits = nbBits
+	}
+}
+
+// WithFinalDegree stops the folding as soon as the folded polynomial has degree
+// at most degree (rounded to the next power of 2 minus 1), and sends its
+// coefficients in clear. Default is 0, that is the polynomial is folded until it
+// is constant.
+func WithFinalDegree(degree int) Option {
+	return func(opt *friConfig) {
+		opt.finalDegree = degree
+	}
+}
+
+// default options
+func friOptions(opts ...Option) friConfig {
+	// apply options
+	opt := friConfig{
+		blowup:    DefaultBlowup,
+		arity:     DefaultFoldingArity,
+		nbQueries: DefaultNbQueries,
+	}
+	for _, option := range opts {
+		option(&opt)
+	}
+	return opt
+}
+
+// check returns an error if the options are not supported
+func (opt *friConfig) check() error {
+	if opt.blowup < 2 || opt.blowup&(opt.blowup-1) != 0 {
+		return fmt.Errorf("%w: the blowup factor must be a power of 2, at least 2", ErrInvalidParameters)
+	}
+	if opt.arity != 2 && opt.arity != 4 && opt.arity != 8 && opt.arity != 16 {
+		return fmt.Errorf("%w: the folding arity must be 2, 4, 8 or 16", ErrInvalidParameters)
+	}
+	if opt.nbQueries < 1 {
+		return fmt.Errorf("%w: the number of queries must be positive", ErrInvalidParameters)
+	}
+	if opt.powBits < 0 || opt.powBits > 32 {
+		return fmt.Errorf("%w: the number of bits of proof of work must be between 0 and 32", ErrInvalidParameters)
+	}
+	if opt.finalDegree < 0 {
+		return fmt.Errorf("%w: the final degree must be non negative", ErrInvalidParameters)
+	}
+	return nil
+}
@@ -0,0 +1,251 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package poseidon2
+
+import (
+	"errors"
+	"hash"
+	"sync"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+const (
+	// BlockSize size that the sponge consumes, one field element
+	BlockSize = fr.Bytes
+
+	seed = "Poseidon2 hash" // seed to derive the round keys
+)
+
+var (
+	ErrInvalidParameters = errors.New("poseidon2: rate and capacity must be positive and rate+capacity must be 2, 3 or a multiple of 4 up to 24")
+
+	// nbRounds are the numbers of full and partial rounds of the permutations
+	// used by NewPoseidon2 and Compress, indexed by the width. They are
+	// computed as in the reference implementation, for 128 bits of security
+	// and with its security margin.
+	nbRounds = map[int][2]int{
+		2:  {8, 56},
+		3:  {8, 56},
+		4:  {8, 56},
+		8:  {8, 57},
+		12: {8, 57},
+		16: {8, 57},
+		20: {8, 57},
+		24: {8, 57},
+	}
+
+	// permutations indexed by the width, shared by all the sponges and Compress
+	permutations    = make(map[int]*Hash)
+	permutationsMux sync.Mutex
+)
+
+// RoundNumbers returns the numbers of full and partial rounds of the
+// permutation of width t used by NewPoseidon2 and Compress. The supported
+// widths are 2, 3 and the multiples of 4 up to 24.
+func RoundNumbers(t int) (nbFullRounds, nbPartialRounds int, err error) {
+	rounds, ok := nbRounds[t]
+	if !ok {
+		return 0, 0, ErrInvalidParameters
+	}
+	return rounds[0], rounds[1], nil
+}
+
+// getPermutation returns the permutation of width t with default parameters,
+// the round keys are computed on first use only. t must be supported.
+func getPermutation(t int) *Hash {
+	permutationsMux.Lock()
+	defer permutationsMux.Unlock()
+	if permutations[t] == nil {
+		h := NewHash(t, nbRounds[t][0], nbRounds[t][1], seed)
+		permutations[t] = &h
+	}
+	return permutations[t]
+}
+
+// sponge implements hash.Hash with the sponge construction on top of the
+// poseidon2 permutation.
+//
+// The state is made of rate elements followed by capacity elements. The first
+// capacity element is initialised with the domain separator. The message is
+// padded with a single one followed by as many zeros as needed to fill a block
+// (padding 10*), so that two messages of distinct lengths never collide.
+// The digest is the first element of the state after the last permutation.
+type sponge struct {
+	permutation *Hash
+	rate        int
+	domain      fr.Element
+	byteOrder   fr.ByteOrder
+
+	state []fr.Element // rate || capacity
+	data  []fr.Element // absorbed elements not yet processed, len(data) < rate
+}
+
+// NewPoseidon2 returns a hash.Hash using the sponge construction on top of
+// the poseidon2 permutation, see Option for the parameters. It panics if the
+// rate or the capacity is not positive, or if rate+capacity is not a supported
+// width, see RoundNumbers.
+func NewPoseidon2(opts ...Option) hash.Hash {
+	cfg := spongeOptions(opts...)
+	if _, ok := nbRounds[cfg.rate+cfg.capacity]; !ok || cfg.rate < 1 || cfg.capacity < 1 {
+		panic(ErrInvalidParameters)
+	}
+	d := &sponge{
+		permutation: getPermutation(cfg.rate + cfg.capacity),
+		rate:        cfg.rate,
+		byteOrder:   cfg.byteOrder,
+		state:       make([]fr.Element, cfg.rate+cfg.capacity),
+		data:        make([]fr.Element, 0, cfg.rate),
+	}
+	if len(cfg.domainSeparator) != 0 {
+		domain, err := fr.Hash(cfg.domainSeparator, []byte(seed), 1)
+		if err != nil {
+			panic(err)
+		}
+		d.domain = domain[0]
+	}
+	d.Reset()
+	return d
+}
+
+// Reset resets the Hash to its initial state.
+func (d *sponge) Reset() {
+	for i := range d.state {
+		d.state[i].SetZero()
+	}
+	d.state[d.rate].Set(&d.domain)
+	d.data = d.data[:0]
+}
+
+// Size returns the number of bytes Sum will return.
+func (d *sponge) Size() int {
+	return BlockSize
+}
+
+// BlockSize returns the hash's underlying block size.
+// The Write method must be able to accept any amount
+// of data, but it may operate more efficiently if all writes
+// are a multiple of the block size.
+func (d *sponge) BlockSize() int {
+	return BlockSize
+}
+
+// Write (via the embedded io.Writer interface) adds more data to the running hash.
+//
+// Each []byte block of size BlockSize represents a fr.Element, encoded using
+// the byte order set with WithByteOrder.
+//
+// If len(p) is not a multiple of BlockSize and any of the []byte in p represent an integer
+// larger than fr.Modulus, this function returns an error.
+//
+// To hash arbitrary data ([]byte not representing canonical field elements) use fr.Hash first
+func (d *sponge) Write(p []byte) (int, error) {
+	// as in MiMC, short inputs are left-padded to a full block.
+	if len(p) > 0 && len(p) < BlockSize {
+		pp := make([]byte, BlockSize)
+		copy(pp[len(pp)-len(p):], p)
+		p = pp
+	}
+	if len(p)%BlockSize != 0 {
+		return 0, errors.New("invalid input length: must represent a list of field elements, expects a []byte of len m*BlockSize")
+	}
+
+	elems := make([]fr.Element, len(p)/BlockSize)
+	for i := range elems {
+		elem, err := d.byteOrder.Element((*[BlockSize]byte)(p[i*BlockSize : (i+1)*BlockSize]))
+		if err != nil {
+			return 0, err
+		}
+		elems[i] = elem
+	}
+	d.WriteElements(elems...)
+
+	return len(p), nil
+}
+
+// WriteElements absorbs field elements in the sponge.
+func (d *sponge) WriteElements(elems ...fr.Element) {
+	for i := range elems {
+		d.data = append(d.data, elems[i])
+		if len(d.data) == d.rate {
+			absorb(d.permutation, d.state, d.data)
+			d.data = d.data[:0]
+		}
+	}
+}
+
+// Sum appends the current hash to b and returns the resulting slice.
+// It does not change the underlying hash state.
+func (d *sponge) Sum(b []byte) []byte {
+	res := d.SumElement()
+	var bytes [BlockSize]byte
+	d.byteOrder.PutElement(&bytes, res)
+	return append(b, bytes[:]...)
+}
+
+// SumElement returns the current hash as a field element.
+// It does not change the underlying hash state.
+func (d *sponge) SumElement() fr.Element {
+	state := make([]fr.Element, len(d.state))
+	copy(state, d.state)
+
+	// padding 10*
+	block := make([]fr.Element, d.rate)
+	copy(block, d.data)
+	block[len(d.data)].SetOne()
+	absorb(d.permutation, state, block)
+
+	return state[0]
+}
+
+// absorb adds block to the rate part of state and applies the permutation.
+func absorb(permutation *Hash, state, block []fr.Element) {
+	for i := range block {
+		state[i].Add(&state[i], &block[i])
+	}
+	// the width of the state matches the permutation by construction
+	_ = permutation.Permutation(state)
+}
+
+// Compress is a 2-to-1 compression function, suitable for building Merkle
+// trees. It applies the poseidon2 permutation of width 2 on (left, right),
+// and adds right to the second element of the result (feed-forward), cf
+// https://eprint.iacr.org/2023/323.pdf section 4.
+func Compress(left, right *fr.Element) fr.Element {
+	state := [2]fr.Element{*left, *right}
+	_ = getPermutation(2).Permutation(state[:])
+	state[1].Add(&state[1], right)
+	return state[1]
+}
+
+// CompressBytes is a 2-to-1 compression function operating on big endian
+// encoded field elements, see Compress.
+func CompressBytes(left, right []byte) ([]byte, error) {
+	if len(left) != BlockSize || len(right) != BlockSize {
+		return nil, ErrInvalidSizebuffer
+	}
+	var l, r fr.Element
+	if err := l.SetBytesCanonical(left); err != nil {
+		return nil, err
+	}
+	if err := r.SetBytesCanonical(right); err != nil {
+		return nil, err
+	}
+	res := Compress(&l, &r)
+	bytes := res.Bytes()
+	return bytes[:], nil
+}
@@ -0,0 +1,255 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package poseidon2
+
+import (
+	"golang.org/x/crypto/sha3"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// initDiagInternalMatrix returns the diagonal, minus one, of the internal
+// matrix M_I = 1 + diag of width t ≥ 4, derived from the seed.
+//
+// As in the reference implementation, the diagonal is drawn until the minimal
+// polynomials of M_I, M_I², .., M_I^{2t} are irreducible of degree t. This
+// ensures that M_I is invertible and prevents arbitrarily long subspace
+// trails, cf https://eprint.iacr.org/2023/323.pdf section 5.3.
+func initDiagInternalMatrix(seed string, t int) []fr.Element {
+	hash := sha3.NewLegacyKeccak256()
+	_, _ = hash.Write([]byte(seed))
+	_, _ = hash.Write([]byte("internal matrix"))
+	rnd := hash.Sum(nil)
+
+	diag := make([]fr.Element, t)
+	for {
+		for i := range diag {
+			hash.Reset()
+			_, _ = hash.Write(rnd)
+			rnd = hash.Sum(nil)
+			diag[i].SetBytes(rnd)
+		}
+		if isInternalMatrixSecure(diag) {
+			return diag
+		}
+	}
+}
+
+// isInternalMatrixSecure reports whether the characteristic polynomials of the
+// powers 1..2t of 1 + diag are irreducible. An irreducible characteristic
+// polynomial is the minimal polynomial.
+func isInternalMatrixSecure(diag []fr.Element) bool {
+	t := len(diag)
+	m := make([][]fr.Element, t)
+	for i := range m {
+		m[i] = make([]fr.Element, t)
+		for j := range m[i] {
+			m[i][j].SetOne()
+		}
+		m[i][i].Add(&m[i][i], &diag[i])
+	}
+
+	mk := m
+	for k := 1; k <= 2*t; k++ {
+		if !isIrreducible(charPoly(mk)) {
+			return false
+		}
+		mk = matMul(m, mk)
+	}
+	return true
+}
+
+// matMul returns a*b
+func matMul(a, b [][]fr.Element) [][]fr.Element {
+	res := make([][]fr.Element, len(a))
+	var tmp fr.Element
+	for i := range a {
+		res[i] = make([]fr.Element, len(b[0]))
+		for j := range res[i] {
+			for k := range b {
+				tmp.Mul(&a[i][k], &b[k][j])
+				res[i][j].Add(&res[i][j], &tmp)
+			}
+		}
+	}
+	return res
+}
+
+// charPoly returns the characteristic polynomial of the square matrix a, with
+// the coefficients in increasing degree. The matrix is first reduced to the
+// Hessenberg form, cf H. Cohen, A Course in Computational Algebraic Number
+// Theory, algorithm 2.2.9.
+func charPoly(a [][]fr.Element) []fr.Element {
+	n := len(a)
+	h := make([][]fr.Element, n)
+	for i := range h {
+		h[i] = make([]fr.Element, n)
+		copy(h[i], a[i])
+	}
+
+	var inv, u, tmp fr.Element
+	for m := 1; m < n-1; m++ {
+		// pivot
+		k := m
+		for k < n && h[k][m-1].IsZero() {
+			k++
+		}
+		if k == n {
+			continue
+		}
+		if k > m {
+			h[k], h[m] = h[m], h[k]
+			for j := range h {
+				h[j][k], h[j][m] = h[j][m], h[j][k]
+			}
+		}
+		inv.Inverse(&h[m][m-1])
+		for i := m + 1; i < n; i++ {
+			u.Mul(&h[i][m-1], &inv)
+			if u.IsZero() {
+				continue
+			}
+			for j := range h {
+				tmp.Mul(&u, &h[m][j])
+				h[i][j].Sub(&h[i][j], &tmp)
+			}
+			for j := range h {
+				tmp.Mul(&u, &h[j][i])
+				h[j][m].Add(&h[j][m], &tmp)
+			}
+		}
+	}
+
+	// p_m = (x - h[m-1][m-1]) p_{m-1} - ∑_{i=1}^{m-1} h[m-i-1][m-1] (∏_{j=1}^{i} h[m-j][m-j-1]) p_{m-i-1}
+	p := make([][]fr.Element, n+1)
+	p[0] = []fr.Element{fr.One()}
+	var c, prod fr.Element
+	for m := 1; m <= n; m++ {
+		p[m] = make([]fr.Element, m+1)
+		copy(p[m][1:], p[m-1])
+		for j := 0; j < m; j++ {
+			tmp.Mul(&h[m-1][m-1], &p[m-1][j])
+			p[m][j].Sub(&p[m][j], &tmp)
+		}
+		prod.SetOne()
+		for i := 1; i < m; i++ {
+			prod.Mul(&prod, &h[m-i][m-i-1])
+			c.Mul(&prod, &h[m-i-1][m-1])
+			for j := range p[m-i-1] {
+				tmp.Mul(&c, &p[m-i-1][j])
+				p[m][j].Sub(&p[m][j], &tmp)
+			}
+		}
+	}
+	return p[n]
+}
+
+// isIrreducible reports whether the monic polynomial f of degree t ≥ 1 is
+// irreducible, with the test of Ben-Or: gcd(x^{r^i} - x, f) = 1 for i ≤ t/2,
+// where r is the modulus of fr.
+func isIrreducible(f []fr.Element) bool {
+	t := len(f) - 1
+
+	// xr = x^r mod f
+	modulus := fr.Modulus()
+	xr := []fr.Element{fr.One()}
+	for i := modulus.BitLen() - 1; i >= 0; i-- {
+		xr = polyMulMod(xr, xr, f)
+		if modulus.Bit(i) == 1 {
+			xr = polyRem(append(make([]fr.Element, 1), xr...), f)
+		}
+	}
+
+	// the powers of xr, to compute g(x)^r = g(x^r) mod f
+	pows := make([][]fr.Element, t)
+	pows[0] = []fr.Element{fr.One()}
+	for j := 1; j < t; j++ {
+		pows[j] = polyMulMod(pows[j-1], xr, f)
+	}
+
+	var tmp fr.Element
+	g := xr
+	for i := 1; i <= t/2; i++ {
+		// gcd(g - x, f)
+		d := make([]fr.Element, max(len(g), 2))
+		copy(d, g)
+		tmp.SetOne()
+		d[1].Sub(&d[1], &tmp)
+		if polyGcdDegree(d, f) != 0 {
+			return false
+		}
+
+		next := make([]fr.Element, t)
+		for j := range g {
+			for k := range pows[j] {
+				tmp.Mul(&g[j], &pows[j][k])
+				next[k].Add(&next[k], &tmp)
+			}
+		}
+		g = trim(next)
+	}
+	return true
+}
+
+// polyMulMod returns a*b mod f, the polynomials are trimmed
+func polyMulMod(a, b, f []fr.Element) []fr.Element {
+	if len(a) == 0 || len(b) == 0 {
+		return nil
+	}
+	res := make([]fr.Element, len(a)+len(b)-1)
+	var tmp fr.Element
+	for i := range a {
+		for j := range b {
+			tmp.Mul(&a[i], &b[j])
+			res[i+j].Add(&res[i+j], &tmp)
+		}
+	}
+	return polyRem(res, f)
+}
+
+// polyRem returns a mod b, where b ≠ 0 is trimmed. a is modified.
+func polyRem(a, b []fr.Element) []fr.Element {
+	var inv, c, tmp fr.Element
+	inv.Inverse(&b[len(b)-1])
+	for i := len(a) - 1; i >= len(b)-1; i-- {
+		c.Mul(&a[i], &inv)
+		shift := i - len(b) + 1
+		for j := range b {
+			tmp.Mul(&c, &b[j])
+			a[shift+j].Sub(&a[shift+j], &tmp)
+		}
+	}
+	return trim(a[:min(len(a), len(b)-1)])
+}
+
+// polyGcdDegree returns the degree of gcd(a, b), -1 if a = b = 0.
+func polyGcdDegree(a, b []fr.Element) int {
+	a = trim(append([]fr.Element(nil), a...))
+	b = trim(append([]fr.Element(nil), b...))
+	for len(b) != 0 {
+		a, b = b, polyRem(a, b)
+	}
+	return len(a) - 1
+}
+
+// trim removes the leading zero coefficients of a
+func trim(a []fr.Element) []fr.Element {
+	for len(a) > 0 && a[len(a)-1].IsZero() {
+		a = a[:len(a)-1]
+	}
+	return a
+}
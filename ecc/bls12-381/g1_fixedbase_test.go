@@ -0,0 +1,169 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package bls12381
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/leanovate/gopter"
+	"github.com/leanovate/gopter/gen"
+	"github.com/leanovate/gopter/prop"
+)
+
+func TestG1FixedBaseTable(t *testing.T) {
+	t.Parallel()
+	parameters := gopter.DefaultTestParameters()
+	if testing.Short() {
+		parameters.MinSuccessfulTests = nbFuzzShort
+	} else {
+		parameters.MinSuccessfulTests = nbFuzz
+	}
+
+	properties := gopter.NewProperties(parameters)
+
+	// a random point of the subgroup, and tables for all the window sizes
+	var k fr.Element
+	k.SetRandom()
+	var base G1Affine
+	base.ScalarMultiplication(&g1GenAff, k.BigInt(new(big.Int)))
+	var tables []*G1FixedBaseTable
+	for c := uint64(2); c <= 10; c++ {
+		table, err := NewG1FixedBaseTable(&base, c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tables = append(tables, table)
+	}
+
+	properties.Property("[BLS12-381] ScalarMultiplicationFixedBase should output the same result as ScalarMultiplication", prop.ForAll(
+		func(s fr.Element, w uint64) bool {
+			var bs big.Int
+			s.BigInt(&bs)
+			var expected, res G1Affine
+			expected.ScalarMultiplication(&base, &bs)
+			res.ScalarMultiplicationFixedBase(tables[w], &bs)
+			return res.Equal(&expected)
+		},
+		GenFr(),
+		gen.UInt64Range(0, 8),
+	))
+
+	properties.Property("[BLS12-381] ScalarMultiplicationBase should output the same result as ScalarMultiplication for any integer", prop.ForAll(
+		func(s fr.Element, mul int64) bool {
+			// scalars larger than the order and negative
+			var bs big.Int
+			s.BigInt(&bs)
+			bs.Mul(&bs, big.NewInt(mul))
+			var expected, res G1Jac
+			expected.ScalarMultiplication(&g1Gen, &bs)
+			res.ScalarMultiplicationBase(&bs)
+			return res.Equal(&expected)
+		},
+		GenFr(),
+		gen.Int64Range(-3, 3),
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+
+	// edge cases
+	var zero, one, minusOne fr.Element
+	one.SetOne()
+	minusOne.Neg(&one)
+	scalars := []fr.Element{zero, one, minusOne}
+	res := tables[6].BatchScalarMultiplication(scalars)
+	if !res[0].IsInfinity() || !res[1].Equal(&base) {
+		t.Fatal("wrong multiplication by 0 or 1")
+	}
+	var neg G1Affine
+	neg.Neg(&base)
+	if !res[2].Equal(&neg) {
+		t.Fatal("wrong multiplication by -1")
+	}
+
+	if _, err := NewG1FixedBaseTable(&base, 1); err == nil {
+		t.Fatal("window size 1 should be rejected")
+	}
+}
+
+func TestG1FixedBaseTableSerialization(t *testing.T) {
+	t.Parallel()
+	table, err := NewG1FixedBaseTable(&g1GenAff, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	written, err := table.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	encoded := buf.Bytes()
+
+	for _, unsafe := range []bool{false, true} {
+		var read G1FixedBaseTable
+		var n int64
+		if unsafe {
+			n, err = read.UnsafeReadFrom(bytes.NewReader(encoded))
+		} else {
+			n, err = read.ReadFrom(bytes.NewReader(encoded))
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != written {
+			t.Fatal("wrong number of bytes read")
+		}
+		if read.WindowSize() != table.WindowSize() || len(read.table) != len(table.table) {
+			t.Fatal("wrong table shape")
+		}
+		for j := range table.table {
+			for d := range table.table[j] {
+				if !read.table[j][d].Equal(&table.table[j][d]) {
+					t.Fatal("wrong point")
+				}
+			}
+		}
+	}
+
+	// truncated
+	var read G1FixedBaseTable
+	if _, err = read.ReadFrom(bytes.NewReader(encoded[:len(encoded)-1])); err == nil {
+		t.Fatal("a truncated table should be rejected")
+	}
+}
+
+func BenchmarkG1JacScalarMultiplicationBase(b *testing.B) {
+	var scalar big.Int
+	scalar.SetString("5243587517512619047944770508185965837690552500527637822603658699938581184513", 10)
+	var p G1Jac
+
+	b.Run("GLV", func(b *testing.B) {
+		for j := 0; j < b.N; j++ {
+			p.ScalarMultiplication(&g1Gen, &scalar)
+		}
+	})
+	b.Run("fixed base", func(b *testing.B) {
+		g1GeneratorTable()
+		b.ResetTimer()
+		for j := 0; j < b.N; j++ {
+			p.ScalarMultiplicationBase(&scalar)
+		}
+	})
+}
@@ -21,9 +21,7 @@ import (
 	"github.com/consensys/gnark-crypto/ecc"
 	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
 	"github.com/consensys/gnark-crypto/ecc/bls12-381/internal/fptower"
-	"github.com/consensys/gnark-crypto/internal/parallel"
 	"math/big"
-	"runtime"
 )
 
 // G2Affine is a point in affine coordinates (x,y)
@@ -75,9 +73,11 @@ func (p *G2Affine) ScalarMultiplication(a *G2Affine, s *big.Int) *G2Affine {
 
 // ScalarMultiplicationBase computes and returns p = [s]g
 // where g is the affine point generating the prime subgroup.
+//
+// It uses a table of precomputed multiples of g, computed on first use.
 func (p *G2Affine) ScalarMultiplicationBase(s *big.Int) *G2Affine {
 	var _p G2Jac
-	_p.mulGLV(&g2Gen, s)
+	_p.ScalarMultiplicationBase(s)
 	p.FromJacobian(&_p)
 	return p
 }
@@ -450,9 +450,10 @@ func (p *G2Jac) ScalarMultiplication(q *G2Jac, s *big.Int) *G2Jac {
 
 // ScalarMultiplicationBase computes and returns p = [s]g
 // where g is the prime subgroup generator.
+//
+// It uses a table of precomputed multiples of g, computed on first use.
 func (p *G2Jac) ScalarMultiplicationBase(s *big.Int) *G2Jac {
-	return p.mulGLV(&g2Gen, s)
-
+	return p.ScalarMultiplicationFixedBase(g2GeneratorTable(), s)
 }
 
 // String converts p to affine coordinates and returns its string representation E(x,y) or "O" if it is infinity.
@@ -1004,83 +1005,23 @@ func (p *g2Proj) FromAffine(a *G2Affine) *g2Proj {
 
 // BatchScalarMultiplicationG2 multiplies the same base by all scalars
 // and return resulting points in affine coordinates
-// uses a simple windowed-NAF-like multiplication algorithm.
+// uses a table of precomputed multiples of the base (see G2FixedBaseTable).
 func BatchScalarMultiplicationG2(base *G2Affine, scalars []fr.Element) []G2Affine {
 	// approximate cost in group ops is
-	// cost = 2^{c-1} + n(scalar.nbBits+nbChunks)
-
+	// cost = nbWindows(2^{c-1} + n)
 	nbPoints := uint64(len(scalars))
 	min := ^uint64(0)
-	bestC := 0
-	for c := 2; c <= 16; c++ {
-		cost := uint64(1 << (c - 1)) // pre compute the table
-		nbChunks := computeNbChunks(uint64(c))
-		cost += nbPoints * (uint64(c) + 1) * nbChunks // doublings + point add
+	bestC := uint64(0)
+	for c := uint64(2); c <= 16; c++ {
+		cost := uint64(fixedBaseNbWindows(c)) * (uint64(1)<<(c-1) + nbPoints)
 		if cost < min {
 			min = cost
 			bestC = c
 		}
 	}
-	c := uint64(bestC) // window size
-	nbChunks := int(computeNbChunks(c))
-
-	// last window may be slightly larger than c; in which case we need to compute one
-	// extra element in the baseTable
-	maxC := lastC(c)
-	if c > maxC {
-		maxC = c
-	}
 
-	// precompute all powers of base for our window
-	// note here that if performance is critical, we can implement as in the msmX methods
-	// this allocation to be on the stack
-	baseTable := make([]G2Jac, (1 << (maxC - 1)))
-	baseTable[0].FromAffine(base)
-	for i := 1; i < len(baseTable); i++ {
-		baseTable[i] = baseTable[i-1]
-		baseTable[i].AddMixed(base)
-	}
-	toReturn := make([]G2Affine, len(scalars))
-
-	// partition the scalars into digits
-	digits, _ := partitionScalars(scalars, c, runtime.NumCPU())
-
-	// for each digit, take value in the base table, double it c time, voilà.
-	parallel.Execute(len(scalars), func(start, end int) {
-		var p G2Jac
-		for i := start; i < end; i++ {
-			p.Set(&g2Infinity)
-			for chunk := nbChunks - 1; chunk >= 0; chunk-- {
-				if chunk != nbChunks-1 {
-					for j := uint64(0); j < c; j++ {
-						p.DoubleAssign()
-					}
-				}
-				offset := chunk * len(scalars)
-				digit := digits[i+offset]
-
-				if digit == 0 {
-					continue
-				}
-
-				// if msbWindow bit is set, we need to subtract
-				if digit&1 == 0 {
-					// add
-					p.AddAssign(&baseTable[(digit>>1)-1])
-				} else {
-					// sub
-					t := baseTable[digit>>1]
-					t.Neg(&t)
-					p.AddAssign(&t)
-				}
-			}
-
-			// set our result point
-			toReturn[i].FromJacobian(&p)
-
-		}
-	})
-	return toReturn
+	table, _ := NewG2FixedBaseTable(base, bestC)
+	return table.BatchScalarMultiplication(scalars)
 }
 
 // batchAddG2Affine adds affine points using the Montgomery batch inversion trick.
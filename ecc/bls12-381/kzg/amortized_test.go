@@ -0,0 +1,129 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package kzg
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr/fft"
+)
+
+func TestOpenAll(t *testing.T) {
+	assert := require.New(t)
+
+	const n = 32
+	domain := fft.NewDomain(n)
+	for _, size := range []int{n, 20, 2} {
+		p := randomPolynomial(size)
+		digest, err := Commit(p, testSrs.Pk)
+		assert.NoError(err)
+
+		proofs, err := OpenAll(p, domain, testSrs.Pk)
+		assert.NoError(err)
+		assert.Equal(n, len(proofs))
+
+		var omegaJ fr.Element
+		omegaJ.SetOne()
+		for j := range proofs {
+			expected, err := Open(p, omegaJ, testSrs.Pk)
+			assert.NoError(err)
+			assert.Equal(expected, proofs[j], "wrong proof at ω^%d", j)
+			assert.NoError(Verify(&digest, &proofs[j], omegaJ, testSrs.Vk))
+			omegaJ.Mul(&omegaJ, &domain.Generator)
+		}
+	}
+
+	// the polynomial should fit in the domain and the domain in the SRS
+	_, err := OpenAll(randomPolynomial(n+1), domain, testSrs.Pk)
+	assert.ErrorIs(err, ErrInvalidPolynomialSize)
+	_, err = OpenAll(randomPolynomial(n), fft.NewDomain(uint64(2*len(testSrs.Pk.G1))), testSrs.Pk)
+	assert.ErrorIs(err, ErrInvalidPolynomialSize)
+}
+
+func TestOpenAllCosets(t *testing.T) {
+	assert := require.New(t)
+
+	const n = 64
+	domain := fft.NewDomain(n)
+	p := randomPolynomial(n)
+	digest, err := Commit(p, testSrs.Pk)
+	assert.NoError(err)
+
+	for _, k := range []uint64{1, 2, 4, 16, n} {
+		t.Run(fmt.Sprintf("k=%d", k), func(t *testing.T) {
+			assert := require.New(t)
+
+			proofs, err := OpenAllCosets(p, k, domain, testSrs.Pk)
+			assert.NoError(err)
+			assert.Equal(int(n/k), len(proofs))
+
+			// [τᵏ]G₂
+			var bTauK big.Int
+			bTauK.Exp(bAlpha, new(big.Int).SetUint64(k), fr.Modulus())
+			var g2TauK = testSrs.Vk.G2[0]
+			g2TauK.ScalarMultiplication(&g2TauK, &bTauK)
+
+			// generator of the subgroup of order k
+			var zeta fr.Element
+			zeta.Exp(domain.Generator, big.NewInt(int64(n/k)))
+
+			var z fr.Element
+			z.SetOne()
+			for j := range proofs {
+				assert.NoError(VerifyCosetOpening(&digest, &proofs[j], z, testSrs.Pk, g2TauK), "coset %d", j)
+
+				// the claimed values are the evaluations on the coset
+				var x fr.Element
+				x.Set(&z)
+				for i := range proofs[j].ClaimedValues {
+					y := eval(p, x)
+					assert.True(y.Equal(&proofs[j].ClaimedValues[i]))
+					x.Mul(&x, &zeta)
+				}
+				z.Mul(&z, &domain.Generator)
+			}
+
+			// wrong claimed value or coset
+			proofs[0].ClaimedValues[k-1].SetOne()
+			assert.ErrorIs(VerifyCosetOpening(&digest, &proofs[0], z, testSrs.Pk, g2TauK), ErrVerifyCosetOpening)
+			if len(proofs) > 1 {
+				assert.ErrorIs(VerifyCosetOpening(&digest, &proofs[1], z, testSrs.Pk, g2TauK), ErrVerifyCosetOpening)
+			}
+		})
+	}
+
+	_, err = OpenAllCosets(p, 3, domain, testSrs.Pk)
+	assert.ErrorIs(err, ErrCosetSize)
+	_, err = OpenAllCosets(p, 2*n, domain, testSrs.Pk)
+	assert.ErrorIs(err, ErrCosetSize)
+}
+
+func BenchmarkOpenAll(b *testing.B) {
+	const n = 128
+	domain := fft.NewDomain(n)
+	p := randomPolynomial(n)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = OpenAll(p, domain, testSrs.Pk)
+	}
+}
@@ -0,0 +1,24 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+// Package eip4844 implements the KZG commitments to blobs of EIP-4844, as
+// specified in the polynomial commitments of the Deneb consensus specs.
+//
+// A blob is a polynomial of degree less than FieldElementsPerBlob, given by its
+// evaluations on the roots of unity in bit-reversed order. The functions of the
+// specs are methods of a Context, built from the trusted setup of the Ethereum
+// KZG ceremony with LoadTrustedSetup.
+package eip4844
@@ -0,0 +1,461 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package eip4844
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math/big"
+	"math/bits"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/kzg"
+)
+
+const (
+	// FieldElementsPerBlob is the number of field elements of a blob
+	FieldElementsPerBlob = 4096
+
+	// BytesPerFieldElement is the size of the big-endian encoding of a field element
+	BytesPerFieldElement = fr.Bytes
+
+	// BytesPerBlob is the size of a blob
+	BytesPerBlob = FieldElementsPerBlob * BytesPerFieldElement
+
+	// BytesPerCommitment is the size of the compressed encoding of a commitment
+	BytesPerCommitment = bls12381.SizeOfG1AffineCompressed
+
+	// BytesPerProof is the size of the compressed encoding of a proof
+	BytesPerProof = bls12381.SizeOfG1AffineCompressed
+)
+
+// domain separation tags of the Fiat-Shamir challenges
+const (
+	fiatShamirProtocolDomain      = "FSBLOBVERIFY_V1_"
+	randomChallengeKzgBatchDomain = "RCKZGBATCH___V1_"
+)
+
+var (
+	ErrInvalidFieldElement = errors.New("invalid field element: not in canonical form")
+	ErrInvalidPoint        = errors.New("invalid point encoding")
+	ErrInvalidBatchSize    = errors.New("the numbers of blobs, commitments and proofs don't match")
+	ErrSetupSize           = errors.New("the setup should have at least FieldElementsPerBlob points")
+	ErrVerifyProof         = errors.New("can't verify the proof")
+)
+
+// Blob is a polynomial in evaluation form, given by its FieldElementsPerBlob
+// big-endian encoded evaluations on the roots of unity in bit-reversed order.
+type Blob [BytesPerBlob]byte
+
+// Commitment is the compressed encoding of a KZG commitment to a blob
+type Commitment [BytesPerCommitment]byte
+
+// Proof is the compressed encoding of a KZG opening proof
+type Proof [BytesPerProof]byte
+
+// Scalar is the big-endian encoding of a field element
+type Scalar [BytesPerFieldElement]byte
+
+// Context holds the trusted setup in the form used by the EIP-4844 functions.
+type Context struct {
+	lagrange []bls12381.G1Affine // [Lᵢ(τ)]G₁, in bit-reversed order
+	vk       kzg.VerifyingKey
+	roots    []fr.Element // ωⁱ, in bit-reversed order
+	nInv     fr.Element   // 1/FieldElementsPerBlob
+}
+
+// NewContext returns a Context from the SRS of the Ethereum KZG ceremony, of
+// which the FieldElementsPerBlob first points are used.
+func NewContext(srs *kzg.SRS) (*Context, error) {
+	if len(srs.Pk.G1) < FieldElementsPerBlob {
+		return nil, ErrSetupSize
+	}
+	lagrange, err := kzg.ToLagrangeG1(srs.Pk.G1[:FieldElementsPerBlob])
+	if err != nil {
+		return nil, err
+	}
+	bitReverse(lagrange)
+
+	// ω = 7^((r-1)/FieldElementsPerBlob), as in fr.Generator
+	omega, err := fr.Generator(FieldElementsPerBlob)
+	if err != nil {
+		return nil, err
+	}
+	roots := make([]fr.Element, FieldElementsPerBlob)
+	roots[0].SetOne()
+	for i := 1; i < len(roots); i++ {
+		roots[i].Mul(&roots[i-1], &omega)
+	}
+	bitReverse(roots)
+
+	c := &Context{
+		lagrange: lagrange,
+		vk:       srs.Vk,
+		roots:    roots,
+	}
+	c.nInv.SetUint64(FieldElementsPerBlob).Inverse(&c.nInv)
+	return c, nil
+}
+
+// LoadTrustedSetup reads the trusted setup of the Ethereum KZG ceremony in JSON
+// format and returns the corresponding Context, see kzg.ImportEthereumTrustedSetup.
+func LoadTrustedSetup(r io.Reader) (*Context, error) {
+	srs, err := kzg.ImportEthereumTrustedSetup(r, FieldElementsPerBlob)
+	if err != nil {
+		return nil, err
+	}
+	return NewContext(srs)
+}
+
+// BlobToKZGCommitment returns the commitment to a blob (blob_to_kzg_commitment)
+func (c *Context) BlobToKZGCommitment(blob *Blob) (Commitment, error) {
+	p, err := blobToPolynomial(blob)
+	if err != nil {
+		return Commitment{}, err
+	}
+	var commitment bls12381.G1Affine
+	if _, err := commitment.MultiExp(c.lagrange, p, ecc.MultiExpConfig{}); err != nil {
+		return Commitment{}, err
+	}
+	return commitment.Bytes(), nil
+}
+
+// ComputeKZGProof returns the proof of the evaluation of a blob at z, along
+// with the evaluation (compute_kzg_proof)
+func (c *Context) ComputeKZGProof(blob *Blob, z Scalar) (Proof, Scalar, error) {
+	p, err := blobToPolynomial(blob)
+	if err != nil {
+		return Proof{}, Scalar{}, err
+	}
+	zz, err := bytesToField(z[:])
+	if err != nil {
+		return Proof{}, Scalar{}, err
+	}
+	proof, y, err := c.computeProof(p, zz)
+	if err != nil {
+		return Proof{}, Scalar{}, err
+	}
+	return proof.Bytes(), y.Bytes(), nil
+}
+
+// ComputeBlobKZGProof returns the proof of the evaluation of a blob at the
+// Fiat-Shamir challenge derived from the blob and its commitment
+// (compute_blob_kzg_proof). The commitment is not checked against the blob.
+func (c *Context) ComputeBlobKZGProof(blob *Blob, commitment Commitment) (Proof, error) {
+	if _, err := bytesToPoint(commitment[:]); err != nil {
+		return Proof{}, err
+	}
+	p, err := blobToPolynomial(blob)
+	if err != nil {
+		return Proof{}, err
+	}
+	proof, _, err := c.computeProof(p, computeChallenge(blob, commitment))
+	if err != nil {
+		return Proof{}, err
+	}
+	return proof.Bytes(), nil
+}
+
+// VerifyKZGProof verifies the proof that the blob committed to evaluates to y
+// at z (verify_kzg_proof)
+func (c *Context) VerifyKZGProof(commitment Commitment, z, y Scalar, proof Proof) error {
+	cm, err := bytesToPoint(commitment[:])
+	if err != nil {
+		return err
+	}
+	zz, err := bytesToField(z[:])
+	if err != nil {
+		return err
+	}
+	yy, err := bytesToField(y[:])
+	if err != nil {
+		return err
+	}
+	h, err := bytesToPoint(proof[:])
+	if err != nil {
+		return err
+	}
+	return c.verifyProof(cm, zz, yy, h)
+}
+
+// VerifyBlobKZGProof verifies the proof returned by ComputeBlobKZGProof
+// (verify_blob_kzg_proof)
+func (c *Context) VerifyBlobKZGProof(blob *Blob, commitment Commitment, proof Proof) error {
+	cm, err := bytesToPoint(commitment[:])
+	if err != nil {
+		return err
+	}
+	p, err := blobToPolynomial(blob)
+	if err != nil {
+		return err
+	}
+	h, err := bytesToPoint(proof[:])
+	if err != nil {
+		return err
+	}
+	z := computeChallenge(blob, commitment)
+	return c.verifyProof(cm, z, c.evaluate(p, z), h)
+}
+
+// VerifyBlobKZGProofBatch verifies the proofs of several blobs with a single
+// pairing check (verify_blob_kzg_proof_batch)
+func (c *Context) VerifyBlobKZGProofBatch(blobs []Blob, commitments []Commitment, proofs []Proof) error {
+	if len(blobs) != len(commitments) || len(blobs) != len(proofs) {
+		return ErrInvalidBatchSize
+	}
+	if len(blobs) == 0 {
+		return nil
+	}
+
+	cms := make([]bls12381.G1Affine, len(blobs))
+	hs := make([]bls12381.G1Affine, len(blobs))
+	zs := make([]fr.Element, len(blobs))
+	ys := make([]fr.Element, len(blobs))
+	for i := range blobs {
+		var err error
+		if cms[i], err = bytesToPoint(commitments[i][:]); err != nil {
+			return err
+		}
+		p, err := blobToPolynomial(&blobs[i])
+		if err != nil {
+			return err
+		}
+		if hs[i], err = bytesToPoint(proofs[i][:]); err != nil {
+			return err
+		}
+		zs[i] = computeChallenge(&blobs[i], commitments[i])
+		ys[i] = c.evaluate(p, zs[i])
+	}
+	return c.verifyProofBatch(cms, zs, ys, hs)
+}
+
+// computeProof returns the proof that p(z) = y, and y
+func (c *Context) computeProof(p []fr.Element, z fr.Element) (bls12381.G1Affine, fr.Element, error) {
+	y := c.evaluate(p, z)
+
+	// q(ωᵢ) = (p(ωᵢ) - y) / (ωᵢ - z)
+	q := make([]fr.Element, FieldElementsPerBlob)
+	for i := range q {
+		q[i].Sub(&c.roots[i], &z)
+	}
+	q = fr.BatchInvert(q)
+	m := -1
+	for i := range q {
+		if q[i].IsZero() {
+			// z = ωᵢ
+			m = i
+			continue
+		}
+		var t fr.Element
+		t.Sub(&p[i], &y)
+		q[i].Mul(&q[i], &t)
+	}
+
+	// if z = ωₘ, q(ωₘ) = ∑_{i≠m} (p(ωᵢ) - y)ωᵢ / (z(z - ωᵢ)), where q[i] = (p(ωᵢ) - y) / (ωᵢ - z)
+	if m >= 0 {
+		var sum, t fr.Element
+		for i := range q {
+			if i != m {
+				t.Mul(&q[i], &c.roots[i])
+				sum.Add(&sum, &t)
+			}
+		}
+		t.Inverse(&z)
+		q[m].Mul(&sum, &t).Neg(&q[m])
+	}
+
+	var proof bls12381.G1Affine
+	if _, err := proof.MultiExp(c.lagrange, q, ecc.MultiExpConfig{}); err != nil {
+		return proof, y, err
+	}
+	return proof, y, nil
+}
+
+// evaluate returns p(z) where p is given by its evaluations on the roots of
+// unity in bit-reversed order, with the barycentric formula
+// p(z) = (zⁿ - 1)/n ∑ p(ωᵢ)ωᵢ/(z - ωᵢ)
+func (c *Context) evaluate(p []fr.Element, z fr.Element) fr.Element {
+	for i := range c.roots {
+		if z.Equal(&c.roots[i]) {
+			return p[i]
+		}
+	}
+	den := make([]fr.Element, FieldElementsPerBlob)
+	for i := range den {
+		den[i].Sub(&z, &c.roots[i])
+	}
+	den = fr.BatchInvert(den)
+
+	var res, t fr.Element
+	for i := range p {
+		t.Mul(&p[i], &c.roots[i]).Mul(&t, &den[i])
+		res.Add(&res, &t)
+	}
+	var one fr.Element
+	one.SetOne()
+	t.Exp(z, big.NewInt(FieldElementsPerBlob)).Sub(&t, &one).Mul(&t, &c.nInv)
+	res.Mul(&res, &t)
+	return res
+}
+
+// verifyProof checks that e(C - [y]G₁ + [z]H, G₂) = e(H, [τ]G₂)
+func (c *Context) verifyProof(commitment bls12381.G1Affine, z, y fr.Element, h bls12381.G1Affine) error {
+	proof := kzg.OpeningProof{H: h, ClaimedValue: y}
+	if err := kzg.Verify(&commitment, &proof, z, c.vk); err != nil {
+		if errors.Is(err, kzg.ErrVerifyOpeningProof) {
+			return ErrVerifyProof
+		}
+		return err
+	}
+	return nil
+}
+
+// verifyProofBatch checks the proofs with a random linear combination, whose
+// coefficients are the powers of r derived from all the inputs:
+// e(∑ rⁱHᵢ, [τ]G₂) = e(∑ rⁱ(Cᵢ - [yᵢ]G₁ + [zᵢ]Hᵢ), G₂)
+func (c *Context) verifyProofBatch(commitments []bls12381.G1Affine, zs, ys []fr.Element, hs []bls12381.G1Affine) error {
+	n := len(commitments)
+
+	// r = H(domain ∥ FieldElementsPerBlob ∥ n ∥ (Cᵢ ∥ zᵢ ∥ yᵢ ∥ Hᵢ)ᵢ) mod r
+	h := sha256.New()
+	h.Write([]byte(randomChallengeKzgBatchDomain))
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], FieldElementsPerBlob)
+	h.Write(buf[:])
+	binary.BigEndian.PutUint64(buf[:], uint64(n))
+	h.Write(buf[:])
+	for i := 0; i < n; i++ {
+		cm := commitments[i].Bytes()
+		z := zs[i].Bytes()
+		y := ys[i].Bytes()
+		proof := hs[i].Bytes()
+		h.Write(cm[:])
+		h.Write(z[:])
+		h.Write(y[:])
+		h.Write(proof[:])
+	}
+	var r fr.Element
+	r.SetBytes(h.Sum(nil))
+
+	// the linear combinations are computed as a single multi-exponentiation
+	// ∑ rⁱHᵢ and ∑ rⁱCᵢ + ∑ rⁱzᵢHᵢ + [-∑ rⁱyᵢ]G₁
+	rPowers := make([]fr.Element, n)
+	rPowers[0].SetOne()
+	for i := 1; i < n; i++ {
+		rPowers[i].Mul(&rPowers[i-1], &r)
+	}
+	points := make([]bls12381.G1Affine, 0, 2*n+1)
+	scalars := make([]fr.Element, 0, 2*n+1)
+	var sumY fr.Element
+	for i := 0; i < n; i++ {
+		var rz, ry fr.Element
+		rz.Mul(&rPowers[i], &zs[i])
+		ry.Mul(&rPowers[i], &ys[i])
+		sumY.Add(&sumY, &ry)
+		points = append(points, commitments[i], hs[i])
+		scalars = append(scalars, rPowers[i], rz)
+	}
+	sumY.Neg(&sumY)
+	points = append(points, c.vk.G1)
+	scalars = append(scalars, sumY)
+
+	var left, right bls12381.G1Affine
+	if _, err := left.MultiExp(hs, rPowers, ecc.MultiExpConfig{}); err != nil {
+		return err
+	}
+	if _, err := right.MultiExp(points, scalars, ecc.MultiExpConfig{}); err != nil {
+		return err
+	}
+
+	// e(-∑ rⁱHᵢ, [τ]G₂).e(∑ rⁱ(Cᵢ - [yᵢ]G₁ + [zᵢ]Hᵢ), G₂) = 1
+	// the lines are copied, as the Miller loop evaluates them in place
+	left.Neg(&left)
+	lines := c.vk.Lines
+	ok, err := bls12381.PairingCheckFixedQ(
+		[]bls12381.G1Affine{right, left},
+		lines[:],
+	)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrVerifyProof
+	}
+	return nil
+}
+
+// computeChallenge returns the evaluation point of a blob
+// H(domain ∥ FieldElementsPerBlob ∥ blob ∥ commitment) mod r
+func computeChallenge(blob *Blob, commitment Commitment) fr.Element {
+	h := sha256.New()
+	h.Write([]byte(fiatShamirProtocolDomain))
+	var degree [16]byte
+	binary.BigEndian.PutUint64(degree[8:], FieldElementsPerBlob)
+	h.Write(degree[:])
+	h.Write(blob[:])
+	h.Write(commitment[:])
+	var z fr.Element
+	z.SetBytes(h.Sum(nil))
+	return z
+}
+
+// blobToPolynomial decodes the evaluations of a blob
+func blobToPolynomial(blob *Blob) ([]fr.Element, error) {
+	p := make([]fr.Element, FieldElementsPerBlob)
+	for i := range p {
+		var err error
+		if p[i], err = bytesToField(blob[i*BytesPerFieldElement : (i+1)*BytesPerFieldElement]); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+// bytesToField decodes a field element in canonical big-endian form
+func bytesToField(b []byte) (fr.Element, error) {
+	var e fr.Element
+	if err := e.SetBytesCanonical(b); err != nil {
+		return e, ErrInvalidFieldElement
+	}
+	return e, nil
+}
+
+// bytesToPoint decodes a compressed point of G₁, and checks that it is in the
+// subgroup
+func bytesToPoint(b []byte) (bls12381.G1Affine, error) {
+	var p bls12381.G1Affine
+	if _, err := p.SetBytes(b); err != nil {
+		return p, ErrInvalidPoint
+	}
+	return p, nil
+}
+
+// bitReverse permutes v in bit-reversed order
+func bitReverse[T any](v []T) {
+	n := uint64(len(v))
+	nn := uint64(64 - bits.TrailingZeros64(n))
+	for i := uint64(0); i < n; i++ {
+		iRev := bits.Reverse64(i) >> nn
+		if iRev > i {
+			v[i], v[iRev] = v[iRev], v[i]
+		}
+	}
+}
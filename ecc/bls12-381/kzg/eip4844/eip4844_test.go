@@ -0,0 +1,295 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package eip4844
+
+import (
+	"encoding/hex"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr/fft"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/kzg"
+)
+
+// a subset of the consensus specs test vectors, flattened as
+// <handler>/<case>.yaml, and the trusted setup of the Ethereum KZG ceremony
+var (
+	testDir          = "../../testing/kzg"
+	trustedSetupFile = filepath.Join(testDir, "trusted_setup.json")
+)
+
+// testContext is an insecure context, whose τ is known
+var testContext *Context
+var testSrs *kzg.SRS
+
+func init() {
+	testSrs, _ = kzg.NewSRS(FieldElementsPerBlob, big.NewInt(1789))
+	testContext, _ = NewContext(testSrs)
+}
+
+func randomBlob() *Blob {
+	var blob Blob
+	for i := 0; i < FieldElementsPerBlob; i++ {
+		var e fr.Element
+		e.SetRandom()
+		e.BigInt(new(big.Int)).FillBytes(blob[i*BytesPerFieldElement : (i+1)*BytesPerFieldElement])
+	}
+	return &blob
+}
+
+// monomial returns the coefficients of the polynomial of a blob
+func monomial(t *testing.T, blob *Blob) []fr.Element {
+	p, err := blobToPolynomial(blob)
+	require.NoError(t, err)
+	domain := fft.NewDomain(FieldElementsPerBlob)
+	domain.FFTInverse(p, fft.DIT)
+	return p
+}
+
+func TestBlobToKZGCommitment(t *testing.T) {
+	assert := require.New(t)
+
+	// the commitment in Lagrange basis matches the commitment in monomial basis
+	blob := randomBlob()
+	commitment, err := testContext.BlobToKZGCommitment(blob)
+	assert.NoError(err)
+	expected, err := kzg.Commit(monomial(t, blob), testSrs.Pk)
+	assert.NoError(err)
+	assert.Equal(expected.Bytes(), [BytesPerCommitment]byte(commitment))
+
+	// the commitment to the zero polynomial is the point at infinity
+	commitment, err = testContext.BlobToKZGCommitment(&Blob{})
+	assert.NoError(err)
+	infinity := Commitment{0xc0}
+	assert.Equal(infinity, commitment)
+
+	// the field elements should be canonical
+	blob[BytesPerFieldElement] = 0xff
+	_, err = testContext.BlobToKZGCommitment(blob)
+	assert.ErrorIs(err, ErrInvalidFieldElement)
+}
+
+func TestKZGProof(t *testing.T) {
+	assert := require.New(t)
+
+	blob := randomBlob()
+	p := monomial(t, blob)
+	commitment, err := testContext.BlobToKZGCommitment(blob)
+	assert.NoError(err)
+
+	// out of the domain and in the domain
+	var z fr.Element
+	z.SetRandom()
+	for _, point := range []fr.Element{z, testContext.roots[5]} {
+		zBytes := Scalar(point.Bytes())
+		proof, y, err := testContext.ComputeKZGProof(blob, zBytes)
+		assert.NoError(err)
+
+		// the evaluation matches the evaluation in monomial basis
+		expected, err := kzg.Open(p, point, testSrs.Pk)
+		assert.NoError(err)
+		assert.Equal(Scalar(expected.ClaimedValue.Bytes()), y)
+		assert.Equal(expected.H.Bytes(), [BytesPerProof]byte(proof))
+
+		assert.NoError(testContext.VerifyKZGProof(commitment, zBytes, y, proof))
+		y[BytesPerFieldElement-1] ^= 1
+		assert.ErrorIs(testContext.VerifyKZGProof(commitment, zBytes, y, proof), ErrVerifyProof)
+	}
+
+	// invalid inputs
+	var modulus Scalar
+	fr.Modulus().FillBytes(modulus[:])
+	_, _, err = testContext.ComputeKZGProof(blob, modulus)
+	assert.ErrorIs(err, ErrInvalidFieldElement)
+	err = testContext.VerifyKZGProof(Commitment{0x80, 1}, modulus, modulus, Proof{})
+	assert.ErrorIs(err, ErrInvalidPoint)
+}
+
+func TestBlobKZGProofBatch(t *testing.T) {
+	assert := require.New(t)
+
+	const nbBlobs = 4
+	blobs := make([]Blob, nbBlobs)
+	commitments := make([]Commitment, nbBlobs)
+	proofs := make([]Proof, nbBlobs)
+	for i := range blobs {
+		blobs[i] = *randomBlob()
+		var err error
+		commitments[i], err = testContext.BlobToKZGCommitment(&blobs[i])
+		assert.NoError(err)
+		proofs[i], err = testContext.ComputeBlobKZGProof(&blobs[i], commitments[i])
+		assert.NoError(err)
+		assert.NoError(testContext.VerifyBlobKZGProof(&blobs[i], commitments[i], proofs[i]))
+	}
+	assert.NoError(testContext.VerifyBlobKZGProofBatch(blobs, commitments, proofs))
+	assert.NoError(testContext.VerifyBlobKZGProofBatch(nil, nil, nil))
+	assert.ErrorIs(testContext.VerifyBlobKZGProofBatch(blobs, commitments, proofs[1:]), ErrInvalidBatchSize)
+
+	// swapped proofs
+	proofs[0], proofs[1] = proofs[1], proofs[0]
+	assert.ErrorIs(testContext.VerifyBlobKZGProof(&blobs[0], commitments[0], proofs[0]), ErrVerifyProof)
+	assert.ErrorIs(testContext.VerifyBlobKZGProofBatch(blobs, commitments, proofs), ErrVerifyProof)
+
+	// the precomputed lines of the verifying key are left unchanged
+	assert.Equal(testSrs.Vk.Lines, testContext.vk.Lines)
+}
+
+func decodeHex(s string, dst []byte) bool {
+	b, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil || len(b) != len(dst) {
+		return false
+	}
+	copy(dst, b)
+	return true
+}
+
+// TestSpecVectors runs the test vectors of the consensus specs in testDir,
+// with the trusted setup.
+func TestSpecVectors(t *testing.T) {
+	f, err := os.Open(trustedSetupFile)
+	require.NoError(t, err, "missing trusted setup")
+	ctx, err := LoadTrustedSetup(f)
+	require.NoError(t, f.Close())
+	require.NoError(t, err)
+
+	type input struct {
+		Blob        string   `yaml:"blob"`
+		Blobs       []string `yaml:"blobs"`
+		Commitment  string   `yaml:"commitment"`
+		Commitments []string `yaml:"commitments"`
+		Z           string   `yaml:"z"`
+		Y           string   `yaml:"y"`
+		Proof       string   `yaml:"proof"`
+		Proofs      []string `yaml:"proofs"`
+	}
+
+	// run decodes the test vectors of a handler, and checks that the output
+	// of f is the expected output, nil meaning that the inputs are invalid
+	run := func(handler string, f func(t *testing.T, in input) (interface{}, bool)) {
+		tests, err := filepath.Glob(filepath.Join(testDir, handler, "*.yaml"))
+		require.NoError(t, err)
+		require.NotEmpty(t, tests, "missing test vectors for %s", handler)
+		for _, testPath := range tests {
+			t.Run(handler+"/"+filepath.Base(testPath), func(t *testing.T) {
+				data, err := os.ReadFile(testPath)
+				require.NoError(t, err)
+				var test struct {
+					Input  input       `yaml:"input"`
+					Output interface{} `yaml:"output"`
+				}
+				require.NoError(t, yaml.Unmarshal(data, &test))
+				output, ok := f(t, test.Input)
+				if test.Output == nil {
+					require.False(t, ok, "invalid inputs should be rejected")
+					return
+				}
+				require.True(t, ok, "valid inputs should be accepted")
+				require.Equal(t, test.Output, output)
+			})
+		}
+	}
+
+	toHex := func(b []byte) string { return "0x" + hex.EncodeToString(b) }
+
+	run("blob_to_kzg_commitment", func(t *testing.T, in input) (interface{}, bool) {
+		var blob Blob
+		if !decodeHex(in.Blob, blob[:]) {
+			return nil, false
+		}
+		commitment, err := ctx.BlobToKZGCommitment(&blob)
+		return toHex(commitment[:]), err == nil
+	})
+
+	run("compute_kzg_proof", func(t *testing.T, in input) (interface{}, bool) {
+		var blob Blob
+		var z Scalar
+		if !decodeHex(in.Blob, blob[:]) || !decodeHex(in.Z, z[:]) {
+			return nil, false
+		}
+		proof, y, err := ctx.ComputeKZGProof(&blob, z)
+		return []interface{}{toHex(proof[:]), toHex(y[:])}, err == nil
+	})
+
+	run("compute_blob_kzg_proof", func(t *testing.T, in input) (interface{}, bool) {
+		var blob Blob
+		var commitment Commitment
+		if !decodeHex(in.Blob, blob[:]) || !decodeHex(in.Commitment, commitment[:]) {
+			return nil, false
+		}
+		proof, err := ctx.ComputeBlobKZGProof(&blob, commitment)
+		return toHex(proof[:]), err == nil
+	})
+
+	// the verification functions return false for a wrong proof, and an
+	// error for invalid inputs
+	verified := func(err error) (interface{}, bool) {
+		if err == ErrVerifyProof {
+			return false, true
+		}
+		return err == nil, err == nil
+	}
+
+	run("verify_kzg_proof", func(t *testing.T, in input) (interface{}, bool) {
+		var commitment Commitment
+		var z, y Scalar
+		var proof Proof
+		if !decodeHex(in.Commitment, commitment[:]) || !decodeHex(in.Z, z[:]) ||
+			!decodeHex(in.Y, y[:]) || !decodeHex(in.Proof, proof[:]) {
+			return nil, false
+		}
+		return verified(ctx.VerifyKZGProof(commitment, z, y, proof))
+	})
+
+	run("verify_blob_kzg_proof", func(t *testing.T, in input) (interface{}, bool) {
+		var blob Blob
+		var commitment Commitment
+		var proof Proof
+		if !decodeHex(in.Blob, blob[:]) || !decodeHex(in.Commitment, commitment[:]) || !decodeHex(in.Proof, proof[:]) {
+			return nil, false
+		}
+		return verified(ctx.VerifyBlobKZGProof(&blob, commitment, proof))
+	})
+
+	run("verify_blob_kzg_proof_batch", func(t *testing.T, in input) (interface{}, bool) {
+		blobs := make([]Blob, len(in.Blobs))
+		commitments := make([]Commitment, len(in.Commitments))
+		proofs := make([]Proof, len(in.Proofs))
+		for i := range blobs {
+			if !decodeHex(in.Blobs[i], blobs[i][:]) {
+				return nil, false
+			}
+		}
+		for i := range commitments {
+			if !decodeHex(in.Commitments[i], commitments[i][:]) {
+				return nil, false
+			}
+		}
+		for i := range proofs {
+			if !decodeHex(in.Proofs[i], proofs[i][:]) {
+				return nil, false
+			}
+		}
+		return verified(ctx.VerifyBlobKZGProofBatch(blobs, commitments, proofs))
+	})
+}
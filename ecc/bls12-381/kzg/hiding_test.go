@@ -0,0 +1,95 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package kzg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+func TestHiding(t *testing.T) {
+	assert := require.New(t)
+
+	const size = 32
+	srs, err := NewHidingSRS(size, bAlpha)
+	assert.NoError(err)
+
+	for _, n := range []int{size, 7, 1} {
+		p := randomPolynomial(n)
+		digest, blinding, err := CommitHiding(p, srs.Pk)
+		assert.NoError(err)
+
+		// the commitment is blinded
+		digest2, _, err := CommitHiding(p, srs.Pk)
+		assert.NoError(err)
+		assert.False(digest.Equal(&digest2), "commitments should be randomized")
+		unblinded, err := Commit(p, ProvingKey{G1: srs.Pk.G1})
+		assert.NoError(err)
+		assert.False(digest.Equal(&unblinded), "commitment should be blinded")
+
+		var point fr.Element
+		point.SetRandom()
+		proof, err := OpenHiding(p, blinding, point, srs.Pk)
+		assert.NoError(err)
+		expected := eval(p, point)
+		assert.True(expected.Equal(&proof.ClaimedValue), "wrong claimed value")
+		assert.NoError(VerifyHiding(&digest, &proof, point, srs.Vk))
+
+		// wrong point, claimed value or blinding value
+		var wrongPoint fr.Element
+		wrongPoint.SetRandom()
+		if n > 1 {
+			assert.ErrorIs(VerifyHiding(&digest, &proof, wrongPoint, srs.Vk), ErrVerifyHidingOpeningProof)
+		}
+		wrongProof := proof
+		wrongProof.ClaimedValue.Double(&proof.ClaimedValue)
+		assert.ErrorIs(VerifyHiding(&digest, &wrongProof, point, srs.Vk), ErrVerifyHidingOpeningProof)
+		wrongProof = proof
+		wrongProof.BlindingValue.Double(&proof.BlindingValue)
+		assert.ErrorIs(VerifyHiding(&digest, &wrongProof, point, srs.Vk), ErrVerifyHidingOpeningProof)
+		assert.ErrorIs(VerifyHiding(&digest2, &proof, point, srs.Vk), ErrVerifyHidingOpeningProof)
+	}
+
+	// wrong sizes
+	_, _, err = CommitHiding(randomPolynomial(size+1), srs.Pk)
+	assert.ErrorIs(err, ErrInvalidPolynomialSize)
+	_, err = OpenHiding(randomPolynomial(4), randomPolynomial(3), fr.One(), srs.Pk)
+	assert.ErrorIs(err, ErrInvalidPolynomialSize)
+}
+
+func BenchmarkOpenHiding(b *testing.B) {
+	const size = 256
+	srs, err := NewHidingSRS(size, bAlpha)
+	if err != nil {
+		b.Fatal(err)
+	}
+	p := randomPolynomial(size)
+	_, blinding, err := CommitHiding(p, srs.Pk)
+	if err != nil {
+		b.Fatal(err)
+	}
+	var point fr.Element
+	point.SetRandom()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = OpenHiding(p, blinding, point, srs.Pk)
+	}
+}
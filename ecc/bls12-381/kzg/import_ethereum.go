@@ -0,0 +1,111 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package kzg
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381"
+
+	"github.com/consensys/gnark-crypto/internal/parallel"
+)
+
+// ethereumTrustedSetup is the JSON format of the trusted setup of the Ethereum
+// KZG ceremony (EIP-4844), as distributed with the consensus specifications.
+// The points are hex encoded in compressed form.
+type ethereumTrustedSetup struct {
+	G1Monomial []string `json:"g1_monomial"`
+	G1Lagrange []string `json:"g1_lagrange"`
+	G2Monomial []string `json:"g2_monomial"`
+}
+
+// ImportEthereumTrustedSetup reads the trusted setup of the Ethereum KZG
+// ceremony (EIP-4844) in JSON format and returns the corresponding SRS. Only
+// the monomial form of the setup is used.
+//
+// The points are checked to be in the correct subgroups, and to be the
+// consecutive powers of a same τ. If maxPkPoints is provided, the number of
+// points in the ProvingKey will be limited to maxPkPoints.
+func ImportEthereumTrustedSetup(r io.Reader, maxPkPoints ...int) (*SRS, error) {
+	var setup ethereumTrustedSetup
+	if err := json.NewDecoder(r).Decode(&setup); err != nil {
+		return nil, err
+	}
+	if len(setup.G2Monomial) < 2 {
+		return nil, errors.New("the trusted setup should contain at least 2 points in G₂")
+	}
+
+	size := len(setup.G1Monomial)
+	if len(maxPkPoints) > 0 && maxPkPoints[0] < size && maxPkPoints[0] > 0 {
+		size = maxPkPoints[0]
+	}
+	if size < 2 {
+		return nil, ErrMinSRSSize
+	}
+
+	g1 := make([]bls12381.G1Affine, size)
+	var errG1 error
+	var errLock sync.Mutex
+	parallel.Execute(size, func(start, end int) {
+		for i := start; i < end; i++ {
+			if err := setPointFromHex(&g1[i], setup.G1Monomial[i]); err != nil {
+				errLock.Lock()
+				errG1 = err
+				errLock.Unlock()
+				return
+			}
+		}
+	})
+	if errG1 != nil {
+		return nil, errG1
+	}
+
+	var g2 [2]bls12381.G2Affine
+	for i := range g2 {
+		if err := setPointFromHex(&g2[i], setup.G2Monomial[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := checkPowers(g1, g2); err != nil {
+		return nil, err
+	}
+
+	return srsFromPowers(g1, g2), nil
+}
+
+// setPointFromHex sets p from its 0x-prefixed hex encoded compressed form,
+// and checks that it is in the correct subgroup.
+func setPointFromHex(p interface{ SetBytes([]byte) (int, error) }, s string) error {
+	b, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil {
+		return err
+	}
+	n, err := p.SetBytes(b)
+	if err != nil {
+		return err
+	}
+	if n != len(b) {
+		return errors.New("invalid point encoding: trailing bytes")
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package kzg
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381"
+)
+
+// ethereumTrustedSetupJSON encodes the powers of τ in the format of the
+// trusted setup of the Ethereum KZG ceremony
+func ethereumTrustedSetupJSON(t *testing.T, g1 []bls12381.G1Affine, g2 []bls12381.G2Affine) []byte {
+	var setup ethereumTrustedSetup
+	for i := range g1 {
+		b := g1[i].Bytes()
+		setup.G1Monomial = append(setup.G1Monomial, "0x"+hex.EncodeToString(b[:]))
+	}
+	for i := range g2 {
+		b := g2[i].Bytes()
+		setup.G2Monomial = append(setup.G2Monomial, "0x"+hex.EncodeToString(b[:]))
+	}
+	res, err := json.Marshal(setup)
+	require.NoError(t, err)
+	return res
+}
+
+func TestImportEthereumTrustedSetup(t *testing.T) {
+	assert := require.New(t)
+
+	const size = 64
+	tau := big.NewInt(1789)
+	expected, err := NewSRS(size, tau)
+	assert.NoError(err)
+	g2 := make([]bls12381.G2Affine, 3)
+	g2[0], g2[1] = expected.Vk.G2[0], expected.Vk.G2[1]
+	g2[2].ScalarMultiplication(&g2[1], tau)
+	setup := ethereumTrustedSetupJSON(t, expected.Pk.G1, g2)
+
+	srs, err := ImportEthereumTrustedSetup(bytes.NewReader(setup))
+	assert.NoError(err)
+	assert.Equal(expected, srs)
+
+	// truncated SRS
+	srs, err = ImportEthereumTrustedSetup(bytes.NewReader(setup), 10)
+	assert.NoError(err)
+	assert.Equal(expected.Pk.G1[:10], srs.Pk.G1)
+	assert.Equal(expected.Vk, srs.Vk)
+
+	// inconsistent powers
+	g1 := make([]bls12381.G1Affine, size)
+	copy(g1, expected.Pk.G1)
+	g1[10], g1[11] = g1[11], g1[10]
+	_, err = ImportEthereumTrustedSetup(bytes.NewReader(ethereumTrustedSetupJSON(t, g1, g2)))
+	assert.ErrorIs(err, ErrMpcSetupPowers)
+
+	_, err = ImportEthereumTrustedSetup(bytes.NewReader(ethereumTrustedSetupJSON(t, expected.Pk.G1, g2[1:])))
+	assert.ErrorIs(err, ErrMpcSetupGenerators)
+
+	// invalid encodings
+	_, err = ImportEthereumTrustedSetup(bytes.NewReader(setup[:len(setup)-1]))
+	assert.Error(err)
+	_, err = ImportEthereumTrustedSetup(bytes.NewReader([]byte(`{"g1_monomial": ["0x00"], "g2_monomial": ["0x00", "0x00"]}`)))
+	assert.Error(err)
+}
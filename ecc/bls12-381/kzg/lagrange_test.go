@@ -0,0 +1,97 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package kzg
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr/fft"
+)
+
+func TestOpenLagrange(t *testing.T) {
+	assert := require.New(t)
+
+	const size = 64
+	pkLagrange, err := NewProvingKeyLagrange(testSrs.Pk, size)
+	assert.NoError(err)
+
+	// polynomial in Lagrange form and canonical form
+	evaluations := make([]fr.Element, size)
+	for i := range evaluations {
+		evaluations[i].SetRandom()
+	}
+	coefficients := make([]fr.Element, size)
+	copy(coefficients, evaluations)
+	d := fft.NewDomain(size)
+	d.FFTInverse(coefficients, fft.DIF)
+	fft.BitReverse(coefficients)
+
+	digest, err := CommitLagrange(evaluations, pkLagrange)
+	assert.NoError(err)
+	expected, err := Commit(coefficients, testSrs.Pk)
+	assert.NoError(err)
+	assert.True(expected.Equal(&digest), "error CommitLagrange")
+
+	// out of the domain and in the domain
+	var point fr.Element
+	point.SetRandom()
+	var omega, one fr.Element
+	omega.Exp(d.Generator, big.NewInt(7))
+	one.SetOne()
+	for _, z := range []fr.Element{point, omega, d.Generator, one} {
+		proof, err := OpenLagrange(evaluations, z, pkLagrange)
+		assert.NoError(err)
+		expectedProof, err := Open(coefficients, z, testSrs.Pk)
+		assert.NoError(err)
+		assert.True(expectedProof.ClaimedValue.Equal(&proof.ClaimedValue), "wrong evaluation")
+		assert.True(expectedProof.H.Equal(&proof.H), "wrong quotient")
+		assert.NoError(Verify(&digest, &proof, z, testSrs.Vk))
+	}
+
+	// wrong sizes
+	_, err = OpenLagrange(evaluations[1:], point, pkLagrange)
+	assert.ErrorIs(err, ErrInvalidPolynomialSize)
+	_, err = CommitLagrange(evaluations[1:], pkLagrange)
+	assert.ErrorIs(err, ErrInvalidPolynomialSize)
+	_, err = NewProvingKeyLagrange(testSrs.Pk, 48)
+	assert.ErrorIs(err, ErrLagrangeDomainSize)
+	_, err = NewProvingKeyLagrange(testSrs.Pk, uint64(2*len(testSrs.Pk.G1)))
+	assert.ErrorIs(err, ErrLagrangeDomainSize)
+}
+
+func BenchmarkOpenLagrange(b *testing.B) {
+	const size = 256
+	pkLagrange, err := NewProvingKeyLagrange(testSrs.Pk, size)
+	if err != nil {
+		b.Fatal(err)
+	}
+	evaluations := make([]fr.Element, size)
+	for i := range evaluations {
+		evaluations[i].SetRandom()
+	}
+	var point fr.Element
+	point.SetRandom()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = OpenLagrange(evaluations, point, pkLagrange)
+	}
+}
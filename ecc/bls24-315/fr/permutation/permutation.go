@@ -167,14 +167,11 @@ func Prove(pk kzg.ProvingKey, t1, t2 []fr.Element) (Proof, error) {
 	d.FFTInverse(ct2, fft.DIF)
 	fft.BitReverse(ct1)
 	fft.BitReverse(ct2)
-	proof.t1, err = kzg.Commit(ct1, pk)
-	if err != nil {
-		return proof, err
-	}
-	proof.t2, err = kzg.Commit(ct2, pk)
+	digests, err := kzg.BatchCommit([][]fr.Element{ct1, ct2}, pk)
 	if err != nil {
 		return proof, err
 	}
+	proof.t1, proof.t2 = digests[0], digests[1]
 
 	// derive challenge for z
 	epsilon, err := deriveRandomness(fs, "epsilon", &proof.t1, &proof.t2)
@@ -0,0 +1,237 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package bls24315
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"math/big"
+	"sync"
+
+	"github.com/consensys/gnark-crypto/ecc/bls24-315/fr"
+	"github.com/consensys/gnark-crypto/internal/parallel"
+)
+
+// fixedBaseGeneratorWindow window size of the tables of the multiples of the
+// generators, used by ScalarMultiplicationBase
+const fixedBaseGeneratorWindow = 8
+
+// fixedBaseNbWindows returns the number of windows of c bits of the signed
+// digits of a scalar, ⌈(fr.Bits+1)/c⌉ so that the last digit has no carry.
+func fixedBaseNbWindows(c uint64) int {
+	return int((fr.Bits + c) / c)
+}
+
+// fixedBaseDigits sets digits to the signed digits of s in base 2ᶜ, which are
+// in [-2ᶜ⁻¹, 2ᶜ⁻¹].
+func fixedBaseDigits(digits []int32, s *fr.Element, c uint64) {
+	words := s.Bits()
+	mask := uint64(1)<<c - 1
+	var carry int32
+	for j := range digits {
+		start := uint64(j) * c
+		var w uint64
+		if idx := start / 64; idx < fr.Limbs {
+			shift := start % 64
+			w = words[idx] >> shift
+			if shift+c > 64 && idx+1 < fr.Limbs {
+				w |= words[idx+1] << (64 - shift)
+			}
+		}
+		d := int32(w&mask) + carry
+		carry = 0
+		if d > 1<<(c-1) {
+			d -= 1 << c
+			carry = 1
+		}
+		digits[j] = d
+	}
+}
+
+// G1FixedBaseTable precomputed multiples of a fixed point, which trade memory
+// for faster scalar multiplications by this point.
+//
+// With windows of c bits, table[j][d-1] = [d⋅2ᶜʲ]base for 1 ⩽ d ⩽ 2ᶜ⁻¹, so that
+// [s]base is the sum of the ±table[j][|sⱼ|-1] for the signed digits sⱼ of s in
+// base 2ᶜ, without any doubling. The table has ⌈(fr.Bits+1)/c⌉⋅2ᶜ⁻¹ points.
+type G1FixedBaseTable struct {
+	c     uint64
+	table [][]G1Affine
+}
+
+var (
+	g1GenTableOnce sync.Once
+	g1GenTable     *G1FixedBaseTable
+)
+
+// g1GeneratorTable returns the table of the multiples of the generator, which
+// is computed on first use.
+func g1GeneratorTable() *G1FixedBaseTable {
+	g1GenTableOnce.Do(func() {
+		g1GenTable, _ = NewG1FixedBaseTable(&g1GenAff, fixedBaseGeneratorWindow)
+	})
+	return g1GenTable
+}
+
+// NewG1FixedBaseTable returns the table of the multiples of base, which must be
+// in the prime order subgroup, with windows of c bits, 2 ⩽ c ⩽ 16.
+func NewG1FixedBaseTable(base *G1Affine, c uint64) (*G1FixedBaseTable, error) {
+	if c < 2 || c > 16 {
+		return nil, errors.New("the window size should be between 2 and 16")
+	}
+	t := &G1FixedBaseTable{c: c, table: make([][]G1Affine, fixedBaseNbWindows(c))}
+
+	// [2ᶜʲ]base
+	bases := make([]G1Jac, len(t.table))
+	bases[0].FromAffine(base)
+	for j := 1; j < len(bases); j++ {
+		bases[j].Set(&bases[j-1])
+		for i := uint64(0); i < c; i++ {
+			bases[j].DoubleAssign()
+		}
+	}
+
+	parallel.Execute(len(t.table), func(start, end int) {
+		multiples := make([]G1Jac, 1<<(c-1))
+		for j := start; j < end; j++ {
+			multiples[0].Set(&bases[j])
+			for d := 1; d < len(multiples); d++ {
+				multiples[d].Set(&multiples[d-1]).AddAssign(&bases[j])
+			}
+			t.table[j] = BatchJacobianToAffineG1(multiples)
+		}
+	})
+	return t, nil
+}
+
+// WindowSize returns the size c of the windows of the table
+func (t *G1FixedBaseTable) WindowSize() uint64 {
+	return t.c
+}
+
+// ScalarMultiplicationFixedBase computes and returns p = [s]base, where base
+// is the point of the table.
+func (p *G1Jac) ScalarMultiplicationFixedBase(t *G1FixedBaseTable, s *big.Int) *G1Jac {
+	var e fr.Element
+	e.SetBigInt(s)
+	t.mul(p, &e)
+	return p
+}
+
+// ScalarMultiplicationFixedBase computes and returns p = [s]base, where base
+// is the point of the table.
+func (p *G1Affine) ScalarMultiplicationFixedBase(t *G1FixedBaseTable, s *big.Int) *G1Affine {
+	var _p G1Jac
+	_p.ScalarMultiplicationFixedBase(t, s)
+	p.FromJacobian(&_p)
+	return p
+}
+
+// BatchScalarMultiplication returns the [sᵢ]base in affine coordinates, where
+// base is the point of the table.
+func (t *G1FixedBaseTable) BatchScalarMultiplication(scalars []fr.Element) []G1Affine {
+	res := make([]G1Jac, len(scalars))
+	parallel.Execute(len(scalars), func(start, end int) {
+		for i := start; i < end; i++ {
+			t.mul(&res[i], &scalars[i])
+		}
+	})
+	return BatchJacobianToAffineG1(res)
+}
+
+// mul sets p to [s]base
+func (t *G1FixedBaseTable) mul(p *G1Jac, s *fr.Element) {
+	var digits [(fr.Bits + 2) / 2]int32
+	fixedBaseDigits(digits[:len(t.table)], s, t.c)
+
+	p.Set(&g1Infinity)
+	var neg G1Affine
+	for j, d := range digits[:len(t.table)] {
+		if d > 0 {
+			p.AddMixed(&t.table[j][d-1])
+		} else if d < 0 {
+			neg.Neg(&t.table[j][-d-1])
+			p.AddMixed(&neg)
+		}
+	}
+}
+
+// WriteTo writes the window size on 8 bytes big-endian and the points of the
+// table, without point compression.
+func (t *G1FixedBaseTable) WriteTo(w io.Writer) (int64, error) {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], t.c)
+	n, err := w.Write(buf[:])
+	written := int64(n)
+	if err != nil {
+		return written, err
+	}
+	for j := range t.table {
+		for d := range t.table[j] {
+			b := t.table[j][d].RawBytes()
+			n, err = w.Write(b[:])
+			written += int64(n)
+			if err != nil {
+				return written, err
+			}
+		}
+	}
+	return written, nil
+}
+
+// ReadFrom reads a table written by WriteTo, checking that the points are in
+// the prime order subgroup.
+func (t *G1FixedBaseTable) ReadFrom(r io.Reader) (int64, error) {
+	return t.readFrom(r, true)
+}
+
+// UnsafeReadFrom reads a table written by WriteTo, without checking that the
+// points are in the prime order subgroup.
+func (t *G1FixedBaseTable) UnsafeReadFrom(r io.Reader) (int64, error) {
+	return t.readFrom(r, false)
+}
+
+func (t *G1FixedBaseTable) readFrom(r io.Reader, subGroupCheck bool) (int64, error) {
+	var buf [SizeOfG1AffineUncompressed]byte
+	n, err := io.ReadFull(r, buf[:8])
+	read := int64(n)
+	if err != nil {
+		return read, err
+	}
+	c := binary.BigEndian.Uint64(buf[:8])
+	if c < 2 || c > 16 {
+		return read, errors.New("invalid window size")
+	}
+
+	t.c = c
+	t.table = make([][]G1Affine, fixedBaseNbWindows(c))
+	for j := range t.table {
+		t.table[j] = make([]G1Affine, 1<<(c-1))
+		for d := range t.table[j] {
+			n, err = io.ReadFull(r, buf[:])
+			read += int64(n)
+			if err != nil {
+				return read, err
+			}
+			if _, err = t.table[j][d].setBytes(buf[:], subGroupCheck); err != nil {
+				return read, err
+			}
+		}
+	}
+	return read, nil
+}
@@ -0,0 +1,329 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package kzg
+
+import (
+	"crypto/sha256"
+	"errors"
+	"io"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bls24-315"
+	"github.com/consensys/gnark-crypto/ecc/bls24-315/fr"
+
+	"github.com/consensys/gnark-crypto/internal/parallel"
+)
+
+var (
+	ErrMpcSetupSize            = errors.New("the contributions don't have the same size")
+	ErrMpcSetupGenerators      = errors.New("the first powers of τ are not the generators")
+	ErrMpcSetupProof           = errors.New("invalid proof of knowledge of the contribution")
+	ErrMpcSetupUpdate          = errors.New("the contribution is not an update of the previous one")
+	ErrMpcSetupPowers          = errors.New("inconsistent powers of τ")
+	ErrMpcSetupNoContributions = errors.New("at least the initial setup is needed")
+)
+
+// mpcSetupDST is the domain separation tag used to hash the contributions to G₂
+const mpcSetupDST = "KZG_MPC_SETUP_POK_"
+
+// MpcSetup is the state of a multi-party computation of the SRS (a "powers of τ"
+// ceremony). Each participant multiplies τ by a secret x and publishes the
+// updated powers along with a proof of knowledge of x. The SRS is secure as
+// long as one of the participants erased its secret.
+//
+// implements io.ReaderFrom and io.WriterTo
+type MpcSetup struct {
+	G1 []bls24315.G1Affine  // [G₁, [τ]G₁, [τ²]G₁, ...]
+	G2 [2]bls24315.G2Affine // [G₂, [τ]G₂]
+
+	// Proof of knowledge of the secret of the last contribution, zero for the
+	// initial setup
+	Proof ContributionProof
+}
+
+// ContributionProof proves the knowledge of the secret x by which a
+// contribution multiplied τ. R is derived from the hash of the previous setup
+// and of S, so that a proof can't be replayed.
+type ContributionProof struct {
+	S  bls24315.G1Affine // [x]G₁
+	XR bls24315.G2Affine // [x]R, where R = H(previous setup ∥ S) ∈ G₂
+}
+
+// InitMpcSetup returns the initial state of a ceremony computing an SRS of the
+// given size, that is the powers of τ = 1.
+func InitMpcSetup(size uint64) (*MpcSetup, error) {
+	if size < 2 {
+		return nil, ErrMinSRSSize
+	}
+	_, _, gen1Aff, gen2Aff := bls24315.Generators()
+
+	var s MpcSetup
+	s.G1 = make([]bls24315.G1Affine, size)
+	parallel.Execute(int(size), func(start, end int) {
+		for i := start; i < end; i++ {
+			s.G1[i] = gen1Aff
+		}
+	})
+	s.G2[0] = gen2Aff
+	s.G2[1] = gen2Aff
+	return &s, nil
+}
+
+// Contribute returns the setup updated with a random secret, along with the
+// proof of knowledge of the secret. The secret is not kept.
+func (s *MpcSetup) Contribute() (*MpcSetup, error) {
+	var x fr.Element
+	for x.IsZero() {
+		if _, err := x.SetRandom(); err != nil {
+			return nil, err
+		}
+	}
+	return s.contribute(x)
+}
+
+func (s *MpcSetup) contribute(x fr.Element) (*MpcSetup, error) {
+
+	// proof of knowledge of x
+	challenge, err := s.hash()
+	if err != nil {
+		return nil, err
+	}
+	var bx big.Int
+	x.BigInt(&bx)
+
+	var res MpcSetup
+	res.Proof.S.ScalarMultiplicationBase(&bx)
+	r, err := proofBase(challenge, &res.Proof.S)
+	if err != nil {
+		return nil, err
+	}
+	res.Proof.XR.ScalarMultiplication(&r, &bx)
+
+	// [τⁱ]G₁ ← [(xτ)ⁱ]G₁
+	res.G1 = make([]bls24315.G1Affine, len(s.G1))
+	parallel.Execute(len(s.G1), func(start, end int) {
+		var xi fr.Element
+		var bxi big.Int
+		xi.Exp(x, big.NewInt(int64(start)))
+		for i := start; i < end; i++ {
+			xi.BigInt(&bxi)
+			res.G1[i].ScalarMultiplication(&s.G1[i], &bxi)
+			xi.Mul(&xi, &x)
+		}
+	})
+	res.G2[0] = s.G2[0]
+	res.G2[1].ScalarMultiplication(&s.G2[1], &bx)
+
+	return &res, nil
+}
+
+// VerifyMpcSetup verifies a chain of contributions, where setups[0] is the
+// initial setup returned by InitMpcSetup, and each next setup is a
+// contribution to the previous one.
+//
+// The points should be in the correct subgroups, which is the case for setups
+// decoded with ReadFrom.
+func VerifyMpcSetup(setups ...*MpcSetup) error {
+	if len(setups) == 0 {
+		return ErrMpcSetupNoContributions
+	}
+	initial, err := InitMpcSetup(uint64(len(setups[0].G1)))
+	if err != nil {
+		return err
+	}
+	if !setups[0].equal(initial) {
+		return errors.New("the first setup is not the initial setup")
+	}
+	for i := 1; i < len(setups); i++ {
+		if err := verifyContribution(setups[i-1], setups[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// verifyContribution checks that next is a valid contribution to prev
+func verifyContribution(prev, next *MpcSetup) error {
+	if len(next.G1) != len(prev.G1) {
+		return ErrMpcSetupSize
+	}
+	if err := checkPowers(next.G1, next.G2); err != nil {
+		return err
+	}
+	_, _, gen1Aff, _ := bls24315.Generators()
+	if next.Proof.S.IsInfinity() || !next.Proof.XR.IsInSubGroup() || !next.G2[1].IsInSubGroup() {
+		return ErrMpcSetupProof
+	}
+
+	// proof of knowledge: e(S, R) = e(G₁, [x]R)
+	challenge, err := prev.hash()
+	if err != nil {
+		return err
+	}
+	r, err := proofBase(challenge, &next.Proof.S)
+	if err != nil {
+		return err
+	}
+	if !sameRatio(next.Proof.S, gen1Aff, next.Proof.XR, r) {
+		return ErrMpcSetupProof
+	}
+
+	// τ is multiplied by x: e([τ']G₁, R) = e([τ]G₁, [x]R)
+	if !sameRatio(next.G1[1], prev.G1[1], next.Proof.XR, r) {
+		return ErrMpcSetupUpdate
+	}
+
+	return nil
+}
+
+// checkPowers returns an error if g1 and g2 are not the powers of a same τ in
+// G₁ and G₂, starting with the generators.
+func checkPowers(g1 []bls24315.G1Affine, g2 [2]bls24315.G2Affine) error {
+	if len(g1) < 2 {
+		return ErrMinSRSSize
+	}
+	_, _, gen1Aff, gen2Aff := bls24315.Generators()
+	if !g1[0].Equal(&gen1Aff) || !g2[0].Equal(&gen2Aff) {
+		return ErrMpcSetupGenerators
+	}
+
+	// e([τ]G₁, G₂) = e(G₁, [τ]G₂)
+	if !sameRatio(g1[1], g1[0], g2[1], g2[0]) {
+		return ErrMpcSetupPowers
+	}
+
+	// the G₁ points are consecutive powers of τ: with a random linear
+	// combination, e(∑ rⁱ[τⁱ⁺¹]G₁, G₂) = e(∑ rⁱ[τⁱ]G₁, [τ]G₂)
+	n := len(g1) - 1
+	coeffs := make([]fr.Element, n)
+	var rnd fr.Element
+	if _, err := rnd.SetRandom(); err != nil {
+		return err
+	}
+	coeffs[0].SetOne()
+	for i := 1; i < n; i++ {
+		coeffs[i].Mul(&coeffs[i-1], &rnd)
+	}
+	var left, right bls24315.G1Affine
+	if _, err := left.MultiExp(g1[:n], coeffs, ecc.MultiExpConfig{}); err != nil {
+		return err
+	}
+	if _, err := right.MultiExp(g1[1:], coeffs, ecc.MultiExpConfig{}); err != nil {
+		return err
+	}
+	if !sameRatio(right, left, g2[1], g2[0]) {
+		return ErrMpcSetupPowers
+	}
+
+	return nil
+}
+
+// SRS returns the SRS computed by the ceremony. The setup should have been
+// verified with VerifyMpcSetup.
+func (s *MpcSetup) SRS() *SRS {
+	g1 := make([]bls24315.G1Affine, len(s.G1))
+	copy(g1, s.G1)
+	return srsFromPowers(g1, s.G2)
+}
+
+// srsFromPowers returns the SRS made of the powers of τ g1 and g2, without copy
+func srsFromPowers(g1 []bls24315.G1Affine, g2 [2]bls24315.G2Affine) *SRS {
+	var srs SRS
+	srs.Pk.G1 = g1
+	srs.Vk.G1 = g1[0]
+	srs.Vk.G2 = g2
+	srs.Vk.Lines[0] = bls24315.PrecomputeLines(srs.Vk.G2[0])
+	srs.Vk.Lines[1] = bls24315.PrecomputeLines(srs.Vk.G2[1])
+	return &srs
+}
+
+// WriteTo writes binary encoding of the MpcSetup
+func (s *MpcSetup) WriteTo(w io.Writer) (int64, error) {
+	enc := bls24315.NewEncoder(w)
+	toEncode := []interface{}{
+		s.G1,
+		&s.G2[0],
+		&s.G2[1],
+		&s.Proof.S,
+		&s.Proof.XR,
+	}
+	for _, v := range toEncode {
+		if err := enc.Encode(v); err != nil {
+			return enc.BytesWritten(), err
+		}
+	}
+	return enc.BytesWritten(), nil
+}
+
+// ReadFrom decodes MpcSetup data from reader.
+func (s *MpcSetup) ReadFrom(r io.Reader) (int64, error) {
+	dec := bls24315.NewDecoder(r)
+	toDecode := []interface{}{
+		&s.G1,
+		&s.G2[0],
+		&s.G2[1],
+		&s.Proof.S,
+		&s.Proof.XR,
+	}
+	for _, v := range toDecode {
+		if err := dec.Decode(v); err != nil {
+			return dec.BytesRead(), err
+		}
+	}
+	return dec.BytesRead(), nil
+}
+
+// hash returns the digest of the binary encoding of the setup
+func (s *MpcSetup) hash() ([]byte, error) {
+	h := sha256.New()
+	if _, err := s.WriteTo(h); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
+
+func (s *MpcSetup) equal(other *MpcSetup) bool {
+	if len(s.G1) != len(other.G1) {
+		return false
+	}
+	for i := range s.G1 {
+		if !s.G1[i].Equal(&other.G1[i]) {
+			return false
+		}
+	}
+	return s.G2[0].Equal(&other.G2[0]) && s.G2[1].Equal(&other.G2[1]) &&
+		s.Proof.S.Equal(&other.Proof.S) && s.Proof.XR.Equal(&other.Proof.XR)
+}
+
+// proofBase returns R = H(challenge ∥ S) ∈ G₂
+func proofBase(challenge []byte, s *bls24315.G1Affine) (bls24315.G2Affine, error) {
+	sBytes := s.Bytes()
+	msg := make([]byte, 0, len(challenge)+len(sBytes))
+	msg = append(msg, challenge...)
+	msg = append(msg, sBytes[:]...)
+	return bls24315.HashToG2(msg, []byte(mpcSetupDST))
+}
+
+// sameRatio returns true if e(a₁, b₂) = e(b₁, a₂), i.e. if a₁ and a₂ are
+// the same multiples of b₁ and b₂.
+func sameRatio(a1, b1 bls24315.G1Affine, a2, b2 bls24315.G2Affine) bool {
+	var nb1 bls24315.G1Affine
+	nb1.Neg(&b1)
+	ok, err := bls24315.PairingCheck([]bls24315.G1Affine{a1, nb1}, []bls24315.G2Affine{b2, a2})
+	return err == nil && ok
+}
@@ -83,6 +83,12 @@ func computeTwiddlesInv(cardinality int) ([]*big.Int, error) {
 	// inverse the generator
 	generator.Inverse(&generator)
 
+	return computeTwiddles(generator, cardinality), nil
+}
+
+// computeTwiddles returns the powers of generator used by difFFTG1 for a FFT
+// of size cardinality
+func computeTwiddles(generator fr.Element, cardinality int) []*big.Int {
 	// nb fft stages
 	nbStages := uint64(bits.TrailingZeros64(uint64(cardinality)))
 
@@ -91,7 +97,7 @@ func computeTwiddlesInv(cardinality int) ([]*big.Int, error) {
 	w := generator
 	r[0] = new(big.Int).SetUint64(1)
 	if len(r) == 1 {
-		return r, nil
+		return r
 	}
 	r[1] = new(big.Int)
 	w.BigInt(r[1])
@@ -101,7 +107,20 @@ func computeTwiddlesInv(cardinality int) ([]*big.Int, error) {
 		w.BigInt(r[j])
 	}
 
-	return r, nil
+	return r
+}
+
+// fftG1 computes in place the FFT of a, in natural order, where generator is
+// of order len(a), a power of 2
+func fftG1(a []curve.G1Jac, generator fr.Element) {
+	if len(a) < 2 {
+		return
+	}
+	numCPU := uint64(runtime.NumCPU())
+	maxSplits := bits.TrailingZeros64(ecc.NextPowerOfTwo(numCPU)) << 1
+
+	difFFTG1(a, computeTwiddles(generator, len(a)), 0, maxSplits, nil)
+	bitReverse(a)
 }
 
 func bitReverse[T any](a []T) {
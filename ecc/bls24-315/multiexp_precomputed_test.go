@@ -0,0 +1,209 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package bls24315
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bls24-315/fr"
+)
+
+func TestMultiExpPrecomputedG1(t *testing.T) {
+	t.Parallel()
+
+	nbSamples := 200
+	nbCopiesRange := []int{1, 2, 3, 5, 8, 100}
+	if !testing.Short() {
+		// large windows, processed with the batch affine method
+		nbSamples = 1 << 12
+		nbCopiesRange = append(nbCopiesRange, 32)
+	}
+
+	samplePoints := make([]G1Affine, nbSamples)
+	var g G1Jac
+	g.Set(&g1Gen)
+	for i := range samplePoints {
+		samplePoints[i].FromJacobian(&g)
+		g.AddAssign(&g1Gen)
+	}
+	// sprinkle some points at infinity and some doublings
+	samplePoints[rand.N(nbSamples)].SetInfinity() //#nosec G404 weak rng is fine here
+	samplePoints[rand.N(nbSamples)].SetInfinity() //#nosec G404 weak rng is fine here
+	sampleScalars := make([]fr.Element, nbSamples)
+	fillBenchScalars(sampleScalars)
+	for i := 10; i < 20; i++ {
+		samplePoints[i] = samplePoints[0]
+		sampleScalars[i] = sampleScalars[0]
+	}
+	sampleScalars[1].SetZero()
+	sampleScalars[2].SetOne()
+	sampleScalars[3].SetOne().Neg(&sampleScalars[3])
+
+	for _, nbCopies := range nbCopiesRange {
+		table, err := NewG1MultiExpTable(samplePoints, nbCopies)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if table.NbPoints() != nbSamples {
+			t.Fatal("wrong number of points in the table")
+		}
+
+		// all the bases, and fewer scalars than bases
+		for _, n := range []int{nbSamples, nbSamples / 3, 1} {
+			var expected, got G1Affine
+			if _, err := expected.MultiExp(samplePoints[:n], sampleScalars[:n], ecc.MultiExpConfig{}); err != nil {
+				t.Fatal(err)
+			}
+			for _, nbTasks := range []int{0, 1, 7} {
+				if _, err := got.MultiExpPrecomputed(table, sampleScalars[:n], ecc.MultiExpConfig{NbTasks: nbTasks}); err != nil {
+					t.Fatal(err)
+				}
+				if !got.Equal(&expected) {
+					t.Fatalf("precomputed msm failed with nbCopies=%d (c=%d, stride=%d), n=%d and nbTasks=%d", nbCopies, table.c, table.stride, n, nbTasks)
+				}
+			}
+		}
+	}
+
+	table, err := NewG1MultiExpTable(samplePoints[:10], 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var p G1Affine
+	if _, err := p.MultiExpPrecomputed(table, sampleScalars[:11], ecc.MultiExpConfig{}); err == nil {
+		t.Fatal("more scalars than points should be rejected")
+	}
+	if _, err := NewG1MultiExpTable(samplePoints, 0); err == nil {
+		t.Fatal("0 copies should be rejected")
+	}
+}
+
+func BenchmarkMultiExpPrecomputedG1(b *testing.B) {
+	const nbSamples = 1 << 14
+
+	samplePoints := make([]G1Affine, nbSamples)
+	sampleScalars := make([]fr.Element, nbSamples)
+	fillBenchBasesG1(samplePoints)
+	fillBenchScalars(sampleScalars)
+
+	var testPoint G1Affine
+	b.Run("no precomputation", func(b *testing.B) {
+		for j := 0; j < b.N; j++ {
+			testPoint.MultiExp(samplePoints, sampleScalars, ecc.MultiExpConfig{})
+		}
+	})
+	for _, nbCopies := range []int{1, 4, 32} {
+		table, _ := NewG1MultiExpTable(samplePoints, nbCopies)
+		b.Run(fmt.Sprintf("%d copies", nbCopies), func(b *testing.B) {
+			for j := 0; j < b.N; j++ {
+				testPoint.MultiExpPrecomputed(table, sampleScalars, ecc.MultiExpConfig{})
+			}
+		})
+	}
+}
+
+func TestMultiExpPrecomputedG2(t *testing.T) {
+	t.Parallel()
+
+	nbSamples := 200
+	nbCopiesRange := []int{1, 2, 3, 5, 8, 100}
+
+	samplePoints := make([]G2Affine, nbSamples)
+	var g G2Jac
+	g.Set(&g2Gen)
+	for i := range samplePoints {
+		samplePoints[i].FromJacobian(&g)
+		g.AddAssign(&g2Gen)
+	}
+	// sprinkle some points at infinity and some doublings
+	samplePoints[rand.N(nbSamples)].SetInfinity() //#nosec G404 weak rng is fine here
+	samplePoints[rand.N(nbSamples)].SetInfinity() //#nosec G404 weak rng is fine here
+	sampleScalars := make([]fr.Element, nbSamples)
+	fillBenchScalars(sampleScalars)
+	for i := 10; i < 20; i++ {
+		samplePoints[i] = samplePoints[0]
+		sampleScalars[i] = sampleScalars[0]
+	}
+	sampleScalars[1].SetZero()
+	sampleScalars[2].SetOne()
+	sampleScalars[3].SetOne().Neg(&sampleScalars[3])
+
+	for _, nbCopies := range nbCopiesRange {
+		table, err := NewG2MultiExpTable(samplePoints, nbCopies)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if table.NbPoints() != nbSamples {
+			t.Fatal("wrong number of points in the table")
+		}
+
+		// all the bases, and fewer scalars than bases
+		for _, n := range []int{nbSamples, nbSamples / 3, 1} {
+			var expected, got G2Affine
+			if _, err := expected.MultiExp(samplePoints[:n], sampleScalars[:n], ecc.MultiExpConfig{}); err != nil {
+				t.Fatal(err)
+			}
+			for _, nbTasks := range []int{0, 1, 7} {
+				if _, err := got.MultiExpPrecomputed(table, sampleScalars[:n], ecc.MultiExpConfig{NbTasks: nbTasks}); err != nil {
+					t.Fatal(err)
+				}
+				if !got.Equal(&expected) {
+					t.Fatalf("precomputed msm failed with nbCopies=%d (c=%d, stride=%d), n=%d and nbTasks=%d", nbCopies, table.c, table.stride, n, nbTasks)
+				}
+			}
+		}
+	}
+
+	table, err := NewG2MultiExpTable(samplePoints[:10], 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var p G2Affine
+	if _, err := p.MultiExpPrecomputed(table, sampleScalars[:11], ecc.MultiExpConfig{}); err == nil {
+		t.Fatal("more scalars than points should be rejected")
+	}
+	if _, err := NewG2MultiExpTable(samplePoints, 0); err == nil {
+		t.Fatal("0 copies should be rejected")
+	}
+}
+
+func BenchmarkMultiExpPrecomputedG2(b *testing.B) {
+	const nbSamples = 1 << 14
+
+	samplePoints := make([]G2Affine, nbSamples)
+	sampleScalars := make([]fr.Element, nbSamples)
+	fillBenchBasesG2(samplePoints)
+	fillBenchScalars(sampleScalars)
+
+	var testPoint G2Affine
+	b.Run("no precomputation", func(b *testing.B) {
+		for j := 0; j < b.N; j++ {
+			testPoint.MultiExp(samplePoints, sampleScalars, ecc.MultiExpConfig{})
+		}
+	})
+	for _, nbCopies := range []int{1, 4, 32} {
+		table, _ := NewG2MultiExpTable(samplePoints, nbCopies)
+		b.Run(fmt.Sprintf("%d copies", nbCopies), func(b *testing.B) {
+			for j := 0; j < b.N; j++ {
+				testPoint.MultiExpPrecomputed(table, sampleScalars, ecc.MultiExpConfig{})
+			}
+		})
+	}
+}
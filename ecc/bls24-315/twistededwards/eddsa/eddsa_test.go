@@ -264,6 +264,66 @@ func TestEddsaSHA256(t *testing.T) {
 
 }
 
+func TestBatchVerify(t *testing.T) {
+
+	src := rand.NewSource(0)
+	r := rand.New(src) //#nosec G404 weak rng is fine here
+
+	hFunc := sha256.New()
+
+	const n = 20
+	publicKeys := make([]PublicKey, n)
+	sigs := make([][]byte, n)
+	msgs := make([][]byte, n)
+	for i := 0; i < n; i++ {
+		privKey, err := GenerateKey(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		publicKeys[i] = privKey.PublicKey
+		msgs[i] = []byte(fmt.Sprintf("message %d", i))
+		sigs[i], err = privKey.Sign(msgs[i], hFunc)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// valid batch
+	invalid, err := BatchVerify(publicKeys, sigs, msgs, hFunc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if invalid != nil {
+		t.Fatalf("valid batch should pass, got invalid indices %v", invalid)
+	}
+
+	// wrong message, swapped signatures and malformed signature
+	msgs[3] = []byte("wrong message")
+	sigs[7], sigs[8] = sigs[8], sigs[7]
+	sigs[15] = sigs[15][:sizeFr]
+	invalid, err = BatchVerify(publicKeys, sigs, msgs, hFunc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []int{3, 7, 8, 15}
+	if fmt.Sprint(invalid) != fmt.Sprint(expected) {
+		t.Fatalf("expected invalid indices %v, got %v", expected, invalid)
+	}
+	for _, i := range expected {
+		if res, _ := publicKeys[i].Verify(sigs[i], msgs[i], hFunc); res {
+			t.Fatalf("signature %d should be invalid", i)
+		}
+	}
+
+	// wrong sizes
+	if _, err = BatchVerify(publicKeys[1:], sigs, msgs, hFunc); err != errBatchSize {
+		t.Fatal("expected error for inconsistent sizes")
+	}
+	if _, err = BatchVerify(publicKeys, sigs, msgs, nil); err != errHashNeeded {
+		t.Fatal("expected error for nil hFunc")
+	}
+}
+
 // benchmarks
 
 func BenchmarkVerify(b *testing.B) {
@@ -289,3 +349,33 @@ func BenchmarkVerify(b *testing.B) {
 		pubKey.Verify(signature, msgBin[:], hFunc)
 	}
 }
+
+func BenchmarkBatchVerify(b *testing.B) {
+
+	src := rand.NewSource(0)
+	r := rand.New(src) //#nosec G404 weak rng is fine here
+
+	hFunc := hash.MIMC_BLS24_315.New()
+
+	const n = 256
+	publicKeys := make([]PublicKey, n)
+	sigs := make([][]byte, n)
+	msgs := make([][]byte, n)
+	for i := 0; i < n; i++ {
+		privKey, err := GenerateKey(r)
+		if err != nil {
+			b.Fatal(err)
+		}
+		publicKeys[i] = privKey.PublicKey
+		var frMsg fr.Element
+		frMsg.SetUint64(uint64(i))
+		msgBin := frMsg.Bytes()
+		msgs[i] = msgBin[:]
+		sigs[i], _ = privKey.Sign(msgs[i], hFunc)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		BatchVerify(publicKeys, sigs, msgs, hFunc)
+	}
+}
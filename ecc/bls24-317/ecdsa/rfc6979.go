@@ -0,0 +1,97 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package ecdsa
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"hash"
+	"math/big"
+)
+
+// hmacDRBG generates the deterministic nonces of RFC 6979, Section 3.2, for a
+// private key x and the hash h1 of a message. The nonces are in [1, q-1].
+type hmacDRBG struct {
+	q       *big.Int
+	newHash func() hash.Hash
+	k, v    []byte
+	started bool
+}
+
+// newHMACDRBG performs the steps a. to g. of RFC 6979, Section 3.2.
+func newHMACDRBG(newHash func() hash.Hash, q, x *big.Int, h1 []byte) *hmacDRBG {
+	d := &hmacDRBG{q: q, newHash: newHash}
+	rlen := (q.BitLen() + 7) / 8
+	hlen := newHash().Size()
+
+	xBin := x.FillBytes(make([]byte, rlen))
+	// bits2octets(h1)
+	z := d.bits2int(h1)
+	if z.Cmp(q) >= 0 {
+		z.Sub(z, q)
+	}
+	hBin := z.FillBytes(make([]byte, rlen))
+
+	d.v = bytes.Repeat([]byte{0x01}, hlen)
+	d.k = make([]byte, hlen)
+	d.k = d.mac(d.v, []byte{0x00}, xBin, hBin)
+	d.v = d.mac(d.v)
+	d.k = d.mac(d.v, []byte{0x01}, xBin, hBin)
+	d.v = d.mac(d.v)
+	return d
+}
+
+// next returns the next nonce, step h. of RFC 6979, Section 3.2. Each call
+// after the first one rejects the previous nonce.
+func (d *hmacDRBG) next() *big.Int {
+	qlen := d.q.BitLen()
+	for {
+		if d.started {
+			d.k = d.mac(d.v, []byte{0x00})
+			d.v = d.mac(d.v)
+		}
+		d.started = true
+
+		var t []byte
+		for len(t)*8 < qlen {
+			d.v = d.mac(d.v)
+			t = append(t, d.v...)
+		}
+		k := d.bits2int(t)
+		if k.Sign() > 0 && k.Cmp(d.q) < 0 {
+			return k
+		}
+	}
+}
+
+// mac returns HMAC_K(data[0] || data[1] || ...) where K is the current key.
+func (d *hmacDRBG) mac(data ...[]byte) []byte {
+	h := hmac.New(d.newHash, d.k)
+	for _, b := range data {
+		h.Write(b)
+	}
+	return h.Sum(nil)
+}
+
+// bits2int returns the integer of the qlen leftmost bits of b.
+func (d *hmacDRBG) bits2int(b []byte) *big.Int {
+	res := new(big.Int).SetBytes(b)
+	if excess := len(b)*8 - d.q.BitLen(); excess > 0 {
+		res.Rsh(res, uint(excess))
+	}
+	return res
+}
@@ -0,0 +1,105 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package ecdsa
+
+import (
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"math/big"
+	"testing"
+)
+
+func TestRFC6979Nonce(t *testing.T) {
+	// the test vectors of RFC 6979, Appendix A.1.2 and A.2.5, only depend on
+	// the order q of the group, not on the curve.
+	vectors := []struct {
+		q, x, k string
+		newHash func() hash.Hash
+		message string
+	}{
+		{
+			q:       "4000000000000000000020108A2E0CC0D99F8A5EF",
+			x:       "09A4D6792295A7F730FC3F2B49CBC0F62E862272F",
+			newHash: sha256.New,
+			message: "sample",
+			k:       "23AF4074C90A02B3FE61D286D5C87F425E6BDD81B",
+		},
+		{
+			q:       "FFFFFFFF00000000FFFFFFFFFFFFFFFFBCE6FAADA7179E84F3B9CAC2FC632551",
+			x:       "C9AFA9D845BA75166B5C215767B1D6934E50C3DB36E89B127B8A622B120F6721",
+			newHash: sha1.New,
+			message: "sample",
+			k:       "882905F1227FD620FBF2ABF21244F0BA83D0DC3A9103DBBEE43A1FB858109DB4",
+		},
+		{
+			q:       "FFFFFFFF00000000FFFFFFFFFFFFFFFFBCE6FAADA7179E84F3B9CAC2FC632551",
+			x:       "C9AFA9D845BA75166B5C215767B1D6934E50C3DB36E89B127B8A622B120F6721",
+			newHash: sha256.New,
+			message: "sample",
+			k:       "A6E3C57DD01ABE90086538398355DD4C3B17AA873382B0F24D6129493D8AAD60",
+		},
+		{
+			q:       "FFFFFFFF00000000FFFFFFFFFFFFFFFFBCE6FAADA7179E84F3B9CAC2FC632551",
+			x:       "C9AFA9D845BA75166B5C215767B1D6934E50C3DB36E89B127B8A622B120F6721",
+			newHash: sha256.New,
+			message: "test",
+			k:       "D16B6AE827F17175E040871A1C7EC3500192C4C92677336EC2537ACAEE0008E0",
+		},
+	}
+
+	for i, v := range vectors {
+		q, _ := new(big.Int).SetString(v.q, 16)
+		x, _ := new(big.Int).SetString(v.x, 16)
+		expected, _ := new(big.Int).SetString(v.k, 16)
+
+		h := v.newHash()
+		h.Write([]byte(v.message))
+		drbg := newHMACDRBG(v.newHash, q, x, h.Sum(nil))
+		if k := drbg.next(); k.Cmp(expected) != 0 {
+			t.Fatalf("vector %d: wrong nonce %s", i, k.Text(16))
+		}
+	}
+}
+
+func TestDeterministicSignature(t *testing.T) {
+	privKey, _ := GenerateKey(rand.Reader)
+	publicKey := privKey.PublicKey
+	msg := []byte("testing deterministic ECDSA")
+
+	sig1, err := privKey.SignWithOptions(msg, sha256.New(), WithDeterministicNonce(sha256.New))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig2, err := privKey.SignWithOptions(msg, sha256.New(), WithDeterministicNonce(sha256.New))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hex.EncodeToString(sig1) != hex.EncodeToString(sig2) {
+		t.Fatal("deterministic signatures of the same message should be equal")
+	}
+	if flag, _ := publicKey.Verify(sig1, msg, sha256.New()); !flag {
+		t.Fatal("deterministic signature should be valid")
+	}
+
+	sig3, _ := privKey.SignWithOptions([]byte("another message"), sha256.New(), WithDeterministicNonce(sha256.New))
+	if hex.EncodeToString(sig1[:sizeFr]) == hex.EncodeToString(sig3[:sizeFr]) {
+		t.Fatal("the nonces of different messages should differ")
+	}
+}
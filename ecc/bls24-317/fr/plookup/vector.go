@@ -401,14 +401,11 @@ func ProveLookupVector(pk kzg.ProvingKey, f, t fr.Vector) (ProofLookupVector, er
 	domainSmall.FFTInverse(cf, fft.DIF)
 	fft.BitReverse(ct)
 	fft.BitReverse(cf)
-	proof.t, err = kzg.Commit(ct, pk)
-	if err != nil {
-		return proof, err
-	}
-	proof.f, err = kzg.Commit(cf, pk)
+	digests, err := kzg.BatchCommit([][]fr.Element{ct, cf}, pk)
 	if err != nil {
 		return proof, err
 	}
+	proof.t, proof.f = digests[0], digests[1]
 
 	// write f sorted by t
 	lfSortedByt := make(fr.Vector, 2*domainSmall.Cardinality-1)
@@ -431,14 +428,11 @@ func ProveLookupVector(pk kzg.ProvingKey, f, t fr.Vector) (ProofLookupVector, er
 	fft.BitReverse(ch1)
 	fft.BitReverse(ch2)
 
-	proof.h1, err = kzg.Commit(ch1, pk)
-	if err != nil {
-		return proof, err
-	}
-	proof.h2, err = kzg.Commit(ch2, pk)
+	digests, err = kzg.BatchCommit([][]fr.Element{ch1, ch2}, pk)
 	if err != nil {
 		return proof, err
 	}
+	proof.h1, proof.h2 = digests[0], digests[1]
 
 	// derive beta, gamma
 	beta, err := deriveRandomness(fs, "beta", &proof.t, &proof.f, &proof.h1, &proof.h2)
@@ -0,0 +1,212 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package kzg
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bls24-317"
+	"github.com/consensys/gnark-crypto/ecc/bls24-317/fr"
+)
+
+var ErrVerifyHidingOpeningProof = errors.New("can't verify hiding opening proof")
+
+// hidingGeneratorDST is the domain separation tag used to hash to the blinding
+// generator, so that its discrete logarithm is unknown
+const hidingGeneratorDST = "KZG_HIDING_BLINDING_GENERATOR_"
+
+// HidingProvingKey used to create or open hiding commitments
+type HidingProvingKey struct {
+	G1 []bls24317.G1Affine // [G₁ [α]G₁ , [α²]G₁, ... ]
+	H  []bls24317.G1Affine // [H [α]H , [α²]H, ... ]
+}
+
+// HidingVerifyingKey used to verify hiding opening proofs
+type HidingVerifyingKey struct {
+	VerifyingKey
+	H bls24317.G1Affine // blinding generator
+}
+
+// HidingSRS is the SRS of the hiding variant of KZG (PolyCommit_Ped in Kate,
+// Zaverucha and Goldberg, "Constant-size commitments to polynomials and their
+// applications"): the commitments are Pedersen commitments, with a random
+// blinding polynomial committed with the powers of α in H.
+type HidingSRS struct {
+	Pk HidingProvingKey
+	Vk HidingVerifyingKey
+}
+
+// HidingOpeningProof hiding KZG proof for opening at a single point.
+type HidingOpeningProof struct {
+	// H commitment to the quotients (f - f(z))/(x-z) and (r - r(z))/(x-z),
+	// where r is the blinding polynomial
+	H bls24317.G1Affine
+
+	// ClaimedValue purported value f(z)
+	ClaimedValue fr.Element
+
+	// BlindingValue r(z)
+	BlindingValue fr.Element
+}
+
+// HidingGenerator returns the blinding generator H, hashed to G₁ so that
+// nobody knows its discrete logarithm in base G₁.
+func HidingGenerator() bls24317.G1Affine {
+	h, err := bls24317.HashToG1([]byte("H"), []byte(hidingGeneratorDST))
+	if err != nil {
+		panic(err) // the hash to curve doesn't fail
+	}
+	return h
+}
+
+// NewHidingSRS returns a new hiding SRS using alpha as randomness source
+//
+// In production, a SRS generated through MPC should be used.
+func NewHidingSRS(size uint64, bAlpha *big.Int) (*HidingSRS, error) {
+	srs, err := NewSRS(size, bAlpha)
+	if err != nil {
+		return nil, err
+	}
+
+	var res HidingSRS
+	res.Pk.G1 = srs.Pk.G1
+	res.Vk.VerifyingKey = srs.Vk
+	res.Vk.H = HidingGenerator()
+
+	var alpha fr.Element
+	alpha.SetBigInt(bAlpha)
+	alphas := make([]fr.Element, size-1)
+	alphas[0] = alpha
+	for i := 1; i < len(alphas); i++ {
+		alphas[i].Mul(&alphas[i-1], &alpha)
+	}
+	res.Pk.H = make([]bls24317.G1Affine, size)
+	res.Pk.H[0] = res.Vk.H
+	copy(res.Pk.H[1:], bls24317.BatchScalarMultiplicationG1(&res.Vk.H, alphas))
+
+	return &res, nil
+}
+
+// CommitHiding commits to a polynomial p with a random blinding polynomial r of
+// the same size, and returns the commitment [p(α)]G₁ + [r(α)]H along with r,
+// which is needed to open the commitment.
+func CommitHiding(p []fr.Element, pk HidingProvingKey, nbTasks ...int) (Digest, []fr.Element, error) {
+	if len(p) == 0 || len(p) > len(pk.G1) || len(p) > len(pk.H) {
+		return Digest{}, nil, ErrInvalidPolynomialSize
+	}
+
+	blinding := make([]fr.Element, len(p))
+	for i := range blinding {
+		if _, err := blinding[i].SetRandom(); err != nil {
+			return Digest{}, nil, err
+		}
+	}
+
+	res, err := commitHiding(p, blinding, pk, nbTasks...)
+	if err != nil {
+		return Digest{}, nil, err
+	}
+	return res, blinding, nil
+}
+
+// commitHiding returns [p(α)]G₁ + [r(α)]H
+func commitHiding(p, blinding []fr.Element, pk HidingProvingKey, nbTasks ...int) (Digest, error) {
+	config := ecc.MultiExpConfig{}
+	if len(nbTasks) > 0 {
+		config.NbTasks = nbTasks[0]
+	}
+	var c, cBlinding bls24317.G1Affine
+	if _, err := c.MultiExp(pk.G1[:len(p)], p, config); err != nil {
+		return Digest{}, err
+	}
+	if _, err := cBlinding.MultiExp(pk.H[:len(blinding)], blinding, config); err != nil {
+		return Digest{}, err
+	}
+	c.Add(&c, &cBlinding)
+	return c, nil
+}
+
+// OpenHiding computes an opening proof at point of a polynomial p committed
+// with the blinding polynomial returned by CommitHiding.
+func OpenHiding(p, blinding []fr.Element, point fr.Element, pk HidingProvingKey) (HidingOpeningProof, error) {
+	if len(p) == 0 || len(p) > len(pk.G1) || len(blinding) != len(p) {
+		return HidingOpeningProof{}, ErrInvalidPolynomialSize
+	}
+	if len(p) == 1 {
+		// the quotients are zero
+		return HidingOpeningProof{ClaimedValue: p[0], BlindingValue: blinding[0]}, nil
+	}
+
+	res := HidingOpeningProof{
+		ClaimedValue:  eval(p, point),
+		BlindingValue: eval(blinding, point),
+	}
+
+	// quotients (p - p(z))/(X - z) and (r - r(z))/(X - z)
+	_p := make([]fr.Element, len(p))
+	copy(_p, p)
+	h := dividePolyByXminusA(_p, res.ClaimedValue, point)
+	_r := make([]fr.Element, len(blinding))
+	copy(_r, blinding)
+	hBlinding := dividePolyByXminusA(_r, res.BlindingValue, point)
+
+	var err error
+	if res.H, err = commitHiding(h, hBlinding, pk); err != nil {
+		return HidingOpeningProof{}, err
+	}
+	return res, nil
+}
+
+// VerifyHiding verifies a hiding KZG opening proof at a single point, that is
+//
+// e(C - [f(z)]G₁ - [r(z)]H + [z]W, G₂) = e(W, [α]G₂)
+func VerifyHiding(commitment *Digest, proof *HidingOpeningProof, point fr.Element, vk HidingVerifyingKey) error {
+
+	// [f(z)]G₁ + [r(z)]H - [z]W
+	var claimed, total bls24317.G1Jac
+	var bClaimed, bBlinding, bPoint big.Int
+	proof.ClaimedValue.BigInt(&bClaimed)
+	proof.BlindingValue.BigInt(&bBlinding)
+	claimed.JointScalarMultiplication(&vk.G1, &vk.H, &bClaimed, &bBlinding)
+	var pointNeg fr.Element
+	pointNeg.Neg(&point).BigInt(&bPoint)
+	total.FromAffine(&proof.H)
+	total.ScalarMultiplication(&total, &bPoint)
+	total.AddAssign(&claimed)
+
+	// [f(z)]G₁ + [r(z)]H - [z]W - C
+	var commitmentJac bls24317.G1Jac
+	commitmentJac.FromAffine(commitment)
+	total.SubAssign(&commitmentJac)
+
+	// e([f(z)]G₁ + [r(z)]H - [z]W - C, G₂).e(W, [α]G₂) == 1
+	var totalAff bls24317.G1Affine
+	totalAff.FromJacobian(&total)
+	check, err := bls24317.PairingCheckFixedQ(
+		[]bls24317.G1Affine{totalAff, proof.H},
+		vk.Lines[:],
+	)
+	if err != nil {
+		return err
+	}
+	if !check {
+		return ErrVerifyHidingOpeningProof
+	}
+	return nil
+}
@@ -0,0 +1,207 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package pst
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/consensys/gnark-crypto/ecc/bls24-317"
+	"github.com/consensys/gnark-crypto/ecc/bls24-317/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls24-317/fr/polynomial"
+	"github.com/consensys/gnark-crypto/utils/testutils"
+)
+
+// Test SRS re-used across tests of the PST scheme
+var testSrs *SRS
+var testTau []fr.Element
+
+const testNbVars = 6
+
+func init() {
+	testTau = make([]fr.Element, testNbVars)
+	bTau := make([]*big.Int, testNbVars)
+	for i := range testTau {
+		testTau[i].SetRandom()
+		bTau[i] = new(big.Int)
+		testTau[i].BigInt(bTau[i])
+	}
+	var err error
+	testSrs, err = NewSRS(bTau)
+	if err != nil {
+		panic(err)
+	}
+}
+
+func randomMultiLin(nbVars int) polynomial.MultiLin {
+	p := make(polynomial.MultiLin, 1<<nbVars)
+	for i := range p {
+		p[i].SetRandom()
+	}
+	return p
+}
+
+func randomPoint(nbVars int) []fr.Element {
+	point := make([]fr.Element, nbVars)
+	for i := range point {
+		point[i].SetRandom()
+	}
+	return point
+}
+
+func TestSerialization(t *testing.T) {
+
+	_, _, g, _ := bls24317.Generators()
+	var proof OpeningProof
+	proof.Quotients = []bls24317.G1Affine{g, g, g}
+	proof.ClaimedValue.SetRandom()
+	var batchProof BatchOpeningProof
+	batchProof.Quotients = []bls24317.G1Affine{g, g}
+	batchProof.ClaimedValues = randomPoint(4)
+
+	t.Run("opening proof round trip", testutils.SerializationRoundTrip(&proof))
+	t.Run("batch opening proof round trip", testutils.SerializationRoundTrip(&batchProof))
+	t.Run("srs round trip", testutils.SerializationRoundTrip(testSrs))
+}
+
+func TestReadFromNbLevels(t *testing.T) {
+	assert := require.New(t)
+
+	// a crafted number of levels is rejected before any allocation
+	var buf bytes.Buffer
+	enc := bls24317.NewEncoder(&buf)
+	assert.NoError(enc.Encode(uint64(1) << 62))
+
+	var pk ProvingKey
+	_, err := pk.ReadFrom(bytes.NewReader(buf.Bytes()))
+	assert.ErrorIs(err, ErrMaxSRSSize)
+
+	_, err = NewSRS(make([]*big.Int, maxNbVariables+1))
+	assert.ErrorIs(err, ErrMaxSRSSize)
+}
+
+func TestCommit(t *testing.T) {
+	assert := require.New(t)
+
+	// the commitment is [f(τ)]G₁, where the last variables of τ are used for
+	// polynomials in less variables than the SRS
+	for _, nbVars := range []int{testNbVars, 3, 0} {
+		p := randomMultiLin(nbVars)
+		digest, err := Commit(p, testSrs.Pk)
+		assert.NoError(err)
+
+		var expected bls24317.G1Affine
+		var bEval big.Int
+		eval := p.Evaluate(testTau[testNbVars-nbVars:], nil)
+		expected.ScalarMultiplication(&testSrs.Vk.G1, eval.BigInt(&bEval))
+		assert.True(expected.Equal(&digest), "wrong commitment in %d variables", nbVars)
+	}
+
+	_, err := Commit(randomMultiLin(testNbVars+1), testSrs.Pk)
+	assert.ErrorIs(err, ErrInvalidPolynomialSize)
+	_, err = Commit(make(polynomial.MultiLin, 3), testSrs.Pk)
+	assert.ErrorIs(err, ErrInvalidPolynomialSize)
+}
+
+func TestOpen(t *testing.T) {
+	assert := require.New(t)
+
+	for _, nbVars := range []int{testNbVars, 3, 1, 0} {
+		p := randomMultiLin(nbVars)
+		digest, err := Commit(p, testSrs.Pk)
+		assert.NoError(err)
+
+		point := randomPoint(nbVars)
+		proof, err := Open(p, point, testSrs.Pk)
+		assert.NoError(err)
+		expected := p.Evaluate(point, nil)
+		assert.True(expected.Equal(&proof.ClaimedValue), "wrong claimed value")
+		assert.NoError(Verify(&digest, &proof, point, testSrs.Vk))
+
+		// wrong claimed value
+		wrongProof := proof
+		wrongProof.ClaimedValue.Double(&proof.ClaimedValue)
+		assert.ErrorIs(Verify(&digest, &wrongProof, point, testSrs.Vk), ErrVerifyOpeningProof)
+
+		if nbVars > 0 {
+			// wrong point
+			assert.ErrorIs(Verify(&digest, &proof, randomPoint(nbVars), testSrs.Vk), ErrVerifyOpeningProof)
+
+			// wrong quotient
+			wrongProof.ClaimedValue = proof.ClaimedValue
+			wrongProof.Quotients = make([]bls24317.G1Affine, nbVars)
+			copy(wrongProof.Quotients, proof.Quotients)
+			wrongProof.Quotients[0].Double(&wrongProof.Quotients[0])
+			assert.ErrorIs(Verify(&digest, &wrongProof, point, testSrs.Vk), ErrVerifyOpeningProof)
+		}
+	}
+
+	_, err := Open(randomMultiLin(3), randomPoint(2), testSrs.Pk)
+	assert.ErrorIs(err, ErrInvalidPointSize)
+}
+
+func TestBatchOpen(t *testing.T) {
+	assert := require.New(t)
+
+	const nbPolys = 5
+	const nbVars = 4
+	polynomials := make([]polynomial.MultiLin, nbPolys)
+	digests := make([]Digest, nbPolys)
+	for i := range polynomials {
+		polynomials[i] = randomMultiLin(nbVars)
+		var err error
+		digests[i], err = Commit(polynomials[i], testSrs.Pk)
+		assert.NoError(err)
+	}
+	point := randomPoint(nbVars)
+
+	proof, err := BatchOpen(polynomials, digests, point, sha256.New(), testSrs.Pk)
+	assert.NoError(err)
+	for i := range polynomials {
+		expected := polynomials[i].Evaluate(point, nil)
+		assert.True(expected.Equal(&proof.ClaimedValues[i]), "wrong claimed value")
+	}
+	assert.NoError(BatchVerify(digests, &proof, point, sha256.New(), testSrs.Vk))
+
+	// wrong claimed value
+	proof.ClaimedValues[1].Double(&proof.ClaimedValues[1])
+	assert.ErrorIs(BatchVerify(digests, &proof, point, sha256.New(), testSrs.Vk), ErrVerifyOpeningProof)
+	proof.ClaimedValues[1] = polynomials[1].Evaluate(point, nil)
+
+	// wrong digest
+	digests[0], digests[1] = digests[1], digests[0]
+	assert.ErrorIs(BatchVerify(digests, &proof, point, sha256.New(), testSrs.Vk), ErrVerifyOpeningProof)
+
+	_, err = BatchOpen(polynomials, digests[1:], point, sha256.New(), testSrs.Pk)
+	assert.ErrorIs(err, ErrInvalidNbDigests)
+	_, err = BatchOpen(nil, nil, point, sha256.New(), testSrs.Pk)
+	assert.ErrorIs(err, ErrZeroNbDigests)
+}
+
+func BenchmarkOpen(b *testing.B) {
+	p := randomMultiLin(testNbVars)
+	point := randomPoint(testNbVars)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = Open(p, point, testSrs.Pk)
+	}
+}
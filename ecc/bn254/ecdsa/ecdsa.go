@@ -216,6 +216,49 @@ func nonce(privateKey *PrivateKey, hash []byte) (csprng *cipher.StreamReader, er
 	return csprng, err
 }
 
+// newNonce returns a nonce k ∈ [1, order-1], the next one of drbg if it is set
+// and a random one otherwise.
+func (privKey *PrivateKey) newNonce(drbg *hmacDRBG, message []byte) (*big.Int, error) {
+	if drbg != nil {
+		return drbg.next(), nil
+	}
+	csprng, err := nonce(privKey, message)
+	if err != nil {
+		return nil, err
+	}
+	return randFieldElement(csprng)
+}
+
+// SignOption defines option for altering the behavior of the signature.
+// See the descriptions of functions returning instances of this type for
+// particular options.
+type SignOption func(*signConfig)
+
+type signConfig struct {
+	newHash func() hash.Hash
+}
+
+// default options
+func signOptions(opts ...SignOption) signConfig {
+	// apply options
+	var opt signConfig
+	for _, option := range opts {
+		option(&opt)
+	}
+	return opt
+}
+
+// WithDeterministicNonce derives the nonce k from the private key and the hash
+// of the message as in RFC 6979, with HMAC instantiated with newHash
+// (typically the constructor of the hash function of the message). Signing
+// the same message twice then gives the same signature. By default, k is drawn
+// from a CSPRNG seeded with the private key, the message and fresh entropy.
+func WithDeterministicNonce(newHash func() hash.Hash) SignOption {
+	return func(opt *signConfig) {
+		opt.newHash = newHash
+	}
+}
+
 // Equal compares 2 public keys
 func (pub *PublicKey) Equal(x signature.PublicKey) bool {
 	xx, ok := x.(*PublicKey)
@@ -242,19 +285,45 @@ func (privKey *PrivateKey) Public() signature.PublicKey {
 // s = k⁻¹ . (m + sk ⋅ r)
 // v = (div(x_P, order)<<1) || y_P[-1]
 //
+// With the option WithDeterministicNonce, k is derived as in RFC 6979 instead.
+//
 // SEC 1, Version 2.0, Section 4.1.3
-func (privKey *PrivateKey) SignForRecover(message []byte, hFunc hash.Hash) (v uint, r, s *big.Int, err error) {
-	r, s = new(big.Int), new(big.Int)
+func (privKey *PrivateKey) SignForRecover(message []byte, hFunc hash.Hash, opts ...SignOption) (v uint, r, s *big.Int, err error) {
+	// compute the hash of the message
+	hramBin := message
+	if hFunc != nil {
+		dataToHash := make([]byte, len(message))
+		copy(dataToHash[:], message[:])
+		hFunc.Reset()
+		_, err := hFunc.Write(dataToHash[:])
+		if err != nil {
+			return 0, nil, nil, err
+		}
+		hramBin = hFunc.Sum(nil)
+	}
+	m := HashToInt(hramBin)
 
-	scalar, kInv := new(big.Int), new(big.Int)
+	scalar := new(big.Int)
 	scalar.SetBytes(privKey.scalar[:sizeFr])
+
+	var drbg *hmacDRBG
+	if cfg := signOptions(opts...); cfg.newHash != nil {
+		drbg = newHMACDRBG(cfg.newHash, order, scalar, hramBin)
+	}
+	return signForRecover(m, scalar, func() (*big.Int, error) {
+		return privKey.newNonce(drbg, message)
+	})
+}
+
+// signForRecover signs the hashed message m with the secret scalar, drawing
+// the nonces from nextNonce until both r and s are non-zero. The recovery id
+// v is the one of the last nonce.
+func signForRecover(m, scalar *big.Int, nextNonce func() (*big.Int, error)) (v uint, r, s *big.Int, err error) {
+	r, s = new(big.Int), new(big.Int)
+	kInv := new(big.Int)
 	for {
 		for {
-			csprng, err := nonce(privKey, message)
-			if err != nil {
-				return 0, nil, nil, err
-			}
-			k, err := randFieldElement(csprng)
+			k, err := nextNonce()
 			if err != nil {
 				return 0, nil, nil, err
 			}
@@ -265,7 +334,7 @@ func (privKey *PrivateKey) SignForRecover(message []byte, hFunc hash.Hash) (v ui
 
 			P.X.BigInt(r)
 			// set how many times we overflow the scalar field
-			v |= (uint(new(big.Int).Div(r, order).Uint64())) << 1
+			v = (uint(new(big.Int).Div(r, order).Uint64())) << 1
 			// set if y is even or odd
 			v |= P.Y.BigInt(new(big.Int)).Bit(0)
 
@@ -276,22 +345,6 @@ func (privKey *PrivateKey) SignForRecover(message []byte, hFunc hash.Hash) (v ui
 		}
 		s.Mul(r, scalar)
 
-		var m *big.Int
-		if hFunc != nil {
-			// compute the hash of the message as an integer
-			dataToHash := make([]byte, len(message))
-			copy(dataToHash[:], message[:])
-			hFunc.Reset()
-			_, err := hFunc.Write(dataToHash[:])
-			if err != nil {
-				return 0, nil, nil, err
-			}
-			hramBin := hFunc.Sum(nil)
-			m = HashToInt(hramBin)
-		} else {
-			m = HashToInt(message)
-		}
-
 		s.Add(m, s).
 			Mul(kInv, s).
 			Mod(s, order) // order != 0
@@ -313,7 +366,13 @@ func (privKey *PrivateKey) SignForRecover(message []byte, hFunc hash.Hash) (v ui
 //
 // SEC 1, Version 2.0, Section 4.1.3
 func (privKey *PrivateKey) Sign(message []byte, hFunc hash.Hash) ([]byte, error) {
-	_, r, s, err := privKey.SignForRecover(message, hFunc)
+	return privKey.SignWithOptions(message, hFunc)
+}
+
+// SignWithOptions performs the ECDSA signature as Sign, with the options opts.
+// With WithDeterministicNonce, k is derived as in RFC 6979 instead of random.
+func (privKey *PrivateKey) SignWithOptions(message []byte, hFunc hash.Hash, opts ...SignOption) ([]byte, error) {
+	_, r, s, err := privKey.SignForRecover(message, hFunc, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -18,6 +18,7 @@ package fri
 
 import (
 	"bytes"
+	"encoding/binary"
 	"errors"
 	"fmt"
 	"hash"
@@ -32,29 +33,24 @@ import (
 )
 
 var (
-	ErrLowDegree            = errors.New("the fully folded polynomial in not of degree 1")
+	ErrLowDegree            = errors.New("the fully folded polynomial is not of the expected degree")
 	ErrProximityTestFolding = errors.New("one round of interaction failed")
 	ErrOddSize              = errors.New("the size should be even")
 	ErrMerkleRoot           = errors.New("merkle roots of the opening and the proof of proximity don't coincide")
 	ErrMerklePath           = errors.New("merkle path proof is wrong")
 	ErrRangePosition        = errors.New("the asked opening position is out of range")
+	ErrPolynomialSize       = errors.New("the polynomial is too large for this iopp")
+	ErrProofOfWork          = errors.New("the proof of work is invalid")
+	ErrProofShape           = errors.New("the proof of proximity does not match the parameters of the iopp")
 )
 
-const rho = 8
-
-const nbRounds = 1
-
-// 2^{-1}, used several times
-var twoInv fr.Element
-
 // Digest commitment of a polynomial.
 type Digest []byte
 
-// merkleProof helper structure to build the merkle proof
-// At each round, two contiguous values from the evaluated polynomial
-// are queried. For one value, the full Merkle path will be provided.
-// For the neighbor value, only the leaf is provided (so ProofSet will
-// be empty), since the Merkle path is the same as for the first value.
+// MerkleProof helper structure to build the merkle proof.
+// At each step of the folding, the leaves of the committed tree are the cosets
+// of size arity which are folded into a single value. The first element of the
+// ProofSet is then the concatenation of the arity values of the coset.
 type MerkleProof struct {
 
 	// Merkle root
@@ -89,32 +85,19 @@ type OpeningProof struct {
 type IOPP uint
 
 const (
-	// Multiplicative version of FRI, using the map x->x², on a
+	// Multiplicative version of FRI, using the map x->x^arity, on a
 	// power of 2 subgroup of Fr^{*}.
 	RADIX_2_FRI IOPP = iota
 )
 
-// round contains the data corresponding to a single round
-// of fri.
-// It consists of a list of Interactions between the prover and the verifier,
-// where each interaction contains a challenge provided by the verifier, as
-// well as MerkleProofs for the queries of the verifier. The Merkle proofs
-// correspond to the openings of the i-th folded polynomial at 2 points that
-// belong to the same fiber of x -> x².
+// Round contains the data corresponding to a single query of the verifier.
+// It consists of a list of Interactions, one per folding step, each of them
+// being the Merkle proof of the coset of the folded polynomial that contains
+// the query.
 type Round struct {
 
-	// stores the Interactions between the prover and the verifier.
-	// Each interaction results in a set or merkle proofs, corresponding
-	// to the queries of the verifier.
-	Interactions [][2]MerkleProof
-
-	// evaluation stores the evaluation of the fully folded polynomial.
-	// The fully folded polynomial is constant, and is evaluated on a
-	// a set of size \rho. Since the polynomial is supposed to be constant,
-	// only one evaluation, corresponding to the polynomial, is given. Since
-	// the prover cannot know in advance which entry the verifier will query,
-	// providing a single evaluation
-	Evaluation fr.Element
+	// Interactions[i] opens the coset queried at the i-th folding step.
+	Interactions []MerkleProof
 }
 
 // ProofOfProximity proof of proximity, attesting that
@@ -128,9 +111,16 @@ type ProofOfProximity struct {
 	// from the proof of proximity.
 	ID []byte
 
-	// round contains the data corresponding to a single round
-	// of fri. There are nbRounds rounds of Interactions.
+	// Rounds contains the data corresponding to each query of the verifier.
+	// All the queries share the same Merkle roots.
 	Rounds []Round
+
+	// FinalPolynomial is the polynomial obtained after the last folding step,
+	// in canonical basis.
+	FinalPolynomial []fr.Element
+
+	// Nonce is the solution of the proof of work, see WithProofOfWork.
+	Nonce uint64
 }
 
 // Iopp interface that an iopp should implement
@@ -149,111 +139,243 @@ type Iopp interface {
 
 	// Verifies the opening of a polynomial at gⁱ where i = position.
 	VerifyOpening(position uint64, openingProof OpeningProof, pp ProofOfProximity) error
-}
 
-// GetRho returns the factor ρ = size_code_word/size_polynomial
-func GetRho() int {
-	return rho
+	// BuildProofOfProximityBatch creates a proof of proximity that each polynomials[i] is
+	// d-close to a polynomial of degree len(polynomials[i]).
+	BuildProofOfProximityBatch(polynomials [][]fr.Element) (ProofOfProximity, error)
+
+	// VerifyProofOfProximityBatch verifies a batch proof of proximity, sizes[i] being
+	// the size of the i-th polynomial.
+	VerifyProofOfProximityBatch(sizes []uint64, proof ProofOfProximity) error
+
+	// OpenBatch opens each of the polynomials at gⁱ where i = position.
+	OpenBatch(polynomials [][]fr.Element, position uint64) ([]OpeningProof, error)
+
+	// VerifyOpeningBatch verifies the openings of the polynomials at gⁱ where i = position.
+	VerifyOpeningBatch(position uint64, openingProofs []OpeningProof, pp ProofOfProximity) error
 }
 
-func init() {
-	twoInv.SetUint64(2).Inverse(&twoInv)
+// GetRho returns the default factor ρ = size_code_word/size_polynomial, see WithBlowup.
+func GetRho() int {
+	return DefaultBlowup
 }
 
 // New creates a new IOPP capable to handle degree(size) polynomials.
-func (iopp IOPP) New(size uint64, h hash.Hash) Iopp {
+// It panics if the options are not supported, see Option.
+func (iopp IOPP) New(size uint64, h hash.Hash, opts ...Option) Iopp {
 	switch iopp {
 	case RADIX_2_FRI:
-		return newRadixTwoFri(size, h)
+		return newRadixTwoFri(size, h, opts...)
 	default:
 		panic("iopp name is not recognized")
 	}
 }
 
-// radixTwoFri empty structs implementing compressionFunction for
-// the squaring function.
+// radixTwoFri implements the multiplicative FRI on a power of 2 subgroup,
+// with a folding arity which is a power of 2.
 type radixTwoFri struct {
 
 	// hash function that is used for Fiat Shamir and for committing to
 	// the oracles.
 	h hash.Hash
 
-	// nbSteps number of Interactions between the prover and the verifier
-	nbSteps int
+	// parameters of the protocol
+	config friConfig
+
+	// degreeBound the committed polynomial is of degree < degreeBound
+	degreeBound uint64
+
+	// finalDegreeBound the folded polynomial sent in clear is of degree < finalDegreeBound
+	finalDegreeBound uint64
+
+	// steps of folding, the i-th step folds the codeword of size steps[i].size
+	steps []foldingStep
+
+	// finalGenerator generates the domain on which the last folded codeword lives
+	finalGenerator fr.Element
 
 	// domain used to build the Reed Solomon code from the given polynomial.
 	// The size of the domain is ρ*size_polynomial.
 	domain *fft.Domain
 }
 
-func newRadixTwoFri(size uint64, h hash.Hash) radixTwoFri {
+// foldingStep contains the precomputed data of a folding step
+type foldingStep struct {
 
-	var res radixTwoFri
+	// arity number of entries folded in one
+	arity int
 
-	// computing the number of steps
-	n := ecc.NextPowerOfTwo(size)
-	nbSteps := bits.TrailingZeros(uint(n))
-	res.nbSteps = nbSteps
+	// size of the codeword before folding
+	size uint64
 
-	// extending the domain
-	n = n * rho
+	// gInv inverse of the generator of the domain of the codeword
+	gInv fr.Element
 
-	// building the domains
-	res.domain = fft.NewDomain(n)
+	// omegaInv[i] = ω⁻ⁱ where ω is a primitive arity-th root of unity
+	omegaInv []fr.Element
+
+	// arityInv = 1/arity
+	arityInv fr.Element
+}
 
-	// hash function
+func newRadixTwoFri(size uint64, h hash.Hash, opts ...Option) radixTwoFri {
+
+	config := friOptions(opts...)
+	if err := config.check(); err != nil {
+		panic(err)
+	}
+
+	var res radixTwoFri
 	res.h = h
+	res.config = config
+
+	// degree bound of the polynomial, at least 2 so that there is at least one step
+	res.degreeBound = ecc.NextPowerOfTwo(size)
+	if res.degreeBound < 2 {
+		res.degreeBound = 2
+	}
+	res.finalDegreeBound = ecc.NextPowerOfTwo(uint64(config.finalDegree) + 1)
+	if res.finalDegreeBound > res.degreeBound/2 {
+		res.finalDegreeBound = res.degreeBound / 2
+	}
+
+	// building the domains
+	res.domain = fft.NewDomain(res.degreeBound * uint64(config.blowup))
+
+	// computing the steps: the degree bound is divided by the arity at each step, the
+	// last step uses a smaller arity if needed.
+	var gInv fr.Element
+	gInv.Set(&res.domain.GeneratorInv)
+	n := res.domain.Cardinality
+	for d := res.degreeBound; d > res.finalDegreeBound; {
+		arity := uint64(config.arity)
+		if d/arity < res.finalDegreeBound {
+			arity = d / res.finalDegreeBound
+		}
+
+		step := foldingStep{
+			arity:    int(arity),
+			size:     n,
+			gInv:     gInv,
+			omegaInv: make([]fr.Element, arity),
+		}
+		var omegaInv fr.Element
+		omegaInv.Exp(gInv, new(big.Int).SetUint64(n/arity))
+		step.omegaInv[0].SetOne()
+		for i := 1; i < len(step.omegaInv); i++ {
+			step.omegaInv[i].Mul(&step.omegaInv[i-1], &omegaInv)
+		}
+		step.arityInv.SetUint64(arity).Inverse(&step.arityInv)
+		res.steps = append(res.steps, step)
+
+		gInv.Exp(gInv, new(big.Int).SetUint64(arity))
+		n /= arity
+		d /= arity
+	}
+	res.finalGenerator.Inverse(&gInv)
 
 	return res
 }
 
-// convertCanonicalSorted convert the index i, an entry in a
-// sorted polynomial, to the corresponding entry in canonical
-// representation. n is the size of the polynomial.
-func convertCanonicalSorted(i, n int) int {
-
-	if i < n/2 {
-		return 2 * i
-	} else {
-		l := n - (i + 1)
-		l = 2 * l
-		return n - l - 1
+// leaves returns the leaves of the Merkle tree committing to the codeword of
+// the i-th step. The leaf j contains the coset {p[j + k*size/arity]}, k < arity.
+func (s radixTwoFri) leaves(i int, p []fr.Element) [][]byte {
+	step := &s.steps[i]
+	m := int(step.size) / step.arity
+	res := make([][]byte, m)
+	for j := 0; j < m; j++ {
+		res[j] = make([]byte, 0, step.arity*fr.Bytes)
+		for k := 0; k < step.arity; k++ {
+			b := p[j+k*m].Bytes()
+			res[j] = append(res[j], b[:]...)
+		}
 	}
+	return res
+}
 
+// readLeaf reads the coset stored in a leaf of the tree of the i-th step.
+func (s radixTwoFri) readLeaf(i int, leaf []byte) ([]fr.Element, error) {
+	step := &s.steps[i]
+	if len(leaf) != step.arity*fr.Bytes {
+		return nil, ErrProofShape
+	}
+	res := make([]fr.Element, step.arity)
+	for k := range res {
+		if err := res[k].SetBytesCanonical(leaf[k*fr.Bytes : (k+1)*fr.Bytes]); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
 }
 
-// deriveQueriesPositions derives the indices of the oracle
-// function that the verifier has to pick, in sorted form.
-// * pos is the initial position, i.e. the logarithm of the first challenge
-// * size is the size of the initial polynomial
-// * The result is a slice of []int, where each entry is a tuple (iₖ), such that
-// the verifier needs to evaluate ∑ₖ oracle(iₖ)xᵏ to build
-// the folded function.
-func (s radixTwoFri) deriveQueriesPositions(pos int, size int) []int {
+// foldCoset folds the values of p on the coset {x, xω, .., xω^{arity-1}} where
+// ω is a primitive arity-th root of unity.
+//
+// Writing p = ∑ᵢ XⁱPᵢ(X^arity), the Pᵢ(x^arity) are obtained by an inverse DFT
+// of size arity on the values, and the function returns ∑ᵢ βⁱPᵢ(x^arity).
+// * values are the values of p on the coset
+// * xInv is x⁻¹
+// * beta is the folding challenge
+func (step *foldingStep) foldCoset(values []fr.Element, xInv, beta fr.Element) fr.Element {
+
+	// ∑ᵢ βⁱPᵢ(x^arity) = 1/arity * ∑ᵢ (β/x)ⁱ ∑ⱼ ω^{-ij} p(xωʲ)
+	var z, res, u, t fr.Element
+	z.Mul(&beta, &xInv)
+	for i := step.arity - 1; i >= 0; i-- {
+		u.SetZero()
+		for j := 0; j < step.arity; j++ {
+			t.Mul(&values[j], &step.omegaInv[(i*j)%step.arity])
+			u.Add(&u, &t)
+		}
+		res.Mul(&res, &z).Add(&res, &u)
+	}
+	res.Mul(&res, &step.arityInv)
+
+	return res
+}
 
-	_s := size / 2
-	res := make([]int, s.nbSteps)
-	res[0] = pos
-	for i := 1; i < s.nbSteps; i++ {
-		t := (res[i-1] - (res[i-1] % 2)) / 2
-		res[i] = convertCanonicalSorted(t, _s)
-		_s = _s / 2
+// foldCodeword folds the codeword p of the i-th step, given in natural order,
+// with the challenge beta. The result is the codeword of the folded polynomial,
+// in natural order, on the domain of size len(p)/arity.
+func (s radixTwoFri) foldCodeword(i int, p []fr.Element, beta fr.Element) []fr.Element {
+	step := &s.steps[i]
+	m := int(step.size) / step.arity
+	res := make([]fr.Element, m)
+	values := make([]fr.Element, step.arity)
+	var xInv fr.Element
+	xInv.SetOne()
+	for j := 0; j < m; j++ {
+		for k := 0; k < step.arity; k++ {
+			values[k] = p[j+k*m]
+		}
+		res[j] = step.foldCoset(values, xInv, beta)
+		xInv.Mul(&xInv, &step.gInv)
 	}
+	return res
+}
 
+// foldCoefficients folds the polynomial p, given in canonical basis: if
+// p = ∑ᵢ XⁱPᵢ(X^arity), it returns ∑ᵢ βⁱPᵢ.
+func foldCoefficients(p []fr.Element, arity int, beta fr.Element) []fr.Element {
+	res := make([]fr.Element, len(p)/arity)
+	for j := range res {
+		for i := arity - 1; i >= 0; i-- {
+			res[j].Mul(&res[j], &beta).Add(&res[j], &p[j*arity+i])
+		}
+	}
 	return res
 }
 
-// sort orders the evaluation of a polynomial on a domain
-// such that contiguous entries are in the same fiber:
-// {q(g⁰), q(g^{n/2}), q(g¹), q(g^{1+n/2}),...,q(g^{n/2-1}), q(gⁿ⁻¹)}
-func sort(evaluations []fr.Element) []fr.Element {
-	q := make([]fr.Element, len(evaluations))
-	n := len(evaluations) / 2
-	for i := 0; i < n; i++ {
-		q[2*i].Set(&evaluations[i])
-		q[2*i+1].Set(&evaluations[i+n])
+// codeword returns the evaluation of p on the domain, in natural order.
+func (s radixTwoFri) codeword(p []fr.Element) ([]fr.Element, error) {
+	if uint64(len(p)) > s.degreeBound {
+		return nil, ErrPolynomialSize
 	}
-	return q
+	q := make([]fr.Element, s.domain.Cardinality)
+	copy(q, p)
+	s.domain.FFT(q, fft.DIF)
+	fft.BitReverse(q)
+	return q, nil
 }
 
 // Opens a polynomial at gⁱ where i = position.
@@ -265,31 +387,21 @@ func (s radixTwoFri) Open(p []fr.Element, position uint64) (OpeningProof, error)
 	}
 
 	// put q in evaluation form
-	q := make([]fr.Element, s.domain.Cardinality)
-	copy(q, p)
-	s.domain.FFT(q, fft.DIF)
-	fft.BitReverse(q)
-
-	// sort q to have fibers in contiguous entries. The goal is to have one
-	// Merkle path for both openings of entries which are in the same fiber.
-	q = sort(q)
-
-	// build the Merkle proof, we the position is converted to fit the sorted polynomial
-	pos := convertCanonicalSorted(int(position), len(q))
-
-	tree := merkletree.New(s.h)
-	err := tree.SetIndex(uint64(pos))
+	q, err := s.codeword(p)
 	if err != nil {
 		return OpeningProof{}, err
 	}
-	for i := 0; i < len(q); i++ {
-		tree.Push(q[i].Marshal())
-	}
-	var res OpeningProof
-	res.merkleRoot, res.ProofSet, res.index, res.numLeaves = tree.Prove()
 
-	// set the claimed value, which is the first entry of the Merkle proof
-	res.ClaimedValue.SetBytes(res.ProofSet[0])
+	// the opening proof is the Merkle proof of the coset containing the position,
+	// in the tree of the first folding step.
+	t := newMerkleTree(s.h, s.leaves(0, q))
+	m := s.domain.Cardinality / uint64(s.steps[0].arity)
+	mp := t.proof(position % m)
+
+	var res OpeningProof
+	res.merkleRoot, res.ProofSet, res.numLeaves = mp.MerkleRoot, mp.ProofSet, mp.numLeaves
+	res.index = position % m
+	res.ClaimedValue.Set(&q[position])
 
 	return res, nil
 }
@@ -303,396 +415,361 @@ func (s radixTwoFri) Open(p []fr.Element, position uint64) (OpeningProof, error)
 // those should be equal, if not an error is raised.
 func (s radixTwoFri) VerifyOpening(position uint64, openingProof OpeningProof, pp ProofOfProximity) error {
 
-	// To query the Merkle path, we look at the first series of Interactions, and check whether it's the point
-	// at 'position' or its neighbor that contains the full Merkle path.
-	var fullMerkleProof int
-	if len(pp.Rounds[0].Interactions[0][0].ProofSet) > len(pp.Rounds[0].Interactions[0][1].ProofSet) {
-		fullMerkleProof = 0
-	} else {
-		fullMerkleProof = 1
+	if position >= s.domain.Cardinality {
+		return ErrRangePosition
+	}
+	if len(pp.Rounds) == 0 || len(pp.Rounds[0].Interactions) == 0 {
+		return ErrProofShape
 	}
 
 	// check that the merkle roots coincide
-	if !bytes.Equal(openingProof.merkleRoot, pp.Rounds[0].Interactions[0][fullMerkleProof].MerkleRoot) {
+	if !bytes.Equal(openingProof.merkleRoot, pp.Rounds[0].Interactions[0].MerkleRoot) {
 		return ErrMerkleRoot
 	}
 
-	// convert position to the sorted version
-	sizePoly := s.domain.Cardinality
-	pos := convertCanonicalSorted(int(position), int(sizePoly))
-
-	// check the Merkle proof
-	res := merkletree.VerifyProof(s.h, openingProof.merkleRoot, openingProof.ProofSet, uint64(pos), openingProof.numLeaves)
-	if !res {
+	// the leaf is the coset containing the position
+	m := s.domain.Cardinality / uint64(s.steps[0].arity)
+	if !merkletree.VerifyProof(s.h, openingProof.merkleRoot, openingProof.ProofSet, position%m, m) {
+		return ErrMerklePath
+	}
+	coset, err := s.readLeaf(0, openingProof.ProofSet[0])
+	if err != nil {
+		return err
+	}
+	if !coset[position/m].Equal(&openingProof.ClaimedValue) {
 		return ErrMerklePath
 	}
 	return nil
 
 }
 
-// foldPolynomialLagrangeBasis folds a polynomial p, expressed in Lagrange basis.
-//
-// Fᵣ[X]/(Xⁿ-1) is a free module of rank 2 on Fᵣ[Y]/(Y^{n/2}-1). If
-// p∈ Fᵣ[X]/(Xⁿ-1), expressed in Lagrange basis, the function finds the coordinates
-// p₁, p₂ of p in Fᵣ[Y]/(Y^{n/2}-1), expressed in Lagrange basis. Finally, it computes
-// p₁ + x*p₂ and returns it.
-//
-// * p is the polynomial to fold, in Lagrange basis, sorted like this: p = [p(1),p(-1),p(g),p(-g),p(g²),p(-g²),...]
-// * g is a generator of the subgroup of Fᵣ^{*} of size len(p)
-// * x is the folding challenge x, used to return p₁+x*p₂
-func foldPolynomialLagrangeBasis(pSorted []fr.Element, gInv, x fr.Element) []fr.Element {
-
-	// we have the following system
-	// p₁(g²ⁱ)+gⁱp₂(g²ⁱ) = p(gⁱ)
-	// p₁(g²ⁱ)-gⁱp₂(g²ⁱ) = p(-gⁱ)
-	// we solve the system for p₁(g²ⁱ),p₂(g²ⁱ)
-	s := len(pSorted)
-	res := make([]fr.Element, s/2)
-
-	var p1, p2, acc fr.Element
-	acc.SetOne()
-
-	for i := 0; i < s/2; i++ {
+// transcript returns the Fiat Shamir transcript of the protocol. The challenges
+// are the ones given by the caller, the folding challenges xᵢ, the seed of the
+// proof of work, and the seed of the queries.
+func (s radixTwoFri) transcript(challengesID ...string) *fiatshamir.Transcript {
+	ids := make([]string, 0, len(challengesID)+len(s.steps)+2)
+	ids = append(ids, challengesID...)
+	for i := range s.steps {
+		ids = append(ids, fmt.Sprintf("x%d", i))
+	}
+	ids = append(ids, "pow", "q")
+	return fiatshamir.NewTranscript(s.h, ids...)
+}
 
-		p1.Add(&pSorted[2*i], &pSorted[2*i+1])
-		p2.Sub(&pSorted[2*i], &pSorted[2*i+1]).Mul(&p2, &acc)
-		res[i].Mul(&p2, &x).Add(&res[i], &p1).Mul(&res[i], &twoInv)
+// deriveQueriesPositions derives the positions queried by the verifier in the
+// initial codeword, from the seed given by Fiat Shamir.
+func (s radixTwoFri) deriveQueriesPositions(seed []byte) []uint64 {
+	res := make([]uint64, s.config.nbQueries)
+	var bPos, bCardinality big.Int
+	bCardinality.SetUint64(s.domain.Cardinality)
+	var buf [8]byte
+	for i := range res {
+		binary.BigEndian.PutUint64(buf[:], uint64(i))
+		s.h.Reset()
+		s.h.Write(seed)
+		s.h.Write(buf[:])
+		bPos.SetBytes(s.h.Sum(nil))
+		bPos.Mod(&bPos, &bCardinality)
+		res[i] = bPos.Uint64()
+	}
+	return res
+}
 
-		acc.Mul(&acc, &gInv)
+// checkProofOfWork returns true if H(seed ∥ nonce) starts with config.powBits zero bits.
+func (s radixTwoFri) checkProofOfWork(seed []byte, nonce uint64) bool {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], nonce)
+	s.h.Reset()
+	s.h.Write(seed)
+	s.h.Write(buf[:])
+	digest := s.h.Sum(nil)
+	nbZeros := 0
+	for i := 0; i < len(digest) && nbZeros < s.config.powBits; i++ {
+		z := bits.LeadingZeros8(digest[i])
+		nbZeros += z
+		if z < 8 {
+			break
+		}
+	}
+	return nbZeros >= s.config.powBits
+}
 
+// finalChallenges binds the final polynomial and the nonce to the transcript,
+// and returns the positions of the queries. If grind is true, the nonce is
+// computed, otherwise the proof of work is checked.
+func (s radixTwoFri) finalChallenges(fs *fiatshamir.Transcript, finalPolynomial []fr.Element, nonce *uint64, grind bool) ([]uint64, error) {
+	for i := range finalPolynomial {
+		if err := fs.Bind("pow", finalPolynomial[i].Marshal()); err != nil {
+			return nil, err
+		}
+	}
+	powSeed, err := fs.ComputeChallenge("pow")
+	if err != nil {
+		return nil, err
+	}
+	if grind {
+		for *nonce = 0; !s.checkProofOfWork(powSeed, *nonce); *nonce++ {
+		}
+	} else if !s.checkProofOfWork(powSeed, *nonce) {
+		return nil, ErrProofOfWork
 	}
 
-	return res
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], *nonce)
+	if err := fs.Bind("q", buf[:]); err != nil {
+		return nil, err
+	}
+	seed, err := fs.ComputeChallenge("q")
+	if err != nil {
+		return nil, err
+	}
+	return s.deriveQueriesPositions(seed), nil
 }
 
-// buildProofOfProximitySingleRound generates a proof that a function, given as an oracle from
+// BuildProofOfProximity generates a proof that a function, given as an oracle from
 // the verifier point of view, is in fact δ-close to a polynomial.
-// * salt is a variable for multi rounds, it allows to generate different challenges using Fiat Shamir
-// * p is in evaluation form
-func (s radixTwoFri) buildProofOfProximitySingleRound(salt fr.Element, p []fr.Element) (Round, error) {
-
-	// the proof will contain nbSteps Interactions
-	var res Round
-	res.Interactions = make([][2]MerkleProof, s.nbSteps)
-
-	// Fiat Shamir transcript to derive the challenges. The xᵢ are used to fold the
-	// polynomials.
-	// During the i-th round, the prover has a polynomial P of degree n. The verifier sends
-	// xᵢ∈ Fᵣ to the prover. The prover expresses F in Fᵣ[X,Y]/<Y-X²> as
-	// P₀(Y)+X P₁(Y) where P₀, P₁ are of degree n/2, and he then folds the polynomial
-	// by replacing x by xᵢ.
-	xis := make([]string, s.nbSteps+1)
-	for i := 0; i < s.nbSteps; i++ {
-		xis[i] = fmt.Sprintf("x%d", i)
-	}
-	xis[s.nbSteps] = "s0"
-	fs := fiatshamir.NewTranscript(s.h, xis...)
-
-	// the salt is binded to the first challenge, to ensure the challenges
-	// are different at each round.
-	err := fs.Bind(xis[0], salt.Marshal())
+func (s radixTwoFri) BuildProofOfProximity(p []fr.Element) (ProofOfProximity, error) {
+
+	// evaluate p
+	codeword, err := s.codeword(p)
 	if err != nil {
-		return Round{}, err
+		return ProofOfProximity{}, err
 	}
+	coefficients := make([]fr.Element, s.degreeBound)
+	copy(coefficients, p)
 
-	// step 1 : fold the polynomial using the xi
-
-	// evalsAtRound stores the list of the nbSteps polynomial evaluations, each evaluation
-	// corresponds to the evaluation o the folded polynomial at round i.
-	evalsAtRound := make([][]fr.Element, s.nbSteps)
-
-	// evaluate p and sort the result
-	_p := make([]fr.Element, s.domain.Cardinality)
-	copy(_p, p)
+	return s.buildProof(s.transcript(), codeword, coefficients, nil)
+}
 
-	// gInv inverse of the generator of the cyclic group of size the size of the polynomial.
-	// The size of the cyclic group is ρ*s.domainSize, and not s.domainSize.
-	var gInv fr.Element
-	gInv.Set(&s.domain.GeneratorInv)
+// buildProof runs the commit phase on the codeword of the polynomial whose
+// coefficients are given, then the query phase.
+// If first is not nil, it is the tree committing to the first codeword, whose
+// root is already bound to the transcript by the caller.
+func (s radixTwoFri) buildProof(fs *fiatshamir.Transcript, codeword, coefficients []fr.Element, first *merkleTree) (ProofOfProximity, error) {
 
-	for i := 0; i < s.nbSteps; i++ {
+	var proof ProofOfProximity
 
-		evalsAtRound[i] = sort(_p)
+	// step 1 : commit to the successive foldings of the polynomial.
+	// During the i-th step, the prover has a polynomial P. The verifier sends
+	// xᵢ∈ Fᵣ to the prover. The prover writes P = ∑ⱼ XʲPⱼ(X^arity), and folds
+	// the polynomial into ∑ⱼ xᵢʲPⱼ.
+	trees := make([]merkleTree, len(s.steps))
+	for i := range s.steps {
 
 		// compute the root hash, needed to derive xi
-		t := merkletree.New(s.h)
-		for k := 0; k < len(_p); k++ {
-			t.Push(evalsAtRound[i][k].Marshal())
-		}
-		rh := t.Root()
-		err := fs.Bind(xis[i], rh)
-		if err != nil {
-			return res, err
+		xi := fmt.Sprintf("x%d", i)
+		if i == 0 && first != nil {
+			trees[i] = *first
+		} else {
+			trees[i] = newMerkleTree(s.h, s.leaves(i, codeword))
+			if err := fs.Bind(xi, trees[i].root()); err != nil {
+				return proof, err
+			}
 		}
 
 		// derive the challenge
-		bxi, err := fs.ComputeChallenge(xis[i])
+		bxi, err := fs.ComputeChallenge(xi)
 		if err != nil {
-			return res, err
+			return proof, err
 		}
-		var xi fr.Element
-		xi.SetBytes(bxi)
-
-		// fold _p, reusing its memory
-		_p = foldPolynomialLagrangeBasis(evalsAtRound[i], gInv, xi)
-
-		// g <- g²
-		gInv.Square(&gInv)
+		var beta fr.Element
+		beta.SetBytes(bxi)
 
+		codeword = s.foldCodeword(i, codeword, beta)
+		coefficients = foldCoefficients(coefficients, s.steps[i].arity, beta)
 	}
 
-	// last round, provide the evaluation. The fully folded polynomial is of size rho. It should
-	// correspond to the evaluation of a polynomial of degree 1 on ρ points, so those points
-	// are supposed to be on a line.
-	res.Evaluation.Set(&_p[0])
-
-	// step 2: provide the Merkle proofs of the queries
+	// last step, provide the folded polynomial in clear.
+	proof.FinalPolynomial = coefficients
 
-	// derive the verifier queries
-	err = fs.Bind(xis[s.nbSteps], res.Evaluation.Marshal())
+	// step 2: grind and derive the queries
+	positions, err := s.finalChallenges(fs, proof.FinalPolynomial, &proof.Nonce, true)
 	if err != nil {
-		return res, err
+		return proof, err
 	}
-	binSeed, err := fs.ComputeChallenge(xis[s.nbSteps])
-	if err != nil {
-		return res, err
-	}
-	var bPos, bCardinality big.Int
-	bPos.SetBytes(binSeed)
-	bCardinality.SetUint64(s.domain.Cardinality)
-	bPos.Mod(&bPos, &bCardinality)
-	si := s.deriveQueriesPositions(int(bPos.Uint64()), int(s.domain.Cardinality))
-
-	for i := 0; i < s.nbSteps; i++ {
 
-		// build proofs of queries at s[i]
-		t := merkletree.New(s.h)
-		err := t.SetIndex(uint64(si[i]))
-		if err != nil {
-			return res, err
-		}
-		for k := 0; k < len(evalsAtRound[i]); k++ {
-			t.Push(evalsAtRound[i][k].Marshal())
-		}
-		mr, ProofSet, _, numLeaves := t.Prove()
-
-		// c denotes the entry that contains the full Merkle proof. The entry 1-c will
-		// only contain 2 elements, which are the neighbor point, and the hash of the
-		// first point. The remaining of the Merkle path is common to both the original
-		// point and its neighbor.
-		c := si[i] % 2
-		res.Interactions[i][c] = MerkleProof{mr, ProofSet, numLeaves}
-		res.Interactions[i][1-c] = MerkleProof{
-			mr,
-			make([][]byte, 2),
-			numLeaves,
-		}
-		res.Interactions[i][1-c].ProofSet[0] = evalsAtRound[i][si[i]+1-2*c].Marshal()
-		s.h.Reset()
-		_, err = s.h.Write(res.Interactions[i][c].ProofSet[0])
-		if err != nil {
-			return res, err
+	// step 3: provide the Merkle proofs of the queries
+	proof.Rounds = make([]Round, len(positions))
+	for i, position := range positions {
+		proof.Rounds[i].Interactions = make([]MerkleProof, len(s.steps))
+		for j := range s.steps {
+			position %= s.steps[j].size / uint64(s.steps[j].arity)
+			proof.Rounds[i].Interactions[j] = trees[j].proof(position)
 		}
-		res.Interactions[i][1-c].ProofSet[1] = s.h.Sum(nil)
-
 	}
 
-	return res, nil
-
+	return proof, nil
 }
 
-// BuildProofOfProximity generates a proof that a function, given as an oracle from
-// the verifier point of view, is in fact δ-close to a polynomial.
-func (s radixTwoFri) BuildProofOfProximity(p []fr.Element) (ProofOfProximity, error) {
-
-	// the proof will contain nbSteps Interactions
-	var proof ProofOfProximity
-	proof.Rounds = make([]Round, nbRounds)
-
-	// evaluate p
-	// evaluate p and sort the result
-	_p := make([]fr.Element, s.domain.Cardinality)
-	copy(_p, p)
-	s.domain.FFT(_p, fft.DIF)
-	fft.BitReverse(_p)
-
-	var err error
-	var salt, one fr.Element
-	one.SetOne()
-	for i := 0; i < nbRounds; i++ {
-		proof.Rounds[i], err = s.buildProofOfProximitySingleRound(salt, _p)
-		if err != nil {
-			return proof, err
-		}
-		salt.Add(&salt, &one)
+// VerifyProofOfProximity verifies the proof, by checking each query one
+// by one.
+func (s radixTwoFri) VerifyProofOfProximity(proof ProofOfProximity) error {
+	readFirstLeaf := func(_ uint64, leaf []byte) ([]fr.Element, error) {
+		return s.readLeaf(0, leaf)
 	}
-
-	return proof, nil
+	return s.verifyProof(s.transcript(), proof, true, readFirstLeaf)
 }
 
-// verifyProofOfProximitySingleRound verifies the proof of proximity. It returns an error if the
-// verification fails.
-func (s radixTwoFri) verifyProofOfProximitySingleRound(salt fr.Element, proof Round) error {
-
-	// Fiat Shamir transcript to derive the challenges
-	xis := make([]string, s.nbSteps+1)
-	for i := 0; i < s.nbSteps; i++ {
-		xis[i] = fmt.Sprintf("x%d", i)
+// verifyProof checks the shape of the proof, derives the challenges and
+// verifies each query.
+// If bindFirstRoot is false, the first Merkle root is already bound to the
+// transcript by the caller.
+// readFirstLeaf returns the values of the first codeword on the coset stored
+// in the leaf at the given index of the first tree.
+func (s radixTwoFri) verifyProof(fs *fiatshamir.Transcript, proof ProofOfProximity, bindFirstRoot bool, readFirstLeaf func(index uint64, leaf []byte) ([]fr.Element, error)) error {
+
+	// check the shape of the proof
+	if len(proof.Rounds) != s.config.nbQueries {
+		return ErrProofShape
 	}
-	xis[s.nbSteps] = "s0"
-	fs := fiatshamir.NewTranscript(s.h, xis...)
-
-	xi := make([]fr.Element, s.nbSteps)
-
-	// the salt is binded to the first challenge, to ensure the challenges
-	// are different at each round.
-	err := fs.Bind(xis[0], salt.Marshal())
-	if err != nil {
-		return err
+	for i := range proof.Rounds {
+		if len(proof.Rounds[i].Interactions) != len(s.steps) {
+			return ErrProofShape
+		}
+	}
+	if uint64(len(proof.FinalPolynomial)) > s.finalDegreeBound {
+		return ErrLowDegree
 	}
 
-	for i := 0; i < s.nbSteps; i++ {
-		err := fs.Bind(xis[i], proof.Interactions[i][0].MerkleRoot)
-		if err != nil {
-			return err
+	// derive the challenges, the roots are those of the first query and
+	// the other queries are checked against them.
+	betas := make([]fr.Element, len(s.steps))
+	for i := range s.steps {
+		xi := fmt.Sprintf("x%d", i)
+		if i > 0 || bindFirstRoot {
+			if err := fs.Bind(xi, proof.Rounds[0].Interactions[i].MerkleRoot); err != nil {
+				return err
+			}
 		}
-		bxi, err := fs.ComputeChallenge(xis[i])
+		bxi, err := fs.ComputeChallenge(xi)
 		if err != nil {
 			return err
 		}
-		xi[i].SetBytes(bxi)
+		betas[i].SetBytes(bxi)
 	}
-
-	// derive the verifier queries
-	// for i := 0; i < len(proof.evaluation); i++ {
-	// 	err := fs.Bind(xis[s.nbSteps], proof.evaluation[i].Marshal())
-	// 	if err != nil {
-	// 		return err
-	// 	}
-	// }
-	err = fs.Bind(xis[s.nbSteps], proof.Evaluation.Marshal())
+	nonce := proof.Nonce
+	positions, err := s.finalChallenges(fs, proof.FinalPolynomial, &nonce, false)
 	if err != nil {
 		return err
 	}
-	binSeed, err := fs.ComputeChallenge(xis[s.nbSteps])
-	if err != nil {
-		return err
+
+	for i, position := range positions {
+		if err := s.verifyQuery(position, proof.Rounds[i], proof.Rounds[0], betas, proof.FinalPolynomial, readFirstLeaf); err != nil {
+			return err
+		}
 	}
-	var bPos, bCardinality big.Int
-	bPos.SetBytes(binSeed)
-	bCardinality.SetUint64(s.domain.Cardinality)
-	bPos.Mod(&bPos, &bCardinality)
-	si := s.deriveQueriesPositions(int(bPos.Uint64()), int(s.domain.Cardinality))
 
-	// for each round check the Merkle proof and the correctness of the folding
+	return nil
+}
+
+// verifyQuery checks the Merkle proofs of a query and the correctness of the
+// successive foldings, up to the final polynomial.
+func (s radixTwoFri) verifyQuery(position uint64, round, first Round, betas, finalPolynomial []fr.Element, readFirstLeaf func(uint64, []byte) ([]fr.Element, error)) error {
 
-	// current size of the polynomial
-	var accGInv fr.Element
-	accGInv.Set(&s.domain.GeneratorInv)
-	for i := 0; i < s.nbSteps; i++ {
+	var folded, xInv fr.Element
+	for i := range s.steps {
+
+		step := &s.steps[i]
+		m := step.size / uint64(step.arity)
+		index, offset := position%m, position/m
+		mp := &round.Interactions[i]
 
 		// correctness of Merkle proof
-		// c is the entry containing the full Merkle proof.
-		c := si[i] % 2
-		res := merkletree.VerifyProof(
-			s.h,
-			proof.Interactions[i][c].MerkleRoot,
-			proof.Interactions[i][c].ProofSet,
-			uint64(si[i]),
-			proof.Interactions[i][c].numLeaves,
-		)
-		if !res {
+		if !bytes.Equal(mp.MerkleRoot, first.Interactions[i].MerkleRoot) {
+			return ErrMerkleRoot
+		}
+		if !merkletree.VerifyProof(s.h, mp.MerkleRoot, mp.ProofSet, index, m) {
 			return ErrMerklePath
 		}
+		var coset []fr.Element
+		var err error
+		if i == 0 {
+			coset, err = readFirstLeaf(index, mp.ProofSet[0])
+		} else {
+			coset, err = s.readLeaf(i, mp.ProofSet[0])
+		}
+		if err != nil {
+			return err
+		}
 
-		// we verify the Merkle proof for the neighbor query, to do that we have
-		// to pick the full Merkle proof of the first entry, stripped off of the leaf and
-		// the first node. We replace the leaf and the first node by the leaf and the first
-		// node of the partial Merkle proof, since the leaf and the first node of both proofs
-		// are the only entries that differ.
-		ProofSet := make([][]byte, len(proof.Interactions[i][c].ProofSet))
-		copy(ProofSet[2:], proof.Interactions[i][c].ProofSet[2:])
-		ProofSet[0] = proof.Interactions[i][1-c].ProofSet[0]
-		ProofSet[1] = proof.Interactions[i][1-c].ProofSet[1]
-		res = merkletree.VerifyProof(
-			s.h,
-			proof.Interactions[i][1-c].MerkleRoot,
-			ProofSet,
-			uint64(si[i]+1-2*c),
-			proof.Interactions[i][1-c].numLeaves,
-		)
-		if !res {
-			return ErrMerklePath
+		// correctness of the previous folding
+		if i > 0 && !coset[offset].Equal(&folded) {
+			return ErrProximityTestFolding
 		}
 
-		// correctness of the folding
-		if i < s.nbSteps-1 {
+		// the coset is {xωʲ} where x = g^index
+		xInv.Exp(step.gInv, new(big.Int).SetUint64(index))
+		folded = step.foldCoset(coset, xInv, betas[i])
 
-			var fe, fo, l, r, fn fr.Element
+		position = index
+	}
 
-			// l = P(gⁱ), r = P(g^{i+n/2})
-			l.SetBytes(proof.Interactions[i][0].ProofSet[0])
-			r.SetBytes(proof.Interactions[i][1].ProofSet[0])
+	// the last folded value should be the evaluation of the final polynomial
+	var x, eval fr.Element
+	x.Exp(s.finalGenerator, new(big.Int).SetUint64(position))
+	for i := len(finalPolynomial) - 1; i >= 0; i-- {
+		eval.Mul(&eval, &x).Add(&eval, &finalPolynomial[i])
+	}
+	if !eval.Equal(&folded) {
+		return ErrProximityTestFolding
+	}
 
-			// (g^{si[i]}, g^{si[i]+1}) is the fiber of g^{2*si[i]}. The system to solve
-			// (for P₀(g^{2si[i]}), P₀(g^{2si[i]}) ) is:
-			// P(g^{si[i]}) = P₀(g^{2si[i]}) +  g^{si[i]/2}*P₀(g^{2si[i]})
-			// P(g^{si[i]+1}) = P₀(g^{2si[i]}) -  g^{si[i]/2}*P₀(g^{2si[i]})
-			bm := big.NewInt(int64(si[i] / 2))
-			var ginv fr.Element
-			ginv.Exp(accGInv, bm)
-			fe.Add(&l, &r)                                      // P₁(g²ⁱ) (to be multiplied by 2⁻¹)
-			fo.Sub(&l, &r).Mul(&fo, &ginv)                      // P₀(g²ⁱ) (to be multiplied by 2⁻¹)
-			fo.Mul(&fo, &xi[i]).Add(&fo, &fe).Mul(&fo, &twoInv) // P₀(g²ⁱ) + xᵢ * P₁(g²ⁱ)
+	return nil
+}
 
-			fn.SetBytes(proof.Interactions[i+1][si[i+1]%2].ProofSet[0])
+// merkleTree is a Merkle tree on a power of 2 number of leaves, hashed as in
+// merkletree.Tree. All the nodes are kept in memory so that the proofs of all
+// the queries are computed from a single tree.
+type merkleTree struct {
+	h      hash.Hash
+	leaves [][]byte
 
-			if !fo.Equal(&fn) {
-				return ErrProximityTestFolding
-			}
+	// nodes[0] contains the hashes of the leaves, nodes[len(nodes)-1] the root.
+	nodes [][][]byte
+}
 
-			// next inverse generator
-			accGInv.Square(&accGInv)
+func newMerkleTree(h hash.Hash, leaves [][]byte) merkleTree {
+	t := merkleTree{h: h, leaves: leaves}
+	level := make([][]byte, len(leaves))
+	for i := range leaves {
+		level[i] = t.sum(leaves[i])
+	}
+	t.nodes = append(t.nodes, level)
+	for len(level) > 1 {
+		next := make([][]byte, len(level)/2)
+		for i := range next {
+			next[i] = t.sum(level[2*i], level[2*i+1])
 		}
-
+		t.nodes = append(t.nodes, next)
+		level = next
 	}
+	return t
+}
 
-	// last transition
-	var fe, fo, l, r fr.Element
-
-	l.SetBytes(proof.Interactions[s.nbSteps-1][0].ProofSet[0])
-	r.SetBytes(proof.Interactions[s.nbSteps-1][1].ProofSet[0])
-
-	_si := si[s.nbSteps-1] / 2
-
-	accGInv.Exp(accGInv, big.NewInt(int64(_si)))
-
-	fe.Add(&l, &r)                                                // P₁(g²ⁱ) (to be multiplied by 2⁻¹)
-	fo.Sub(&l, &r).Mul(&fo, &accGInv)                             // P₀(g²ⁱ) (to be multiplied by 2⁻¹)
-	fo.Mul(&fo, &xi[s.nbSteps-1]).Add(&fo, &fe).Mul(&fo, &twoInv) // P₀(g²ⁱ) + xᵢ * P₁(g²ⁱ)
-
-	// Last step: the final evaluation should be the evaluation of a degree 0 polynomial,
-	// so it must be constant.
-	if !fo.Equal(&proof.Evaluation) {
-		return ErrProximityTestFolding
+func (t *merkleTree) sum(data ...[]byte) []byte {
+	t.h.Reset()
+	for i := range data {
+		t.h.Write(data[i])
 	}
-
-	return nil
+	return t.h.Sum(nil)
 }
 
-// VerifyProofOfProximity verifies the proof, by checking each interaction one
-// by one.
-func (s radixTwoFri) VerifyProofOfProximity(proof ProofOfProximity) error {
+func (t *merkleTree) root() []byte {
+	return t.nodes[len(t.nodes)-1][0]
+}
 
-	var salt, one fr.Element
-	one.SetOne()
-	for i := 0; i < nbRounds; i++ {
-		err := s.verifyProofOfProximitySingleRound(salt, proof.Rounds[i])
-		if err != nil {
-			return err
-		}
-		salt.Add(&salt, &one)
+// proof returns the Merkle proof of the leaf at index, in the format expected by
+// merkletree.VerifyProof.
+func (t *merkleTree) proof(index uint64) MerkleProof {
+	res := MerkleProof{
+		MerkleRoot: t.root(),
+		ProofSet:   make([][]byte, 1, len(t.nodes)),
+		numLeaves:  uint64(len(t.leaves)),
 	}
-	return nil
-
+	res.ProofSet[0] = t.leaves[index]
+	for i := 0; i < len(t.nodes)-1; i++ {
+		res.ProofSet = append(res.ProofSet, t.nodes[i][index^1])
+		index >>= 1
+	}
+	return res
 }
@@ -23,24 +23,12 @@ import (
 	"testing"
 
 	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/fft"
 	"github.com/leanovate/gopter"
 	"github.com/leanovate/gopter/gen"
 	"github.com/leanovate/gopter/prop"
 )
 
-// logFiber returns u, v such that {g^u, g^v} = f⁻¹((g²)^{_p})
-func logFiber(_p, _n int) (_u, _v big.Int) {
-	if _p%2 == 0 {
-		_u.SetInt64(int64(_p / 2))
-		_v.SetInt64(int64(_p/2 + _n/2))
-	} else {
-		l := (_n - 1 - _p) / 2
-		_u.SetInt64(int64(_n - 1 - l))
-		_v.SetInt64(int64(_n - 1 - l - _n/2))
-	}
-	return
-}
-
 func randomPolynomial(size uint64, seed int32) []fr.Element {
 	p := make([]fr.Element, size)
 	p[0].SetUint64(uint64(seed))
@@ -50,18 +38,6 @@ func randomPolynomial(size uint64, seed int32) []fr.Element {
 	return p
 }
 
-// convertSortedCanonical convert the index i, an entry in a
-// sorted polynomial, to the corresponding entry in canonical
-// representation. n is the size of the polynomial.
-func convertSortedCanonical(i, n int) int {
-	if i%2 == 0 {
-		return i / 2
-	} else {
-		l := (n - 1 - i) / 2
-		return n - 1 - l
-	}
-}
-
 func TestFRI(t *testing.T) {
 
 	parameters := gopter.DefaultTestParameters()
@@ -95,7 +71,7 @@ func TestFRI(t *testing.T) {
 			return err != nil
 
 		},
-		gen.Int32Range(1, int32(rho*size)),
+		gen.Int32Range(1, int32(DefaultBlowup*size)),
 	))
 
 	properties.Property("verifying correct opening should succeed", prop.ForAll(
@@ -121,7 +97,7 @@ func TestFRI(t *testing.T) {
 			return err == nil
 
 		},
-		gen.Int32Range(0, int32(rho*size)),
+		gen.Int32Range(0, int32(DefaultBlowup*size)),
 	))
 
 	properties.Property("The claimed value of a polynomial should match P(x)", prop.ForAll(
@@ -152,42 +128,39 @@ func TestFRI(t *testing.T) {
 			return openingProof.ClaimedValue.Equal(&val)
 
 		},
-		gen.Int32Range(0, int32(rho*size)),
+		gen.Int32Range(0, int32(DefaultBlowup*size)),
 	))
 
-	properties.Property("Derive queries position: points should belong the correct fiber", prop.ForAll(
+	properties.Property("folding the codeword should give the codeword of the folded polynomial", prop.ForAll(
 
-		func(m int32) bool {
+		func(m int32, logArity int) bool {
 
-			_s := RADIX_2_FRI.New(uint64(size), sha256.New())
+			_s := RADIX_2_FRI.New(uint64(size), sha256.New(), WithFoldingArity(1<<logArity))
 			s := _s.(radixTwoFri)
 
-			var g fr.Element
-
-			_m := int(m) % size
-			pos := s.deriveQueriesPositions(_m, int(s.domain.Cardinality))
-			g.Set(&s.domain.Generator)
-			n := int(s.domain.Cardinality)
-
-			for i := 0; i < len(pos)-1; i++ {
+			p := randomPolynomial(uint64(size), m)
+			var beta fr.Element
+			beta.SetUint64(uint64(m))
 
-				u, v := logFiber(pos[i], n)
+			codeword, _ := s.codeword(p)
+			folded := s.foldCodeword(0, codeword, beta)
 
-				var g1, g2, g3 fr.Element
-				g1.Exp(g, &u).Square(&g1)
-				g2.Exp(g, &v).Square(&g2)
-				nextPos := convertSortedCanonical(pos[i+1], n/2)
-				g3.Square(&g).Exp(g3, big.NewInt(int64(nextPos)))
+			q := foldCoefficients(p, s.steps[0].arity, beta)
+			d := fft.NewDomain(uint64(len(folded)))
+			expected := make([]fr.Element, d.Cardinality)
+			copy(expected, q)
+			d.FFT(expected, fft.DIF)
+			fft.BitReverse(expected)
 
-				if !g1.Equal(&g2) || !g1.Equal(&g3) {
+			for i := range folded {
+				if !folded[i].Equal(&expected[i]) {
 					return false
 				}
-				g.Square(&g)
-				n = n >> 1
 			}
 			return true
 		},
-		gen.Int32Range(0, int32(rho*size)),
+		gen.Int32Range(0, int32(DefaultBlowup*size)),
+		gen.IntRange(1, 4),
 	))
 
 	properties.Property("verifying a correctly formed proof should succeed", prop.ForAll(
@@ -205,13 +178,112 @@ func TestFRI(t *testing.T) {
 			err = iop.VerifyProofOfProximity(proof)
 			return err == nil
 		},
-		gen.Int32Range(0, int32(rho*size)),
+		gen.Int32Range(0, int32(DefaultBlowup*size)),
 	))
 
 	properties.TestingRun(t, gopter.ConsoleReporter(false))
 
 }
 
+func TestFRIOptions(t *testing.T) {
+
+	const size = 1000
+
+	p := randomPolynomial(size, 3)
+
+	for _, blowup := range []int{2, 4, 16} {
+		for _, arity := range []int{2, 4, 8, 16} {
+			for _, finalDegree := range []int{0, 5, 64} {
+
+				name := fmt.Sprintf("blowup=%d/arity=%d/final=%d", blowup, arity, finalDegree)
+				t.Run(name, func(t *testing.T) {
+
+					iop := RADIX_2_FRI.New(size, sha256.New(),
+						WithBlowup(blowup),
+						WithFoldingArity(arity),
+						WithNbQueries(5),
+						WithProofOfWork(4),
+						WithFinalDegree(finalDegree),
+					)
+					proof, err := iop.BuildProofOfProximity(p)
+					if err != nil {
+						t.Fatal(err)
+					}
+					if len(proof.Rounds) != 5 {
+						t.Fatal("wrong number of queries")
+					}
+					if len(proof.FinalPolynomial) != int(iop.(radixTwoFri).finalDegreeBound) {
+						t.Fatal("wrong size of the final polynomial")
+					}
+					if err := iop.VerifyProofOfProximity(proof); err != nil {
+						t.Fatal(err)
+					}
+				})
+			}
+		}
+	}
+}
+
+func TestFRITamperedProof(t *testing.T) {
+
+	const size = 512
+
+	p := randomPolynomial(size, 7)
+	iop := RADIX_2_FRI.New(size, sha256.New(),
+		WithFoldingArity(4),
+		WithNbQueries(8),
+		WithProofOfWork(8),
+		WithFinalDegree(3),
+	)
+
+	build := func() ProofOfProximity {
+		proof, err := iop.BuildProofOfProximity(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return proof
+	}
+
+	proof := build()
+	if err := iop.VerifyProofOfProximity(proof); err != nil {
+		t.Fatal(err)
+	}
+
+	proof.FinalPolynomial[1].SetOne()
+	if err := iop.VerifyProofOfProximity(proof); err == nil {
+		t.Fatal("a wrong final polynomial should be rejected")
+	}
+
+	proof = build()
+	proof.FinalPolynomial = append(proof.FinalPolynomial, fr.One())
+	if err := iop.VerifyProofOfProximity(proof); err != ErrLowDegree {
+		t.Fatal("a final polynomial of high degree should be rejected")
+	}
+
+	proof = build()
+	proof.Nonce++
+	if err := iop.VerifyProofOfProximity(proof); err == nil {
+		t.Fatal("a wrong nonce should be rejected")
+	}
+
+	proof = build()
+	proof.Rounds = proof.Rounds[1:]
+	if err := iop.VerifyProofOfProximity(proof); err != ErrProofShape {
+		t.Fatal("a proof with a wrong number of queries should be rejected")
+	}
+
+	proof = build()
+	proof.Rounds[2].Interactions[1].ProofSet[0][0] ^= 1
+	if err := iop.VerifyProofOfProximity(proof); err == nil {
+		t.Fatal("a tampered leaf should be rejected")
+	}
+
+	// a polynomial of too large degree can't be committed
+	if _, err := iop.BuildProofOfProximity(randomPolynomial(size+1, 7)); err != ErrPolynomialSize {
+		t.Fatal("a polynomial of too large degree should be rejected")
+	}
+}
+
 // Benchmarks
 
 func BenchmarkProximityVerification(b *testing.B) {
@@ -116,10 +116,6 @@ func ProveLookupTables(pk kzg.ProvingKey, f, t []fr.Vector) (ProofLookupTables,
 		}
 		d.FFTInverse(cfs[i], fft.DIF)
 		fft.BitReverse(cfs[i])
-		proof.fs[i], err = kzg.Commit(cfs[i], pk)
-		if err != nil {
-			return proof, err
-		}
 
 		cts[i] = make([]fr.Element, nbColumns)
 		lts[i] = make([]fr.Element, nbColumns)
@@ -131,12 +127,16 @@ func ProveLookupTables(pk kzg.ProvingKey, f, t []fr.Vector) (ProofLookupTables,
 		}
 		d.FFTInverse(cts[i], fft.DIF)
 		fft.BitReverse(cts[i])
-		proof.ts[i], err = kzg.Commit(cts[i], pk)
-		if err != nil {
-			return proof, err
-		}
 	}
 
+	// commit to f and t
+	digests, err := kzg.BatchCommit(append(cfs, cts...), pk)
+	if err != nil {
+		return proof, err
+	}
+	copy(proof.fs, digests[:nbRows])
+	copy(proof.ts, digests[nbRows:])
+
 	// fold f and t
 	comms := make([]*kzg.Digest, 2*nbRows)
 	for i := 0; i < nbRows; i++ {
@@ -0,0 +1,224 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package kzg
+
+import (
+	"errors"
+	"math/big"
+	"math/bits"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/fft"
+
+	"github.com/consensys/gnark-crypto/internal/parallel"
+)
+
+var (
+	ErrCosetSize          = errors.New("the size of the cosets should be a power of 2 dividing the size of the domain")
+	ErrVerifyCosetOpening = errors.New("can't verify coset opening proof")
+)
+
+// CosetOpeningProof proves the evaluations of a polynomial f on a coset zH,
+// where H is the subgroup of order k of the roots of unity.
+type CosetOpeningProof struct {
+	// H commitment to the quotient (f - r)/(Xᵏ - zᵏ), where r interpolates
+	// the claimed values on the coset
+	H bn254.G1Affine
+
+	// ClaimedValues f(zζⁱ), i < k, where ζ generates H
+	ClaimedValues []fr.Element
+}
+
+// OpenAll computes the opening proofs of p at all the points ωʲ of the domain,
+// in O(n log n) operations instead of O(n²) for n calls to Open, following
+// Feist and Khovratovich, "Fast amortized KZG proofs". The i-th proof is the
+// opening proof at ωⁱ.
+func OpenAll(p []fr.Element, domain *fft.Domain, pk ProvingKey) ([]OpeningProof, error) {
+	proofs, err := OpenAllCosets(p, 1, domain, pk)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]OpeningProof, len(proofs))
+	for i := range proofs {
+		res[i] = OpeningProof{H: proofs[i].H, ClaimedValue: proofs[i].ClaimedValues[0]}
+	}
+	return res, nil
+}
+
+// OpenAllCosets computes the opening proofs of p on all the cosets ωʲH of the
+// domain, j < n/k, where H is the subgroup of order k, in O(n log n)
+// operations. The j-th proof opens p on the coset ωʲH.
+//
+// The quotient by Xᵏ - ωʲᵏ of p is ∑ₜ ωʲᵏᵗHₜ, where the Hₜ = ∑ᵢ pᵢ₊₍ₜ₊₁₎ₖ[τⁱ]G₁
+// don't depend on j: they are computed with k Toeplitz matrix-vector products
+// in G₁, and the proofs with a FFT of size n/k in G₁.
+func OpenAllCosets(p []fr.Element, k uint64, domain *fft.Domain, pk ProvingKey) ([]CosetOpeningProof, error) {
+	n := domain.Cardinality
+	if len(p) == 0 || uint64(len(p)) > n || n > uint64(len(pk.G1)) {
+		return nil, ErrInvalidPolynomialSize
+	}
+	if k == 0 || bits.OnesCount64(k) != 1 || k > n || n%k != 0 {
+		return nil, ErrCosetSize
+	}
+
+	h, err := toeplitzQuotients(p, k, n, pk)
+	if err != nil {
+		return nil, err
+	}
+
+	// the proofs are the evaluations of ∑ₜ Hₜ Xᵗ at ωʲᵏ
+	var omegaK fr.Element
+	omegaK.Exp(domain.Generator, new(big.Int).SetUint64(k))
+	fftG1(h, omegaK)
+	hAff := bn254.BatchJacobianToAffineG1(h)
+
+	// evaluations of p on the domain
+	evaluations := make([]fr.Element, n)
+	copy(evaluations, p)
+	domain.FFT(evaluations, fft.DIF)
+	fft.BitReverse(evaluations)
+
+	nbCosets := n / k
+	proofs := make([]CosetOpeningProof, nbCosets)
+	for j := range proofs {
+		proofs[j].H = hAff[j]
+		proofs[j].ClaimedValues = make([]fr.Element, k)
+		for i := range proofs[j].ClaimedValues {
+			proofs[j].ClaimedValues[i] = evaluations[uint64(j)+uint64(i)*nbCosets]
+		}
+	}
+	return proofs, nil
+}
+
+// toeplitzQuotients returns the Hₜ = ∑ᵢ pᵢ₊₍ₜ₊₁₎ₖ[τⁱ]G₁, t < n/k. Splitting
+// the sum by the residue ρ of i modulo k, each part is a Toeplitz
+// matrix-vector product of size L = n/k, computed as a circular convolution
+// of size 2L with FFTs, sharing the last inverse FFT.
+func toeplitzQuotients(p []fr.Element, k, n uint64, pk ProvingKey) ([]bn254.G1Jac, error) {
+	l := n / k
+	m := 2 * l
+
+	var omega, omegaInv fr.Element
+	var err error
+	if omega, err = fr.Generator(m); err != nil {
+		return nil, err
+	}
+	omegaInv.Inverse(&omega)
+	domain := fft.NewDomain(m)
+
+	// ∑_ρ FFT(Fᵨ) ⋅ FFT(Sᵨ) / 2L, where Sᵨ = ([τ^(ρ+kv)]G₁)_{v<L-1} and Fᵨ = (p_{ρ+k(L-1-u)})_{u<L}
+	var mInv fr.Element
+	mInv.SetUint64(m).Inverse(&mInv)
+	acc := make([]bn254.G1Jac, m)
+	s := make([]bn254.G1Jac, m)
+	f := make([]fr.Element, m)
+	for rho := uint64(0); rho < k; rho++ {
+		for v := range s {
+			s[v] = bn254.G1Jac{}
+			if uint64(v) < l-1 {
+				s[v].FromAffine(&pk.G1[rho+k*uint64(v)])
+			}
+		}
+		fftG1(s, omega)
+
+		for u := range f {
+			f[u].SetZero()
+			if uint64(u) < l {
+				if idx := rho + k*(l-1-uint64(u)); idx < uint64(len(p)) {
+					f[u].Mul(&p[idx], &mInv)
+				}
+			}
+		}
+		domain.FFT(f, fft.DIF)
+		fft.BitReverse(f)
+
+		parallel.Execute(int(m), func(start, end int) {
+			var t bn254.G1Jac
+			var b big.Int
+			for i := start; i < end; i++ {
+				t.ScalarMultiplication(&s[i], f[i].BigInt(&b))
+				acc[i].AddAssign(&t)
+			}
+		})
+	}
+	fftG1(acc, omegaInv)
+
+	// Hₜ is the coefficient L-2-t of the convolution
+	h := make([]bn254.G1Jac, l)
+	for t := uint64(0); t+1 < l; t++ {
+		h[t] = acc[l-2-t]
+	}
+	return h, nil
+}
+
+// VerifyCosetOpening verifies an opening proof of a polynomial on the coset zH
+// returned by OpenAllCosets, where H is the subgroup of order k =
+// len(proof.ClaimedValues). The verifier needs the k first points of the
+// ProvingKey, and g2TauK = [τᵏ]G₂.
+func VerifyCosetOpening(commitment *Digest, proof *CosetOpeningProof, z fr.Element, pk ProvingKey, g2TauK bn254.G2Affine) error {
+	k := uint64(len(proof.ClaimedValues))
+	if k == 0 || bits.OnesCount64(k) != 1 || k > uint64(len(pk.G1)) {
+		return ErrCosetSize
+	}
+
+	// r(zY) = ∑ cᵢYⁱ interpolates the claimed values on H, so that
+	// r(X) = ∑ cᵢz⁻ⁱXⁱ
+	r := make([]fr.Element, k)
+	copy(r, proof.ClaimedValues)
+	if k > 1 {
+		fft.NewDomain(k).FFTInverse(r, fft.DIF)
+		fft.BitReverse(r)
+	}
+	var zInv, zInvI fr.Element
+	zInv.Inverse(&z)
+	zInvI.SetOne()
+	for i := range r {
+		r[i].Mul(&r[i], &zInvI)
+		zInvI.Mul(&zInvI, &zInv)
+	}
+	var rCommit bn254.G1Affine
+	if _, err := rCommit.MultiExp(pk.G1[:k], r, ecc.MultiExpConfig{}); err != nil {
+		return err
+	}
+
+	// e(C - [r(τ)]G₁, G₂) = e(H, [τᵏ - zᵏ]G₂)
+	var left bn254.G1Affine
+	left.Sub(commitment, &rCommit)
+	_, _, _, g2 := bn254.Generators()
+	var zK fr.Element
+	var bZK big.Int
+	zK.Exp(z, new(big.Int).SetUint64(k)).BigInt(&bZK)
+	var right bn254.G2Affine
+	right.ScalarMultiplication(&g2, &bZK)
+	right.Sub(&g2TauK, &right)
+
+	var negH bn254.G1Affine
+	negH.Neg(&proof.H)
+	check, err := bn254.PairingCheck(
+		[]bn254.G1Affine{left, negH},
+		[]bn254.G2Affine{g2, right},
+	)
+	if err != nil {
+		return err
+	}
+	if !check {
+		return ErrVerifyCosetOpening
+	}
+	return nil
+}
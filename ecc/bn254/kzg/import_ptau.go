@@ -0,0 +1,303 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package kzg
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"sync"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
+
+	"github.com/consensys/gnark-crypto/internal/parallel"
+)
+
+var (
+	ErrPtauFormat = errors.New("invalid ptau file")
+	ErrPh1Format  = errors.New("invalid ph1 file")
+)
+
+// ptau section types
+const (
+	ptauSectionHeader = 1
+	ptauSectionTauG1  = 2
+	ptauSectionTauG2  = 3
+)
+
+// ImportPtau reads a powers of tau file in the snarkjs ptau format and returns
+// the corresponding SRS. The points are stored uncompressed, in little-endian
+// Montgomery form.
+//
+// The points are checked to be on the curve and in the correct subgroups, and
+// to be the consecutive powers of a same τ. If maxPkPoints is provided, the
+// number of points in the ProvingKey will be limited to maxPkPoints.
+func ImportPtau(r io.Reader, maxPkPoints ...int) (*SRS, error) {
+	br := bufio.NewReader(r)
+
+	var magic [4]byte
+	if _, err := io.ReadFull(br, magic[:]); err != nil {
+		return nil, err
+	}
+	if string(magic[:]) != "ptau" {
+		return nil, ErrPtauFormat
+	}
+	var version, nbSections uint32
+	if err := binary.Read(br, binary.LittleEndian, &version); err != nil {
+		return nil, err
+	}
+	if err := binary.Read(br, binary.LittleEndian, &nbSections); err != nil {
+		return nil, err
+	}
+
+	// the sections are read in order, the header comes first
+	power := -1
+	var g1 []bn254.G1Affine
+	var g2 [2]bn254.G2Affine
+	var hasG2 bool
+	for i := uint32(0); i < nbSections && (g1 == nil || !hasG2); i++ {
+		var sectionType uint32
+		var sectionSize uint64
+		if err := binary.Read(br, binary.LittleEndian, &sectionType); err != nil {
+			return nil, err
+		}
+		if err := binary.Read(br, binary.LittleEndian, &sectionSize); err != nil {
+			return nil, err
+		}
+		section := io.LimitReader(br, int64(sectionSize))
+
+		switch sectionType {
+		case ptauSectionHeader:
+			var err error
+			if power, err = readPtauHeader(section); err != nil {
+				return nil, err
+			}
+		case ptauSectionTauG1:
+			if power < 0 {
+				return nil, fmt.Errorf("%w: missing header", ErrPtauFormat)
+			}
+			nbPoints := 2<<power - 1
+			if sectionSize != uint64(nbPoints)*2*fp.Bytes {
+				return nil, fmt.Errorf("%w: wrong size of the section of the powers of τ in G₁", ErrPtauFormat)
+			}
+			var err error
+			if g1, err = readPointsG1(section, pkSize(nbPoints, maxPkPoints), readLEMG1); err != nil {
+				return nil, err
+			}
+		case ptauSectionTauG2:
+			if power < 0 {
+				return nil, fmt.Errorf("%w: missing header", ErrPtauFormat)
+			}
+			if sectionSize != (1<<power)*4*fp.Bytes {
+				return nil, fmt.Errorf("%w: wrong size of the section of the powers of τ in G₂", ErrPtauFormat)
+			}
+			var buf [4 * fp.Bytes]byte
+			for j := range g2 {
+				if _, err := io.ReadFull(section, buf[:]); err != nil {
+					return nil, err
+				}
+				if err := readLEMG2(&g2[j], buf[:]); err != nil {
+					return nil, err
+				}
+			}
+			hasG2 = true
+		}
+
+		// skip the remaining of the section
+		if _, err := io.Copy(io.Discard, section); err != nil {
+			return nil, err
+		}
+	}
+	if g1 == nil || !hasG2 {
+		return nil, fmt.Errorf("%w: missing powers of τ", ErrPtauFormat)
+	}
+
+	if err := checkPowers(g1, g2); err != nil {
+		return nil, err
+	}
+	return srsFromPowers(g1, g2), nil
+}
+
+// readPtauHeader reads the header section of a ptau file and returns the
+// power of the ceremony (the number of G₂ points is 2ᵖᵒʷᵉʳ).
+func readPtauHeader(r io.Reader) (int, error) {
+	var n8 uint32
+	if err := binary.Read(r, binary.LittleEndian, &n8); err != nil {
+		return 0, err
+	}
+	if n8 != fp.Bytes {
+		return 0, fmt.Errorf("%w: wrong size of the base field elements", ErrPtauFormat)
+	}
+	var q [fp.Bytes]byte
+	if _, err := io.ReadFull(r, q[:]); err != nil {
+		return 0, err
+	}
+	for i := 0; i < fp.Bytes/2; i++ {
+		q[i], q[fp.Bytes-1-i] = q[fp.Bytes-1-i], q[i]
+	}
+	if fp.Modulus().Cmp(new(big.Int).SetBytes(q[:])) != 0 {
+		return 0, fmt.Errorf("%w: wrong base field", ErrPtauFormat)
+	}
+	var power uint32
+	if err := binary.Read(r, binary.LittleEndian, &power); err != nil {
+		return 0, err
+	}
+	if power < 1 || power > 32 {
+		return 0, fmt.Errorf("%w: invalid power", ErrPtauFormat)
+	}
+	return int(power), nil
+}
+
+// readLEM decodes a base field element in little-endian Montgomery form, as in
+// the snarkjs files.
+func readLEM(b []byte) (fp.Element, error) {
+	e, err := fp.LittleEndian.Element((*[fp.Bytes]byte)(b))
+	if err != nil {
+		return e, err
+	}
+	// e is the Montgomery form of the encoded value m, multiplying by the
+	// element whose Montgomery form is 1 gives the element whose Montgomery
+	// form is m.
+	montOne := fp.Element{1}
+	e.Mul(&e, &montOne)
+	return e, nil
+}
+
+// readLEMG1 decodes a point of G₁ encoded as x ∥ y in little-endian Montgomery form
+func readLEMG1(p *bn254.G1Affine, b []byte) error {
+	var err error
+	if p.X, err = readLEM(b[:fp.Bytes]); err != nil {
+		return err
+	}
+	if p.Y, err = readLEM(b[fp.Bytes:]); err != nil {
+		return err
+	}
+	if p.IsInfinity() || !p.IsOnCurve() || !p.IsInSubGroup() {
+		return errors.New("invalid point: not in the subgroup")
+	}
+	return nil
+}
+
+// readLEMG2 decodes a point of G₂ encoded as x.A0 ∥ x.A1 ∥ y.A0 ∥ y.A1 in
+// little-endian Montgomery form
+func readLEMG2(p *bn254.G2Affine, b []byte) error {
+	coordinates := []*fp.Element{&p.X.A0, &p.X.A1, &p.Y.A0, &p.Y.A1}
+	for i, c := range coordinates {
+		var err error
+		if *c, err = readLEM(b[i*fp.Bytes : (i+1)*fp.Bytes]); err != nil {
+			return err
+		}
+	}
+	if p.IsInfinity() || !p.IsOnCurve() || !p.IsInSubGroup() {
+		return errors.New("invalid point: not in the subgroup")
+	}
+	return nil
+}
+
+// ImportPh1 reads a challenge file of the Perpetual Powers of Tau ceremony
+// (ph1) of 2ᵖᵒʷᵉʳ powers and returns the corresponding SRS. The file starts
+// with the 64 bytes hash of the previous response, followed by the powers of τ
+// in G₁ and in G₂, stored uncompressed in big-endian form.
+//
+// The points are checked to be on the curve and in the correct subgroups, and
+// to be the consecutive powers of a same τ. If maxPkPoints is provided, the
+// number of points in the ProvingKey will be limited to maxPkPoints.
+func ImportPh1(r io.Reader, power int, maxPkPoints ...int) (*SRS, error) {
+	if power < 1 || power > 32 {
+		return nil, fmt.Errorf("%w: invalid power", ErrPh1Format)
+	}
+	br := bufio.NewReader(r)
+
+	// hash of the previous response
+	if _, err := io.CopyN(io.Discard, br, 64); err != nil {
+		return nil, err
+	}
+
+	nbPoints := 2<<power - 1
+	size := pkSize(nbPoints, maxPkPoints)
+	g1, err := readPointsG1(br, size, readUncompressedG1)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.CopyN(io.Discard, br, int64(nbPoints-size)*bn254.SizeOfG1AffineUncompressed); err != nil {
+		return nil, err
+	}
+
+	var g2 [2]bn254.G2Affine
+	var buf [bn254.SizeOfG2AffineUncompressed]byte
+	for i := range g2 {
+		if _, err := io.ReadFull(br, buf[:]); err != nil {
+			return nil, err
+		}
+		if buf[0]&(0b11<<6) != 0 {
+			return nil, fmt.Errorf("%w: compressed or infinity point", ErrPh1Format)
+		}
+		if _, err := g2[i].SetBytes(buf[:]); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := checkPowers(g1, g2); err != nil {
+		return nil, err
+	}
+	return srsFromPowers(g1, g2), nil
+}
+
+// readUncompressedG1 decodes a point of G₁ encoded as x ∥ y in big-endian form
+func readUncompressedG1(p *bn254.G1Affine, b []byte) error {
+	if b[0]&(0b11<<6) != 0 {
+		return fmt.Errorf("%w: compressed or infinity point", ErrPh1Format)
+	}
+	_, err := p.SetBytes(b)
+	return err
+}
+
+// readPointsG1 reads and decodes the size first points of G₁ of r
+func readPointsG1(r io.Reader, size int, decode func(*bn254.G1Affine, []byte) error) ([]bn254.G1Affine, error) {
+	const pointSize = 2 * fp.Bytes
+	buf := make([]byte, size*pointSize)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return nil, err
+	}
+
+	points := make([]bn254.G1Affine, size)
+	var errDecode error
+	var errLock sync.Mutex
+	parallel.Execute(size, func(start, end int) {
+		for i := start; i < end; i++ {
+			if err := decode(&points[i], buf[i*pointSize:(i+1)*pointSize]); err != nil {
+				errLock.Lock()
+				errDecode = err
+				errLock.Unlock()
+				return
+			}
+		}
+	})
+	return points, errDecode
+}
+
+// pkSize returns the number of points of the ProvingKey, out of nbPoints
+func pkSize(nbPoints int, maxPkPoints []int) int {
+	if len(maxPkPoints) > 0 && maxPkPoints[0] < nbPoints && maxPkPoints[0] > 0 {
+		return maxPkPoints[0]
+	}
+	return nbPoints
+}
@@ -0,0 +1,253 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package kzg
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
+)
+
+// powersOfTau returns the powers of τ of a ceremony of 2ᵖᵒʷᵉʳ powers
+func powersOfTau(t *testing.T, power int, tau *big.Int) ([]bn254.G1Affine, []bn254.G2Affine) {
+	srs, err := NewSRS(2<<power-1, tau)
+	require.NoError(t, err)
+	g2 := make([]bn254.G2Affine, 1<<power)
+	g2[0] = srs.Vk.G2[0]
+	for i := 1; i < len(g2); i++ {
+		g2[i].ScalarMultiplication(&g2[i-1], tau)
+	}
+	return srs.Pk.G1, g2
+}
+
+// writeLEM writes the Montgomery form of e in little-endian
+func writeLEM(buf *bytes.Buffer, e ...fp.Element) {
+	for i := range e {
+		for _, w := range e[i] {
+			_ = binary.Write(buf, binary.LittleEndian, w)
+		}
+	}
+}
+
+// ptauFile encodes the powers of τ in the snarkjs ptau format
+func ptauFile(power int, g1 []bn254.G1Affine, g2 []bn254.G2Affine) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("ptau")
+	_ = binary.Write(&buf, binary.LittleEndian, []uint32{1, 4})
+
+	// header
+	q := fp.Modulus().Bytes()
+	for i := 0; i < len(q)/2; i++ {
+		q[i], q[len(q)-1-i] = q[len(q)-1-i], q[i]
+	}
+	_ = binary.Write(&buf, binary.LittleEndian, uint32(ptauSectionHeader))
+	_ = binary.Write(&buf, binary.LittleEndian, uint64(4+fp.Bytes+8))
+	_ = binary.Write(&buf, binary.LittleEndian, uint32(fp.Bytes))
+	buf.Write(q)
+	_ = binary.Write(&buf, binary.LittleEndian, []uint32{uint32(power), uint32(power)})
+
+	// powers of τ
+	_ = binary.Write(&buf, binary.LittleEndian, uint32(ptauSectionTauG1))
+	_ = binary.Write(&buf, binary.LittleEndian, uint64(len(g1)*2*fp.Bytes))
+	for i := range g1 {
+		writeLEM(&buf, g1[i].X, g1[i].Y)
+	}
+	_ = binary.Write(&buf, binary.LittleEndian, uint32(ptauSectionTauG2))
+	_ = binary.Write(&buf, binary.LittleEndian, uint64(len(g2)*4*fp.Bytes))
+	for i := range g2 {
+		writeLEM(&buf, g2[i].X.A0, g2[i].X.A1, g2[i].Y.A0, g2[i].Y.A1)
+	}
+
+	// other sections are skipped
+	_ = binary.Write(&buf, binary.LittleEndian, uint32(4))
+	_ = binary.Write(&buf, binary.LittleEndian, uint64(3))
+	buf.Write([]byte{1, 2, 3})
+
+	return buf.Bytes()
+}
+
+// ph1File encodes the powers of τ as a challenge file of the Perpetual Powers of Tau
+func ph1File(g1 []bn254.G1Affine, g2 []bn254.G2Affine) []byte {
+	var buf bytes.Buffer
+	buf.Write(make([]byte, 64))
+	for i := range g1 {
+		b := g1[i].RawBytes()
+		buf.Write(b[:])
+	}
+	for i := range g2 {
+		b := g2[i].RawBytes()
+		buf.Write(b[:])
+	}
+	// α and β powers
+	buf.Write(make([]byte, 2*len(g2)*bn254.SizeOfG1AffineUncompressed))
+	return buf.Bytes()
+}
+
+func TestImportPtau(t *testing.T) {
+	assert := require.New(t)
+
+	const power = 5
+	tau := big.NewInt(1789)
+	g1, g2 := powersOfTau(t, power, tau)
+	expected, err := NewSRS(uint64(len(g1)), tau)
+	assert.NoError(err)
+
+	srs, err := ImportPtau(bytes.NewReader(ptauFile(power, g1, g2)))
+	assert.NoError(err)
+	assert.Equal(expected, srs)
+
+	// truncated SRS
+	srs, err = ImportPtau(bytes.NewReader(ptauFile(power, g1, g2)), 10)
+	assert.NoError(err)
+	assert.Equal(expected.Pk.G1[:10], srs.Pk.G1)
+	assert.Equal(expected.Vk, srs.Vk)
+
+	// inconsistent powers
+	tampered := make([]bn254.G1Affine, len(g1))
+	copy(tampered, g1)
+	tampered[3], tampered[4] = tampered[4], tampered[3]
+	_, err = ImportPtau(bytes.NewReader(ptauFile(power, tampered, g2)))
+	assert.ErrorIs(err, ErrMpcSetupPowers)
+
+	// invalid files
+	file := ptauFile(power, g1, g2)
+	_, err = ImportPtau(bytes.NewReader(file[:len(file)/2]))
+	assert.Error(err)
+	_, err = ImportPtau(bytes.NewReader(ptauFile(power+1, g1, g2)))
+	assert.ErrorIs(err, ErrPtauFormat)
+	file[0] = 'q'
+	_, err = ImportPtau(bytes.NewReader(file))
+	assert.ErrorIs(err, ErrPtauFormat)
+
+	// a point which is not on the curve
+	tampered[3], tampered[4] = tampered[4], tampered[3]
+	tampered[5].Y.SetOne()
+	_, err = ImportPtau(bytes.NewReader(ptauFile(power, tampered, g2)))
+	assert.Error(err)
+}
+
+func TestImportPh1(t *testing.T) {
+	assert := require.New(t)
+
+	const power = 5
+	tau := big.NewInt(42)
+	g1, g2 := powersOfTau(t, power, tau)
+	expected, err := NewSRS(uint64(len(g1)), tau)
+	assert.NoError(err)
+
+	srs, err := ImportPh1(bytes.NewReader(ph1File(g1, g2)), power)
+	assert.NoError(err)
+	assert.Equal(expected, srs)
+
+	// truncated SRS
+	srs, err = ImportPh1(bytes.NewReader(ph1File(g1, g2)), power, 10)
+	assert.NoError(err)
+	assert.Equal(expected.Pk.G1[:10], srs.Pk.G1)
+	assert.Equal(expected.Vk, srs.Vk)
+
+	// wrong power
+	_, err = ImportPh1(bytes.NewReader(ph1File(g1, g2)), power-1)
+	assert.Error(err)
+
+	// inconsistent powers
+	tampered := make([]bn254.G2Affine, len(g2))
+	copy(tampered, g2)
+	tampered[1] = tampered[2]
+	_, err = ImportPh1(bytes.NewReader(ph1File(g1, tampered)), power)
+	assert.ErrorIs(err, ErrMpcSetupPowers)
+}
+
+// TestReadLEMSnarkjs decodes points written by snarkjs, in the encoding of the
+// ptau files. testdata/ballot_proof_header.zkey holds the first two sections
+// of circuits/ballotproof/circom_assets/ballot_proof_pkey.zkey of
+// github.com/vocdoni/davinci-node v0.0.1, a Groth16 key set up by snarkjs from
+// a Hermez ptau: its α and β are the ones of the ptau and are published as
+// vk_alpha_1 and vk_beta_2 in ballot_proof_vkey.json, its δ and γ are the
+// generators.
+func TestReadLEMSnarkjs(t *testing.T) {
+	assert := require.New(t)
+
+	file, err := os.ReadFile("testdata/ballot_proof_header.zkey")
+	assert.NoError(err)
+	r := bytes.NewReader(file)
+
+	// the sections are framed as in the ptau files
+	var magic [4]byte
+	_, err = io.ReadFull(r, magic[:])
+	assert.NoError(err)
+	assert.Equal("zkey", string(magic[:]))
+	var version, nbSections uint32
+	assert.NoError(binary.Read(r, binary.LittleEndian, &version))
+	assert.NoError(binary.Read(r, binary.LittleEndian, &nbSections))
+	var sectionType uint32
+	var sectionSize uint64
+	for {
+		assert.NoError(binary.Read(r, binary.LittleEndian, &sectionType))
+		assert.NoError(binary.Read(r, binary.LittleEndian, &sectionSize))
+		if sectionType == 2 {
+			break
+		}
+		_, err = r.Seek(int64(sectionSize), io.SeekCurrent)
+		assert.NoError(err)
+	}
+	header := make([]byte, sectionSize)
+	_, err = io.ReadFull(r, header)
+	assert.NoError(err)
+
+	// n8q ∥ q ∥ n8r ∥ r ∥ nVars ∥ nPublic ∥ domainSize, then the points
+	assert.Equal(uint32(fp.Bytes), binary.LittleEndian.Uint32(header))
+	q := make([]byte, fp.Bytes)
+	for i := range q {
+		q[i] = header[4+fp.Bytes-1-i]
+	}
+	assert.Equal(fp.Modulus(), new(big.Int).SetBytes(q))
+	points := header[2*(4+fp.Bytes)+12:]
+
+	// α ∥ βG₁ ∥ βG₂ ∥ γG₂ ∥ δG₁ ∥ δG₂
+	const sizeG1, sizeG2 = 2 * fp.Bytes, 4 * fp.Bytes
+	var alpha, delta1 bn254.G1Affine
+	var beta, gamma bn254.G2Affine
+	assert.NoError(readLEMG1(&alpha, points[:sizeG1]))
+	assert.NoError(readLEMG2(&beta, points[2*sizeG1:2*sizeG1+sizeG2]))
+	assert.NoError(readLEMG2(&gamma, points[2*sizeG1+sizeG2:2*sizeG1+2*sizeG2]))
+	assert.NoError(readLEMG1(&delta1, points[2*sizeG1+2*sizeG2:3*sizeG1+2*sizeG2]))
+
+	element := func(s string) fp.Element {
+		var e fp.Element
+		_, err := e.SetString(s)
+		assert.NoError(err)
+		return e
+	}
+	assert.Equal(element("20636558247165583357368579937126721642956017696858471082087318080438266562919"), alpha.X)
+	assert.Equal(element("9412628053302548157709185916410648035105851419466944971169259585978381609041"), alpha.Y)
+	assert.Equal(element("277107384414915639629352944148395289906373836095109600629340270768497470492"), beta.X.A0)
+	assert.Equal(element("1947334687211392601962422827505790579610911016077992832174516469650416189720"), beta.X.A1)
+	assert.Equal(element("706073735357536074062796489477501520556283046304943143788545586574314796662"), beta.Y.A0)
+	assert.Equal(element("21810691200851279684567550495344007109827514342465119679543525192561805782097"), beta.Y.A1)
+
+	_, _, g1, g2 := bn254.Generators()
+	assert.Equal(g1, delta1)
+	assert.Equal(g2, gamma)
+}
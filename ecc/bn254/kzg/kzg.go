@@ -45,6 +45,32 @@ type Digest = bn254.G1Affine
 // ProvingKey used to create or open commitments
 type ProvingKey struct {
 	G1 []bn254.G1Affine // [G₁ [α]G₁ , [α²]G₁, ... ]
+
+	// table of shifted copies of G1 for the multi-exponentiations of Commit, see Precompute
+	table   *bn254.G1MultiExpTable
+	tableG1 []bn254.G1Affine // the points the table was built from
+}
+
+// Precompute precomputes up to nbCopies shifted copies of the points of the
+// proving key, which speed up the multi-exponentiations of Commit (and Open)
+// for nbCopies times the memory of the key. It must be called again if pk.G1
+// is modified.
+func (pk *ProvingKey) Precompute(nbCopies int) error {
+	table, err := bn254.NewG1MultiExpTable(pk.G1, nbCopies)
+	if err != nil {
+		return err
+	}
+	pk.table, pk.tableG1 = table, pk.G1
+	return nil
+}
+
+// precomputed returns the table of Precompute, or nil if there is none or if
+// pk.G1 was replaced since.
+func (pk *ProvingKey) precomputed() *bn254.G1MultiExpTable {
+	if pk.table == nil || len(pk.G1) == 0 || len(pk.G1) != len(pk.tableG1) || &pk.G1[0] != &pk.tableG1[0] {
+		return nil
+	}
+	return pk.table
 }
 
 // VerifyingKey used to verify opening proofs
@@ -179,6 +205,12 @@ func Commit(p []fr.Element, pk ProvingKey, nbTasks ...int) (Digest, error) {
 	if len(nbTasks) > 0 {
 		config.NbTasks = nbTasks[0]
 	}
+	if table := pk.precomputed(); table != nil && len(p) <= table.NbPoints() {
+		if _, err := res.MultiExpPrecomputed(table, p, config); err != nil {
+			return Digest{}, err
+		}
+		return res, nil
+	}
 	if _, err := res.MultiExp(pk.G1[:len(p)], p, config); err != nil {
 		return Digest{}, err
 	}
@@ -186,6 +218,35 @@ func Commit(p []fr.Element, pk ProvingKey, nbTasks ...int) (Digest, error) {
 	return res, nil
 }
 
+// BatchCommit commits to several polynomials using multi exponentiations with the SRS,
+// which are scheduled together (see MultiExpBatchG1).
+// It is assumed that the polynomials are in canonical form, in Montgomery form.
+func BatchCommit(polynomials [][]fr.Element, pk ProvingKey, nbTasks ...int) ([]Digest, error) {
+	for _, p := range polynomials {
+		if len(p) == 0 || len(p) > len(pk.G1) {
+			return nil, ErrInvalidPolynomialSize
+		}
+	}
+
+	// with precomputed copies of the SRS, the commitments are computed one by one
+	if pk.precomputed() != nil {
+		res := make([]Digest, len(polynomials))
+		for i := range polynomials {
+			var err error
+			if res[i], err = Commit(polynomials[i], pk, nbTasks...); err != nil {
+				return nil, err
+			}
+		}
+		return res, nil
+	}
+
+	config := ecc.MultiExpConfig{}
+	if len(nbTasks) > 0 {
+		config.NbTasks = nbTasks[0]
+	}
+	return bn254.MultiExpBatchG1(pk.G1, polynomials, config)
+}
+
 // Open computes an opening proof of polynomial p at given point.
 // fft.Domain Cardinality must be larger than p.Degree()
 func Open(p []fr.Element, point fr.Element, pk ProvingKey) (OpeningProof, error) {
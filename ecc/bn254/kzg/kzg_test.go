@@ -198,6 +198,73 @@ func TestCommit(t *testing.T) {
 
 }
 
+func TestCommitPrecomputed(t *testing.T) {
+	assert := require.New(t)
+
+	pk := ProvingKey{G1: testSrs.Pk.G1}
+	assert.NoError(pk.Precompute(4))
+
+	for _, size := range []int{len(pk.G1), 60, 1} {
+		f := randomPolynomial(size)
+		expected, err := Commit(f, testSrs.Pk)
+		assert.NoError(err)
+		got, err := Commit(f, pk)
+		assert.NoError(err)
+		assert.True(expected.Equal(&got), "size %d", size)
+	}
+
+	assert.Error(pk.Precompute(0))
+}
+
+func TestCommitPrecomputedStale(t *testing.T) {
+	assert := require.New(t)
+
+	otherSrs, err := NewSRS(uint64(len(testSrs.Pk.G1)), big.NewInt(7))
+	assert.NoError(err)
+	var buf bytes.Buffer
+	_, err = otherSrs.Pk.WriteTo(&buf)
+	assert.NoError(err)
+
+	f := randomPolynomial(60)
+	expected, err := Commit(f, otherSrs.Pk)
+	assert.NoError(err)
+
+	// the points are decoded in place, the table must be dropped
+	pk := ProvingKey{G1: make([]bn254.G1Affine, len(testSrs.Pk.G1))}
+	copy(pk.G1, testSrs.Pk.G1)
+	assert.NoError(pk.Precompute(4))
+	_, err = pk.ReadFrom(bytes.NewReader(buf.Bytes()))
+	assert.NoError(err)
+	got, err := Commit(f, pk)
+	assert.NoError(err)
+	assert.True(expected.Equal(&got), "stale table after ReadFrom")
+
+	// the points are replaced, the table must be ignored
+	pk = ProvingKey{G1: testSrs.Pk.G1}
+	assert.NoError(pk.Precompute(4))
+	pk.G1 = otherSrs.Pk.G1
+	got, err = Commit(f, pk)
+	assert.NoError(err)
+	assert.True(expected.Equal(&got), "stale table after replacing G1")
+}
+
+func TestBatchCommit(t *testing.T) {
+	assert := require.New(t)
+
+	polynomials := [][]fr.Element{randomPolynomial(60), randomPolynomial(1), randomPolynomial(len(testSrs.Pk.G1))}
+	digests, err := BatchCommit(polynomials, testSrs.Pk)
+	assert.NoError(err)
+	assert.Len(digests, len(polynomials))
+	for i := range polynomials {
+		expected, err := Commit(polynomials[i], testSrs.Pk)
+		assert.NoError(err)
+		assert.True(expected.Equal(&digests[i]), "polynomial %d", i)
+	}
+
+	_, err = BatchCommit(append(polynomials, nil), testSrs.Pk)
+	assert.ErrorIs(err, ErrInvalidPolynomialSize)
+}
+
 func TestVerifySinglePoint(t *testing.T) {
 
 	// create a polynomial
@@ -502,6 +569,18 @@ func BenchmarkKZGCommit(b *testing.B) {
 			_, _ = Commit(p, srs.Pk)
 		}
 	})
+	b.Run("precomputed SRS", func(b *testing.B) {
+		srs, err := NewSRS(ecc.NextPowerOfTwo(benchSize), new(big.Int).SetInt64(42))
+		assert.NoError(b, err)
+		assert.NoError(b, srs.Pk.Precompute(8))
+		// random polynomial
+		p := randomPolynomial(benchSize / 2)
+
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			_, _ = Commit(p, srs.Pk)
+		}
+	})
 	b.Run("quick SRS", func(b *testing.B) {
 		srs, err := NewSRS(ecc.NextPowerOfTwo(benchSize), big.NewInt(-1))
 		assert.NoError(b, err)
@@ -0,0 +1,153 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package kzg
+
+import (
+	"errors"
+	"math/big"
+	"math/bits"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+)
+
+var ErrLagrangeDomainSize = errors.New("the size of the Lagrange domain should be a power of 2, at most the size of the SRS")
+
+// ProvingKeyLagrange is a ProvingKey in Lagrange basis, used to commit to and
+// open polynomials given by their evaluations on the domain of the n-th roots
+// of unity, in natural order.
+type ProvingKeyLagrange struct {
+	G1 []bn254.G1Affine // [L₀(τ)]G₁, [L₁(τ)]G₁, ... where Lᵢ(ωʲ) = δᵢⱼ
+}
+
+// NewProvingKeyLagrange returns the ProvingKey in Lagrange basis on the domain
+// of size n, from the n first points of pk, see ToLagrangeG1.
+func NewProvingKeyLagrange(pk ProvingKey, n uint64) (ProvingKeyLagrange, error) {
+	if n < 2 || bits.OnesCount64(n) != 1 || n > uint64(len(pk.G1)) {
+		return ProvingKeyLagrange{}, ErrLagrangeDomainSize
+	}
+	lagrange, err := ToLagrangeG1(pk.G1[:n])
+	if err != nil {
+		return ProvingKeyLagrange{}, err
+	}
+	return ProvingKeyLagrange{G1: lagrange}, nil
+}
+
+// CommitLagrange commits to a polynomial given by its evaluations on the
+// domain of pk, using a multi exponentiation with the Lagrange SRS.
+func CommitLagrange(evaluations []fr.Element, pk ProvingKeyLagrange, nbTasks ...int) (Digest, error) {
+	if len(evaluations) != len(pk.G1) {
+		return Digest{}, ErrInvalidPolynomialSize
+	}
+
+	var res bn254.G1Affine
+
+	config := ecc.MultiExpConfig{}
+	if len(nbTasks) > 0 {
+		config.NbTasks = nbTasks[0]
+	}
+	if _, err := res.MultiExp(pk.G1, evaluations, config); err != nil {
+		return Digest{}, err
+	}
+
+	return res, nil
+}
+
+// OpenLagrange computes an opening proof at point of the polynomial given by
+// its evaluations on the domain of pk. The quotient (p - p(point))/(X - point)
+// is computed in evaluation form, so no FFT is needed. The point may be in the
+// domain.
+func OpenLagrange(evaluations []fr.Element, point fr.Element, pk ProvingKeyLagrange) (OpeningProof, error) {
+	if len(evaluations) != len(pk.G1) || bits.OnesCount(uint(len(evaluations))) != 1 {
+		return OpeningProof{}, ErrInvalidPolynomialSize
+	}
+	n := len(evaluations)
+
+	// ωⁱ, i < n
+	omega, err := fr.Generator(uint64(n))
+	if err != nil {
+		return OpeningProof{}, err
+	}
+	domain := make([]fr.Element, n)
+	domain[0].SetOne()
+	for i := 1; i < n; i++ {
+		domain[i].Mul(&domain[i-1], &omega)
+	}
+
+	// qᵢ = 1/(ωⁱ - point), 0 if point = ωᵐ
+	q := make([]fr.Element, n)
+	for i := range q {
+		q[i].Sub(&domain[i], &point)
+	}
+	q = fr.BatchInvert(q)
+	m := -1
+	for i := range q {
+		if q[i].IsZero() {
+			m = i
+			break
+		}
+	}
+
+	res := OpeningProof{}
+	if m < 0 {
+		// barycentric formula p(point) = (pointⁿ - 1)/n ∑ pᵢωⁱ/(point - ωⁱ)
+		var t, one, nInv fr.Element
+		for i := range evaluations {
+			t.Mul(&evaluations[i], &domain[i]).Mul(&t, &q[i])
+			res.ClaimedValue.Sub(&res.ClaimedValue, &t)
+		}
+		one.SetOne()
+		nInv.SetUint64(uint64(n)).Inverse(&nInv)
+		t.Exp(point, big.NewInt(int64(n))).Sub(&t, &one).Mul(&t, &nInv)
+		res.ClaimedValue.Mul(&res.ClaimedValue, &t)
+	} else {
+		res.ClaimedValue = evaluations[m]
+	}
+
+	// qᵢ = (pᵢ - p(point))/(ωⁱ - point)
+	for i := range q {
+		if i == m {
+			continue
+		}
+		var t fr.Element
+		t.Sub(&evaluations[i], &res.ClaimedValue)
+		q[i].Mul(&q[i], &t)
+	}
+
+	// if point = ωᵐ, qₘ = p'(ωᵐ) = ∑_{i≠m} (pᵢ - pₘ)ωⁱ/(ωᵐ(ωᵐ - ωⁱ)) = -1/ωᵐ ∑_{i≠m} qᵢωⁱ
+	if m >= 0 {
+		var t fr.Element
+		for i := range q {
+			if i == m {
+				continue
+			}
+			t.Mul(&q[i], &domain[i])
+			q[m].Sub(&q[m], &t)
+		}
+		q[m].Mul(&q[m], &domain[(n-m)&(n-1)]) // 1/ωᵐ = ωⁿ⁻ᵐ
+	}
+
+	// commit to the quotient
+	h, err := CommitLagrange(q, pk)
+	if err != nil {
+		return OpeningProof{}, err
+	}
+	res.H = h
+
+	return res, nil
+}
@@ -0,0 +1,373 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package pst
+
+import (
+	"errors"
+	"hash"
+	"math/big"
+	"math/bits"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/polynomial"
+	fiatshamir "github.com/consensys/gnark-crypto/fiat-shamir"
+)
+
+var (
+	ErrInvalidNbDigests      = errors.New("number of digests is not the same as the number of polynomials")
+	ErrZeroNbDigests         = errors.New("number of digests is zero")
+	ErrInvalidPolynomialSize = errors.New("invalid polynomial size (not a power of 2 or larger than SRS)")
+	ErrInvalidPointSize      = errors.New("the number of coordinates of the point should be the number of variables of the polynomial")
+	ErrVerifyOpeningProof    = errors.New("can't verify opening proof")
+	ErrMinSRSSize            = errors.New("minimum number of variables is 1")
+	ErrMaxSRSSize            = errors.New("maximum number of variables is 64")
+)
+
+// maxNbVariables is the maximum number of variables of an SRS
+const maxNbVariables = 64
+
+// Digest commitment of a multilinear polynomial.
+type Digest = bn254.G1Affine
+
+// ProvingKey used to create or open commitments
+type ProvingKey struct {
+	// G1[k][b] = [eq(b, (τₖ₊₁, ..., τₙ))]G₁ for b in {0,1}ⁿ⁻ᵏ, indexed as in
+	// polynomial.MultiLin: G1[k] is the key for the polynomials in n-k variables
+	G1 [][]bn254.G1Affine
+}
+
+// VerifyingKey used to verify opening proofs
+type VerifyingKey struct {
+	G1  bn254.G1Affine
+	G2  bn254.G2Affine
+	Tau []bn254.G2Affine // [τᵢ]G₂
+}
+
+// SRS must be computed through MPC and comprises the ProvingKey and the VerifyingKey
+type SRS struct {
+	Pk ProvingKey
+	Vk VerifyingKey
+}
+
+// OpeningProof PST proof for opening a multilinear polynomial f at a point r.
+type OpeningProof struct {
+	// Quotients commitments to the qᵢ such that f - f(r) = ∑ᵢ (Xᵢ - rᵢ)qᵢ(Xᵢ₊₁, ..., Xₙ)
+	Quotients []bn254.G1Affine
+
+	// ClaimedValue purported value f(r)
+	ClaimedValue fr.Element
+}
+
+// BatchOpeningProof opening proof for many polynomials at the same point
+type BatchOpeningProof struct {
+	// Quotients commitments to the quotients of the folded polynomial
+	Quotients []bn254.G1Affine
+
+	// ClaimedValues purported values
+	ClaimedValues []fr.Element
+}
+
+// NewSRS returns a new SRS for polynomials in up to len(tau) variables, using
+// tau as randomness source.
+//
+// In production, a SRS generated through MPC should be used.
+func NewSRS(tau []*big.Int) (*SRS, error) {
+	nbVars := len(tau)
+	if nbVars < 1 {
+		return nil, ErrMinSRSSize
+	}
+	if nbVars > maxNbVariables {
+		return nil, ErrMaxSRSSize
+	}
+
+	var srs SRS
+	var gen1Jac bn254.G1Jac
+	gen1Jac, _, srs.Vk.G1, srs.Vk.G2 = bn254.Generators()
+
+	_tau := make([]fr.Element, nbVars)
+	srs.Vk.Tau = make([]bn254.G2Affine, nbVars)
+	for i := range tau {
+		_tau[i].SetBigInt(tau[i])
+		srs.Vk.Tau[i].ScalarMultiplication(&srs.Vk.G2, tau[i])
+	}
+
+	// [eq(b, τ)]G₁ for b in {0,1}ⁿ
+	eq := make(polynomial.MultiLin, 1<<nbVars)
+	eq[0].SetOne()
+	eq.Eq(_tau)
+	srs.Pk.G1 = make([][]bn254.G1Affine, nbVars+1)
+	srs.Pk.G1[0] = bn254.BatchScalarMultiplicationG1(&srs.Vk.G1, eq)
+
+	// eq(b, (τₖ₊₁, ..., τₙ)) = eq((0, b), (τₖ, ..., τₙ)) + eq((1, b), (τₖ, ..., τₙ))
+	buf := make([]bn254.G1Jac, 1<<(nbVars-1))
+	for k := 1; k < nbVars; k++ {
+		prev := srs.Pk.G1[k-1]
+		mid := len(prev) / 2
+		for i := 0; i < mid; i++ {
+			buf[i].FromAffine(&prev[i])
+			buf[i].AddMixed(&prev[mid+i])
+		}
+		srs.Pk.G1[k] = bn254.BatchJacobianToAffineG1(buf[:mid])
+	}
+	var g1 bn254.G1Affine
+	g1.FromJacobian(&gen1Jac)
+	srs.Pk.G1[nbVars] = []bn254.G1Affine{g1}
+
+	return &srs, nil
+}
+
+// nbVariables returns the number of variables of p, or an error if it doesn't
+// fit in a key of maxVars variables.
+func nbVariables(p polynomial.MultiLin, maxVars int) (int, error) {
+	if len(p) == 0 || bits.OnesCount(uint(len(p))) != 1 {
+		return 0, ErrInvalidPolynomialSize
+	}
+	nbVars := p.NumVars()
+	if nbVars > maxVars {
+		return 0, ErrInvalidPolynomialSize
+	}
+	return nbVars, nil
+}
+
+// Commit commits to a multilinear polynomial given by its evaluations on the
+// boolean hypercube, in at most len(pk.G1)-1 variables.
+func Commit(p polynomial.MultiLin, pk ProvingKey, nbTasks ...int) (Digest, error) {
+	nbVars, err := nbVariables(p, len(pk.G1)-1)
+	if err != nil {
+		return Digest{}, err
+	}
+	return commit(p, pk.G1[len(pk.G1)-1-nbVars], nbTasks...)
+}
+
+func commit(p []fr.Element, key []bn254.G1Affine, nbTasks ...int) (Digest, error) {
+	config := ecc.MultiExpConfig{}
+	if len(nbTasks) > 0 {
+		config.NbTasks = nbTasks[0]
+	}
+	var res bn254.G1Affine
+	if _, err := res.MultiExp(key, p, config); err != nil {
+		return Digest{}, err
+	}
+	return res, nil
+}
+
+// Open computes an opening proof of p at point.
+//
+// The quotients are obtained by folding p one variable at a time: writing
+// f(Xᵢ, ...) = f(rᵢ, ...) + (Xᵢ - rᵢ)(f(1, ...) - f(0, ...)), the i-th quotient
+// is f(1, ...) - f(0, ...) and f is then folded at rᵢ.
+func Open(p polynomial.MultiLin, point []fr.Element, pk ProvingKey) (OpeningProof, error) {
+	nbVars, err := nbVariables(p, len(pk.G1)-1)
+	if err != nil {
+		return OpeningProof{}, err
+	}
+	if len(point) != nbVars {
+		return OpeningProof{}, ErrInvalidPointSize
+	}
+
+	var res OpeningProof
+	res.Quotients = make([]bn254.G1Affine, nbVars)
+	offset := len(pk.G1) - nbVars
+	f := p.Clone()
+	q := make([]fr.Element, len(p)/2)
+	for i := range point {
+		mid := len(f) / 2
+		for j := 0; j < mid; j++ {
+			q[j].Sub(&f[mid+j], &f[j])
+		}
+		if res.Quotients[i], err = commit(q[:mid], pk.G1[offset+i]); err != nil {
+			return OpeningProof{}, err
+		}
+		f.Fold(point[i])
+	}
+	res.ClaimedValue = f[0]
+
+	return res, nil
+}
+
+// Verify verifies a PST opening proof of a polynomial in len(point) variables
+//
+// e(C - [f(r)]G₁ + ∑ᵢ[rᵢ]Qᵢ, G₂) = ∏ᵢ e(Qᵢ, [τᵢ]G₂)
+func Verify(commitment *Digest, proof *OpeningProof, point []fr.Element, vk VerifyingKey) error {
+	nbVars := len(point)
+	if len(proof.Quotients) != nbVars || nbVars > len(vk.Tau) {
+		return ErrInvalidPointSize
+	}
+
+	// C - [f(r)]G₁ + ∑ᵢ[rᵢ]Qᵢ
+	var left, acc bn254.G1Jac
+	if nbVars > 0 {
+		if _, err := acc.MultiExp(proof.Quotients, point, ecc.MultiExpConfig{}); err != nil {
+			return err
+		}
+	}
+	var claimedValueBigInt big.Int
+	proof.ClaimedValue.BigInt(&claimedValueBigInt)
+	left.FromAffine(&vk.G1)
+	left.ScalarMultiplication(&left, &claimedValueBigInt)
+	acc.SubAssign(&left)
+	acc.AddMixed(commitment)
+
+	// e(C - [f(r)]G₁ + ∑ᵢ[rᵢ]Qᵢ, G₂) ∏ᵢ e(-Qᵢ, [τᵢ]G₂) == 1
+	p := make([]bn254.G1Affine, nbVars+1)
+	q := make([]bn254.G2Affine, nbVars+1)
+	p[0].FromJacobian(&acc)
+	q[0] = vk.G2
+	copy(q[1:], vk.Tau[len(vk.Tau)-nbVars:])
+	for i := range proof.Quotients {
+		p[i+1].Neg(&proof.Quotients[i])
+	}
+	check, err := bn254.PairingCheck(p, q)
+	if err != nil {
+		return err
+	}
+	if !check {
+		return ErrVerifyOpeningProof
+	}
+	return nil
+}
+
+// BatchOpen opens the polynomials at the same point, the polynomials must have
+// the same number of variables.
+//
+// * hf is the hash function used to derive the folding challenge
+// * dataTranscript extra data that might be needed to derive the challenge used for folding
+func BatchOpen(polynomials []polynomial.MultiLin, digests []Digest, point []fr.Element, hf hash.Hash, pk ProvingKey, dataTranscript ...[]byte) (BatchOpeningProof, error) {
+	nbDigests := len(digests)
+	if nbDigests != len(polynomials) {
+		return BatchOpeningProof{}, ErrInvalidNbDigests
+	}
+	if nbDigests == 0 {
+		return BatchOpeningProof{}, ErrZeroNbDigests
+	}
+	for i := range polynomials {
+		if len(polynomials[i]) != len(polynomials[0]) {
+			return BatchOpeningProof{}, ErrInvalidPolynomialSize
+		}
+	}
+	if len(polynomials[0]) != 1<<len(point) {
+		return BatchOpeningProof{}, ErrInvalidPointSize
+	}
+
+	var res BatchOpeningProof
+	res.ClaimedValues = make([]fr.Element, nbDigests)
+	for i := range polynomials {
+		res.ClaimedValues[i] = polynomials[i].Evaluate(point, nil)
+	}
+
+	gamma, err := deriveGamma(point, digests, res.ClaimedValues, hf, dataTranscript...)
+	if err != nil {
+		return BatchOpeningProof{}, err
+	}
+
+	// ∑ᵢ γⁱfᵢ
+	folded := polynomials[0].Clone()
+	var gammaI, t fr.Element
+	gammaI.Set(&gamma)
+	for i := 1; i < nbDigests; i++ {
+		for j := range folded {
+			t.Mul(&polynomials[i][j], &gammaI)
+			folded[j].Add(&folded[j], &t)
+		}
+		gammaI.Mul(&gammaI, &gamma)
+	}
+
+	proof, err := Open(folded, point, pk)
+	if err != nil {
+		return BatchOpeningProof{}, err
+	}
+	res.Quotients = proof.Quotients
+
+	return res, nil
+}
+
+// BatchVerify verifies a batch opening proof of polynomials at point.
+//
+// * hf is the hash function used to derive the folding challenge
+// * dataTranscript extra data that might be needed to derive the challenge used for folding
+func BatchVerify(digests []Digest, batchOpeningProof *BatchOpeningProof, point []fr.Element, hf hash.Hash, vk VerifyingKey, dataTranscript ...[]byte) error {
+	nbDigests := len(digests)
+	if nbDigests != len(batchOpeningProof.ClaimedValues) {
+		return ErrInvalidNbDigests
+	}
+	if nbDigests == 0 {
+		return ErrZeroNbDigests
+	}
+
+	gamma, err := deriveGamma(point, digests, batchOpeningProof.ClaimedValues, hf, dataTranscript...)
+	if err != nil {
+		return err
+	}
+
+	// ∑ᵢ γⁱCᵢ and ∑ᵢ γⁱfᵢ(r)
+	gammas := make([]fr.Element, nbDigests)
+	gammas[0].SetOne()
+	for i := 1; i < nbDigests; i++ {
+		gammas[i].Mul(&gammas[i-1], &gamma)
+	}
+	var foldedDigest Digest
+	if _, err := foldedDigest.MultiExp(digests, gammas, ecc.MultiExpConfig{}); err != nil {
+		return err
+	}
+	proof := OpeningProof{Quotients: batchOpeningProof.Quotients}
+	var t fr.Element
+	for i := range gammas {
+		t.Mul(&gammas[i], &batchOpeningProof.ClaimedValues[i])
+		proof.ClaimedValue.Add(&proof.ClaimedValue, &t)
+	}
+
+	return Verify(&foldedDigest, &proof, point, vk)
+}
+
+// deriveGamma derives a challenge using Fiat Shamir to fold the polynomials.
+func deriveGamma(point []fr.Element, digests []Digest, claimedValues []fr.Element, hf hash.Hash, dataTranscript ...[]byte) (fr.Element, error) {
+
+	// derive the challenge gamma, binded to the point and the commitments
+	fs := fiatshamir.NewTranscript(hf, "gamma")
+	for i := range point {
+		if err := fs.Bind("gamma", point[i].Marshal()); err != nil {
+			return fr.Element{}, err
+		}
+	}
+	for i := range digests {
+		if err := fs.Bind("gamma", digests[i].Marshal()); err != nil {
+			return fr.Element{}, err
+		}
+	}
+	for i := range claimedValues {
+		if err := fs.Bind("gamma", claimedValues[i].Marshal()); err != nil {
+			return fr.Element{}, err
+		}
+	}
+
+	for i := 0; i < len(dataTranscript); i++ {
+		if err := fs.Bind("gamma", dataTranscript[i]); err != nil {
+			return fr.Element{}, err
+		}
+	}
+
+	gammaByte, err := fs.ComputeChallenge("gamma")
+	if err != nil {
+		return fr.Element{}, err
+	}
+	var gamma fr.Element
+	gamma.SetBytes(gammaByte)
+
+	return gamma, nil
+}
@@ -0,0 +1,167 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package eddsa
+
+import (
+	"crypto/rand"
+	"errors"
+	"hash"
+	"math/big"
+	"sort"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/twistededwards"
+)
+
+var errBatchSize = errors.New("the numbers of public keys, signatures and messages differ")
+
+// batchEntry is a well-formed signature of a batch, with its negated
+// commitment R, negated public key A, response S and challenge H(R, A, M).
+type batchEntry struct {
+	index   int
+	negR    twistededwards.PointAffine
+	negA    twistededwards.PointAffine
+	s, hram big.Int
+}
+
+// BatchVerify verifies the signatures sigs of the messages msgs under the
+// public keys publicKeys, and returns the sorted indices of the invalid
+// signatures, nil if they are all valid.
+//
+// For random 128 bits scalars zᵢ, it checks with one multi-scalar
+// multiplication that
+//
+//	cofactor*((∑ zᵢSᵢ)*Base - ∑ zᵢRᵢ - ∑ (zᵢH(Rᵢ,Aᵢ,Mᵢ))*Aᵢ) = 0
+//
+// If the check fails, the batch is split in halves recursively to locate the
+// invalid signatures. A signature is valid in the batch if and only if Verify
+// accepts it, except with probability 2⁻¹²⁸.
+func BatchVerify(publicKeys []PublicKey, sigs, msgs [][]byte, hFunc hash.Hash) ([]int, error) {
+
+	// hFunc cannot be nil.
+	// We need a hash function for the Fiat-Shamir.
+	if hFunc == nil {
+		return nil, errHashNeeded
+	}
+	if len(sigs) != len(publicKeys) || len(msgs) != len(publicKeys) {
+		return nil, errBatchSize
+	}
+
+	curveParams := twistededwards.GetEdwardsCurve()
+
+	var invalid []int
+	entries := make([]batchEntry, 0, len(publicKeys))
+	for i := range publicKeys {
+		// malformed signatures and public keys are invalid
+		var sig Signature
+		if !publicKeys[i].A.IsOnCurve() {
+			invalid = append(invalid, i)
+			continue
+		}
+		if _, err := sig.SetBytes(sigs[i]); err != nil {
+			invalid = append(invalid, i)
+			continue
+		}
+
+		hramInt, err := computeHRAM(hFunc, &sig.R, &publicKeys[i].A, msgs[i])
+		if err != nil {
+			return nil, err
+		}
+
+		var e batchEntry
+		e.index = i
+		e.negR.Neg(&sig.R)
+		e.negA.Neg(&publicKeys[i].A)
+		e.s.SetBytes(sig.S[:])
+		e.hram.Mod(hramInt, &curveParams.Order)
+		entries = append(entries, e)
+	}
+
+	var err error
+	if invalid, err = locateInvalid(entries, invalid, &curveParams); err != nil {
+		return nil, err
+	}
+	if len(invalid) == 0 {
+		return nil, nil
+	}
+	sort.Ints(invalid)
+	return invalid, nil
+}
+
+// locateInvalid appends to invalid the indices of the invalid signatures of
+// entries, splitting them in halves while their batch check fails.
+func locateInvalid(entries []batchEntry, invalid []int, curveParams *twistededwards.CurveParams) ([]int, error) {
+	if len(entries) == 0 {
+		return invalid, nil
+	}
+	ok, err := batchCheck(entries, curveParams)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		return invalid, nil
+	}
+	if len(entries) == 1 {
+		return append(invalid, entries[0].index), nil
+	}
+	m := len(entries) / 2
+	if invalid, err = locateInvalid(entries[:m], invalid, curveParams); err != nil {
+		return nil, err
+	}
+	return locateInvalid(entries[m:], invalid, curveParams)
+}
+
+// batchCheck checks a random linear combination of the verification equations
+// of entries.
+func batchCheck(entries []batchEntry, curveParams *twistededwards.CurveParams) (bool, error) {
+	n := len(entries)
+	points := make([]twistededwards.PointAffine, 2*n+1)
+	scalars := make([]big.Int, 2*n+1)
+
+	var sumS big.Int
+	var buf [16]byte
+	for i := range entries {
+		if _, err := rand.Read(buf[:]); err != nil {
+			return false, err
+		}
+		z := &scalars[2*i]
+		z.SetBytes(buf[:])
+		if z.Sign() == 0 {
+			z.SetUint64(1)
+		}
+		points[2*i].Set(&entries[i].negR)
+
+		scalars[2*i+1].Mul(z, &entries[i].hram).Mod(&scalars[2*i+1], &curveParams.Order)
+		points[2*i+1].Set(&entries[i].negA)
+
+		var zs big.Int
+		sumS.Add(&sumS, zs.Mul(z, &entries[i].s))
+	}
+	scalars[2*n].Mod(&sumS, &curveParams.Order)
+	points[2*n].Set(&curveParams.Base)
+
+	var res twistededwards.PointExtended
+	if _, err := res.MultiExp(points, scalars, ecc.MultiExpConfig{}); err != nil {
+		return false, err
+	}
+
+	var bCofactor big.Int
+	curveParams.Cofactor.BigInt(&bCofactor)
+	res.ScalarMultiplication(&res, &bCofactor)
+
+	return res.IsZero(), nil
+}
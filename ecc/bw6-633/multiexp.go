@@ -83,27 +83,7 @@ func (p *G1Jac) MultiExp(points []G1Affine, scalars []fr.Element, config ecc.Mul
 
 	// here, we compute the best C for nbPoints
 	// we split recursively until nbChunks(c) >= nbTasks,
-	bestC := func(nbPoints int) uint64 {
-		// implemented msmC methods (the c we use must be in this slice)
-		implementedCs := []uint64{4, 5, 6, 8, 12, 16}
-		var C uint64
-		// approximate cost (in group operations)
-		// cost = bits/c * (nbPoints + 2^{c})
-		// this needs to be verified empirically.
-		// for example, on a MBP 2016, for G2 MultiExp > 8M points, hand picking c gives better results
-		min := math.MaxFloat64
-		for _, c := range implementedCs {
-			cc := (fr.Bits + 1) * (nbPoints + (1 << c))
-			cost := float64(cc) / float64(c)
-			if cost < min {
-				min = cost
-				C = c
-			}
-		}
-		return C
-	}
-
-	C := bestC(nbPoints)
+	C := bestCG1(nbPoints)
 	nbChunks := int(computeNbChunks(C))
 
 	// should we recursively split the msm in half? (see below)
@@ -131,7 +111,7 @@ func (p *G1Jac) MultiExp(points []G1Affine, scalars []fr.Element, config ecc.Mul
 
 	costPreSplit := costFunction(nbChunks, config.NbTasks, costPerTask(C, nbPoints))
 
-	cPostSplit := bestC(nbPoints / 2)
+	cPostSplit := bestCG1(nbPoints / 2)
 	nbChunksPostSplit := int(computeNbChunks(cPostSplit))
 	costPostSplit := costFunction(nbChunksPostSplit*2, config.NbTasks, costPerTask(cPostSplit, nbPoints/2))
 
@@ -156,6 +136,28 @@ func (p *G1Jac) MultiExp(points []G1Affine, scalars []fr.Element, config ecc.Mul
 	return p, nil
 }
 
+// bestCG1 returns the window size which minimizes the approximate
+// cost of a multi-exponentiation of nbPoints points
+func bestCG1(nbPoints int) uint64 {
+	// implemented msmC methods (the c we use must be in this slice)
+	implementedCs := []uint64{4, 5, 6, 8, 12, 16}
+	var C uint64
+	// approximate cost (in group operations)
+	// cost = bits/c * (nbPoints + 2^{c})
+	// this needs to be verified empirically.
+	// for example, on a MBP 2016, for G2 MultiExp > 8M points, hand picking c gives better results
+	min := math.MaxFloat64
+	for _, c := range implementedCs {
+		cc := (fr.Bits + 1) * (nbPoints + (1 << c))
+		cost := float64(cc) / float64(c)
+		if cost < min {
+			min = cost
+			C = c
+		}
+	}
+	return C
+}
+
 func _innerMsmG1(p *G1Jac, c uint64, points []G1Affine, scalars []fr.Element, config ecc.MultiExpConfig) *G1Jac {
 	// partition the scalars
 	digits, chunkStats := partitionScalars(scalars, c, config.NbTasks)
@@ -297,6 +299,77 @@ func (p *G1Jac) Fold(points []G1Affine, combinationCoeff fr.Element, config ecc.
 	return p.MultiExp(points, scalars, config)
 }
 
+// MultiExpBatchG1 computes the multi-exponentiations ∑ⱼ[scalars[i][j]]points[j]
+// of several vectors of scalars with the same points, in affine coordinates. A
+// vector may be shorter than points, in which case only the first points are used.
+//
+// The chunks of all the multi-exponentiations are scheduled together, on at most
+// config.NbTasks go routines, so that the tasks running at the same time read the
+// same points. This call return an error if a vector of scalars is longer than
+// points or if provided config is invalid.
+func MultiExpBatchG1(points []G1Affine, scalars [][]fr.Element, config ecc.MultiExpConfig) ([]G1Affine, error) {
+	nbPoints := 0
+	for i := range scalars {
+		if len(scalars[i]) > len(points) {
+			return nil, errors.New("len(scalars[i]) > len(points)")
+		}
+		if len(scalars[i]) > nbPoints {
+			nbPoints = len(scalars[i])
+		}
+	}
+
+	// if nbTasks is not set, use all available CPUs
+	if config.NbTasks <= 0 {
+		config.NbTasks = runtime.NumCPU()
+	} else if config.NbTasks > 1024 {
+		return nil, errors.New("invalid config: config.NbTasks > 1024")
+	}
+
+	// the same window size for all the multi-exponentiations
+	c := bestCG1(nbPoints)
+	nbChunks := int(computeNbChunks(c))
+
+	// the go routines of all the chunks share the same semaphore
+	sem := make(chan struct{}, config.NbTasks)
+	for i := 0; i < config.NbTasks; i++ {
+		sem <- struct{}{}
+	}
+	defer close(sem)
+
+	// chChunks[i][j] receives the weighted bucket sum of the chunk j of the i-th
+	// multi-exponentiation
+	chChunks := make([][]chan g1JacExtended, len(scalars))
+	for i := range chChunks {
+		chChunks[i] = make([]chan g1JacExtended, nbChunks)
+		for j := range chChunks[i] {
+			chChunks[i][j] = make(chan g1JacExtended, 1)
+		}
+	}
+
+	// partition the scalars, and spawn the go routines chunk by chunk so that the
+	// tasks acquiring the semaphore at the same time process the same points
+	digits := make([][]uint16, len(scalars))
+	chunkStats := make([][]chunkStat, len(scalars))
+	for i := range scalars {
+		digits[i], chunkStats[i] = partitionScalars(scalars[i], c, config.NbTasks)
+	}
+	for j := nbChunks - 1; j >= 0; j-- {
+		for i := range scalars {
+			processChunk := getChunkProcessorG1(c, chunkStats[i][j])
+			if j == nbChunks-1 {
+				processChunk = getChunkProcessorG1(lastC(c), chunkStats[i][j])
+			}
+			n := len(scalars[i])
+			go processChunk(uint64(j), chChunks[i][j], c, points[:n], digits[i][j*n:(j+1)*n], sem)
+		}
+	}
+	res := make([]G1Jac, len(scalars))
+	for i := range res {
+		msmReduceChunkG1Affine(&res[i], int(c), chChunks[i])
+	}
+	return BatchJacobianToAffineG1(res), nil
+}
+
 // MultiExp implements section 4 of https://eprint.iacr.org/2012/549.pdf
 //
 // This call return an error if len(scalars) != len(points) or if provided config is invalid.
@@ -355,27 +428,7 @@ func (p *G2Jac) MultiExp(points []G2Affine, scalars []fr.Element, config ecc.Mul
 
 	// here, we compute the best C for nbPoints
 	// we split recursively until nbChunks(c) >= nbTasks,
-	bestC := func(nbPoints int) uint64 {
-		// implemented msmC methods (the c we use must be in this slice)
-		implementedCs := []uint64{4, 5, 6, 8, 12, 16}
-		var C uint64
-		// approximate cost (in group operations)
-		// cost = bits/c * (nbPoints + 2^{c})
-		// this needs to be verified empirically.
-		// for example, on a MBP 2016, for G2 MultiExp > 8M points, hand picking c gives better results
-		min := math.MaxFloat64
-		for _, c := range implementedCs {
-			cc := (fr.Bits + 1) * (nbPoints + (1 << c))
-			cost := float64(cc) / float64(c)
-			if cost < min {
-				min = cost
-				C = c
-			}
-		}
-		return C
-	}
-
-	C := bestC(nbPoints)
+	C := bestCG2(nbPoints)
 	nbChunks := int(computeNbChunks(C))
 
 	// should we recursively split the msm in half? (see below)
@@ -403,7 +456,7 @@ func (p *G2Jac) MultiExp(points []G2Affine, scalars []fr.Element, config ecc.Mul
 
 	costPreSplit := costFunction(nbChunks, config.NbTasks, costPerTask(C, nbPoints))
 
-	cPostSplit := bestC(nbPoints / 2)
+	cPostSplit := bestCG2(nbPoints / 2)
 	nbChunksPostSplit := int(computeNbChunks(cPostSplit))
 	costPostSplit := costFunction(nbChunksPostSplit*2, config.NbTasks, costPerTask(cPostSplit, nbPoints/2))
 
@@ -428,6 +481,28 @@ func (p *G2Jac) MultiExp(points []G2Affine, scalars []fr.Element, config ecc.Mul
 	return p, nil
 }
 
+// bestCG2 returns the window size which minimizes the approximate
+// cost of a multi-exponentiation of nbPoints points
+func bestCG2(nbPoints int) uint64 {
+	// implemented msmC methods (the c we use must be in this slice)
+	implementedCs := []uint64{4, 5, 6, 8, 12, 16}
+	var C uint64
+	// approximate cost (in group operations)
+	// cost = bits/c * (nbPoints + 2^{c})
+	// this needs to be verified empirically.
+	// for example, on a MBP 2016, for G2 MultiExp > 8M points, hand picking c gives better results
+	min := math.MaxFloat64
+	for _, c := range implementedCs {
+		cc := (fr.Bits + 1) * (nbPoints + (1 << c))
+		cost := float64(cc) / float64(c)
+		if cost < min {
+			min = cost
+			C = c
+		}
+	}
+	return C
+}
+
 func _innerMsmG2(p *G2Jac, c uint64, points []G2Affine, scalars []fr.Element, config ecc.MultiExpConfig) *G2Jac {
 	// partition the scalars
 	digits, chunkStats := partitionScalars(scalars, c, config.NbTasks)
@@ -569,6 +644,79 @@ func (p *G2Jac) Fold(points []G2Affine, combinationCoeff fr.Element, config ecc.
 	return p.MultiExp(points, scalars, config)
 }
 
+// MultiExpBatchG2 computes the multi-exponentiations ∑ⱼ[scalars[i][j]]points[j]
+// of several vectors of scalars with the same points, in affine coordinates. A
+// vector may be shorter than points, in which case only the first points are used.
+//
+// The chunks of all the multi-exponentiations are scheduled together, on at most
+// config.NbTasks go routines, so that the tasks running at the same time read the
+// same points. This call return an error if a vector of scalars is longer than
+// points or if provided config is invalid.
+func MultiExpBatchG2(points []G2Affine, scalars [][]fr.Element, config ecc.MultiExpConfig) ([]G2Affine, error) {
+	nbPoints := 0
+	for i := range scalars {
+		if len(scalars[i]) > len(points) {
+			return nil, errors.New("len(scalars[i]) > len(points)")
+		}
+		if len(scalars[i]) > nbPoints {
+			nbPoints = len(scalars[i])
+		}
+	}
+
+	// if nbTasks is not set, use all available CPUs
+	if config.NbTasks <= 0 {
+		config.NbTasks = runtime.NumCPU()
+	} else if config.NbTasks > 1024 {
+		return nil, errors.New("invalid config: config.NbTasks > 1024")
+	}
+
+	// the same window size for all the multi-exponentiations
+	c := bestCG2(nbPoints)
+	nbChunks := int(computeNbChunks(c))
+
+	// the go routines of all the chunks share the same semaphore
+	sem := make(chan struct{}, config.NbTasks)
+	for i := 0; i < config.NbTasks; i++ {
+		sem <- struct{}{}
+	}
+	defer close(sem)
+
+	// chChunks[i][j] receives the weighted bucket sum of the chunk j of the i-th
+	// multi-exponentiation
+	chChunks := make([][]chan g2JacExtended, len(scalars))
+	for i := range chChunks {
+		chChunks[i] = make([]chan g2JacExtended, nbChunks)
+		for j := range chChunks[i] {
+			chChunks[i][j] = make(chan g2JacExtended, 1)
+		}
+	}
+
+	// partition the scalars, and spawn the go routines chunk by chunk so that the
+	// tasks acquiring the semaphore at the same time process the same points
+	digits := make([][]uint16, len(scalars))
+	chunkStats := make([][]chunkStat, len(scalars))
+	for i := range scalars {
+		digits[i], chunkStats[i] = partitionScalars(scalars[i], c, config.NbTasks)
+	}
+	for j := nbChunks - 1; j >= 0; j-- {
+		for i := range scalars {
+			processChunk := getChunkProcessorG2(c, chunkStats[i][j])
+			if j == nbChunks-1 {
+				processChunk = getChunkProcessorG2(lastC(c), chunkStats[i][j])
+			}
+			n := len(scalars[i])
+			go processChunk(uint64(j), chChunks[i][j], c, points[:n], digits[i][j*n:(j+1)*n], sem)
+		}
+	}
+	res := make([]G2Affine, len(scalars))
+	var p G2Jac
+	for i := range res {
+		msmReduceChunkG2Affine(&p, int(c), chChunks[i])
+		res[i].FromJacobian(&p)
+	}
+	return res, nil
+}
+
 // selector stores the index, mask and shifts needed to select bits from a scalar
 // it is used during the multiExp algorithm or the batch scalar multiplication
 type selector struct {
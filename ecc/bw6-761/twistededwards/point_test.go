@@ -424,6 +424,19 @@ func TestOps(t *testing.T) {
 		genS1,
 	))
 
+	properties.Property("(affine) [a]O = O", prop.ForAll(
+		func(s1 big.Int) bool {
+
+			var p, inf PointAffine
+			inf.X.SetZero()
+			inf.Y.SetOne()
+			p.ScalarMultiplication(&inf, &s1)
+
+			return p.IsOnCurve() && p.Equal(&inf)
+		},
+		genS1,
+	))
+
 	properties.Property("(affine) [5]P=[2][2]P+P", prop.ForAll(
 		func(s1 big.Int) bool {
 
@@ -21,8 +21,12 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 //
+// SignEthereum, VerifyEthereum and Ecrecover implement the Ethereum flavour:
+// 65 bytes signatures r||s||v, low s (EIP-2) and Keccak-256 addresses.
+//
 // Documentation:
 // - Wikipedia: https://en.wikipedia.org/wiki/Elliptic_Curve_Digital_Signature_Algorithm
 // - FIPS 186-4: https://nvlpubs.nist.gov/nistpubs/FIPS/NIST.FIPS.186-4.pdf
 // - SEC 1, v-2: https://www.secg.org/sec1-v2.pdf
+// - EIP-2: https://eips.ethereum.org/EIPS/eip-2
 package ecdsa
@@ -19,6 +19,7 @@ package ecdsa
 import (
 	"crypto/rand"
 	"crypto/sha256"
+	"github.com/consensys/gnark-crypto/ecc/secp256k1"
 	"github.com/consensys/gnark-crypto/ecc/secp256k1/fr"
 	"math/big"
 	"testing"
@@ -90,6 +91,56 @@ func TestRecoverPublicKey(t *testing.T) {
 	properties.TestingRun(t, gopter.ConsoleReporter(false))
 }
 
+func TestSignForRecoverRetry(t *testing.T) {
+	// the first nonce gives s = 0, the recovery id must be the one of the
+	// second nonce, of opposite y parity
+	msg := []byte("test")
+	m := HashToInt(msg)
+
+	nonceWithParity := func(parity uint) *big.Int {
+		var P secp256k1.G1Affine
+		for k := big.NewInt(1); ; k.Add(k, big.NewInt(1)) {
+			P.ScalarMultiplicationBase(k)
+			if P.Y.BigInt(new(big.Int)).Bit(0) == parity {
+				return k
+			}
+		}
+	}
+	nonces := []*big.Int{nonceWithParity(1), nonceWithParity(0)}
+
+	// sk = -m/r₁ so that s₁ = k₁⁻¹ . (m + sk ⋅ r₁) = 0
+	var P secp256k1.G1Affine
+	P.ScalarMultiplicationBase(nonces[0])
+	r1 := P.X.BigInt(new(big.Int))
+	r1.Mod(r1, order)
+	scalar := new(big.Int).ModInverse(r1, order)
+	scalar.Mul(scalar, m).Neg(scalar).Mod(scalar, order)
+
+	v, r, s, err := signForRecover(m, scalar, func() (*big.Int, error) {
+		k := nonces[0]
+		nonces = nonces[1:]
+		return k, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(nonces) != 0 {
+		t.Fatal("expected a retry")
+	}
+	if v&1 != 0 {
+		t.Fatalf("expected an even y, got the recovery id %d", v)
+	}
+
+	var pk, recovered PublicKey
+	pk.A.ScalarMultiplicationBase(scalar)
+	if err = recovered.RecoverFrom(msg, v, r, s); err != nil {
+		t.Fatal(err)
+	}
+	if !pk.Equal(&recovered) {
+		t.Fatal("recovered the wrong public key")
+	}
+}
+
 func TestNonMalleability(t *testing.T) {
 
 	// buffer too big
@@ -0,0 +1,183 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package ecdsa
+
+import (
+	"errors"
+	"math/big"
+
+	"golang.org/x/crypto/sha3"
+)
+
+const (
+	sizeHash              = 32
+	sizeAddress           = 20
+	sizeEthereumSignature = sizeSignature + 1
+)
+
+var (
+	// ErrHighS is returned when the s component of a signature is larger than
+	// half the order of the curve, which EIP-2 forbids.
+	ErrHighS = errors.New("s is larger than half the order")
+	// ErrInvalidRecoveryID is returned when the recovery id v of a signature is
+	// not one of 0, 1, 27 or 28.
+	ErrInvalidRecoveryID = errors.New("invalid recovery id")
+	// ErrInvalidHashLength is returned when the signed hash is not 32 bytes long.
+	ErrInvalidHashLength = errors.New("hash must be 32 bytes long")
+	// ErrRecoveredInfinity is returned when the recovered public key is the
+	// point at infinity.
+	ErrRecoveredInfinity = errors.New("recovered public key is the point at infinity")
+)
+
+var errROverflow = errors.New("x coordinate of R is larger than the order")
+
+var halfOrder = new(big.Int).Rsh(order, 1)
+
+// IsLowS reports whether s ≤ n/2, as required by EIP-2 for the signatures of
+// Ethereum transactions.
+func (sig *Signature) IsLowS() bool {
+	s := new(big.Int).SetBytes(sig.S[:sizeFr])
+	return s.Cmp(halfOrder) <= 0
+}
+
+// NormalizeS replaces s by n-s when s > n/2 and reports whether it did.
+//
+// (r, s) and (r, n-s) are both valid signatures of the same message, for the
+// opposite points ±R, so the recovery id must be flipped along with s.
+func (sig *Signature) NormalizeS() bool {
+	s := new(big.Int).SetBytes(sig.S[:sizeFr])
+	if s.Cmp(halfOrder) <= 0 {
+		return false
+	}
+	s.Sub(order, s).FillBytes(sig.S[:sizeFr])
+	return true
+}
+
+// SignEthereum signs a 32 bytes hash (typically Keccak-256 of the message) the
+// way Ethereum does, and returns the 65 bytes signature r||s||v where s ≤ n/2
+// and v ∈ {0, 1} is the parity of the y coordinate of R.
+//
+// With WithDeterministicNonce(sha256.New), the nonces are the ones of
+// libsecp256k1, used by go-ethereum, and so are the signatures.
+func (privKey *PrivateKey) SignEthereum(hash []byte, opts ...SignOption) ([]byte, error) {
+	if len(hash) != sizeHash {
+		return nil, ErrInvalidHashLength
+	}
+	for {
+		v, r, s, err := privKey.SignForRecover(hash, nil, opts...)
+		if err != nil {
+			return nil, err
+		}
+		if v > 1 {
+			// x_R ≥ n cannot be encoded in an Ethereum signature. This happens
+			// with probability ~2⁻¹²⁸, sign again with a new random nonce.
+			if signOptions(opts...).newHash != nil {
+				return nil, errROverflow
+			}
+			continue
+		}
+		var sig Signature
+		r.FillBytes(sig.R[:sizeFr])
+		s.FillBytes(sig.S[:sizeFr])
+		if sig.NormalizeS() {
+			v ^= 1
+		}
+		res := make([]byte, sizeEthereumSignature)
+		copy(res, sig.Bytes())
+		res[sizeSignature] = byte(v)
+		return res, nil
+	}
+}
+
+// VerifyEthereum validates the Ethereum signature sigBin of a 32 bytes hash.
+// sigBin is either r||s or r||s||v, the recovery id v is ignored. Unlike
+// Verify, it rejects the signatures with s > n/2.
+func (publicKey *PublicKey) VerifyEthereum(sigBin, hash []byte) (bool, error) {
+	if len(hash) != sizeHash {
+		return false, ErrInvalidHashLength
+	}
+	if len(sigBin) != sizeSignature && len(sigBin) != sizeEthereumSignature {
+		return false, errWrongSize
+	}
+	var sig Signature
+	if _, err := sig.SetBytes(sigBin[:sizeSignature]); err != nil {
+		return false, err
+	}
+	if !sig.IsLowS() {
+		return false, ErrHighS
+	}
+	return publicKey.Verify(sigBin[:sizeSignature], hash, nil)
+}
+
+// RecoverFromEthereum recovers the public key from a 32 bytes hash and its 65
+// bytes signature r||s||v, where v ∈ {0, 1, 27, 28}. If recovery succeeded,
+// the method sets the current public key to the recovered value. Otherwise it
+// returns an error and leaves the current public key unchanged.
+//
+// As the ecrecover precompile, it accepts the signatures with s > n/2.
+func (pk *PublicKey) RecoverFromEthereum(hash, sigBin []byte) error {
+	if len(hash) != sizeHash {
+		return ErrInvalidHashLength
+	}
+	if len(sigBin) != sizeEthereumSignature {
+		return errWrongSize
+	}
+	v := uint(sigBin[sizeSignature])
+	if v >= 27 {
+		v -= 27
+	}
+	if v > 1 {
+		return ErrInvalidRecoveryID
+	}
+	var sig Signature
+	if _, err := sig.SetBytes(sigBin[:sizeSignature]); err != nil {
+		return err
+	}
+	r := new(big.Int).SetBytes(sig.R[:sizeFr])
+	s := new(big.Int).SetBytes(sig.S[:sizeFr])
+
+	var recovered PublicKey
+	if err := recovered.RecoverFrom(hash, v, r, s); err != nil {
+		return err
+	}
+	if recovered.A.IsInfinity() {
+		return ErrRecoveredInfinity
+	}
+	pk.A.Set(&recovered.A)
+	return nil
+}
+
+// EthereumAddress returns the Ethereum address of the public key, the last 20
+// bytes of the Keccak-256 hash of x||y.
+func (pk *PublicKey) EthereumAddress() (address [sizeAddress]byte) {
+	xy := pk.A.RawBytes()
+	h := sha3.NewLegacyKeccak256()
+	h.Write(xy[:])
+	copy(address[:], h.Sum(nil)[sizeHash-sizeAddress:])
+	return
+}
+
+// Ecrecover returns the Ethereum address of the signer of a 32 bytes hash,
+// from its 65 bytes signature r||s||v where v ∈ {0, 1, 27, 28}. It follows the
+// ecrecover precompile, and as such accepts the signatures with s > n/2.
+func Ecrecover(hash, sigBin []byte) ([sizeAddress]byte, error) {
+	var pk PublicKey
+	if err := pk.RecoverFromEthereum(hash, sigBin); err != nil {
+		return [sizeAddress]byte{}, err
+	}
+	return pk.EthereumAddress(), nil
+}
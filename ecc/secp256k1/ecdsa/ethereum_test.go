@@ -0,0 +1,176 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package ecdsa
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/secp256k1"
+	"github.com/leanovate/gopter"
+	"github.com/leanovate/gopter/prop"
+	"golang.org/x/crypto/sha3"
+)
+
+func keccak256(data []byte) []byte {
+	h := sha3.NewLegacyKeccak256()
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+func privateKeyFromHex(t *testing.T, s string) *PrivateKey {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var privKey PrivateKey
+	copy(privKey.scalar[sizeFr-len(b):], b)
+	_, g := secp256k1.Generators()
+	privKey.PublicKey.A.ScalarMultiplication(&g, new(big.Int).SetBytes(b))
+	return &privKey
+}
+
+func TestEthereum(t *testing.T) {
+	t.Parallel()
+	parameters := gopter.DefaultTestParameters()
+	properties := gopter.NewProperties(parameters)
+
+	properties.Property("[SECP256K1] test the Ethereum signing, verification and recovery", prop.ForAll(
+		func() bool {
+			privKey, _ := GenerateKey(rand.Reader)
+			publicKey := privKey.PublicKey
+
+			hash := keccak256([]byte("testing Ethereum"))
+			sig, err := privKey.SignEthereum(hash)
+			if err != nil {
+				return false
+			}
+
+			var s Signature
+			s.SetBytes(sig[:sizeSignature])
+			if !s.IsLowS() || sig[sizeSignature] > 1 {
+				return false
+			}
+			if flag, err := publicKey.VerifyEthereum(sig, hash); !flag || err != nil {
+				return false
+			}
+
+			address, err := Ecrecover(hash, sig)
+			if err != nil || address != publicKey.EthereumAddress() {
+				return false
+			}
+			sig[sizeSignature] += 27
+			address, err = Ecrecover(hash, sig)
+			return err == nil && address == publicKey.EthereumAddress()
+		},
+	))
+
+	properties.Property("[SECP256K1] test the rejection of high s", prop.ForAll(
+		func() bool {
+			privKey, _ := GenerateKey(rand.Reader)
+			publicKey := privKey.PublicKey
+
+			hash := keccak256([]byte("testing Ethereum"))
+			sig, _ := privKey.SignEthereum(hash)
+
+			// (r, n-s, v^1) is a valid high s signature
+			s := new(big.Int).SetBytes(sig[sizeFr:sizeSignature])
+			s.Sub(order, s).FillBytes(sig[sizeFr:sizeSignature])
+			sig[sizeSignature] ^= 1
+
+			if flag, _ := publicKey.Verify(sig[:sizeSignature], hash, nil); !flag {
+				return false
+			}
+			if _, err := publicKey.VerifyEthereum(sig, hash); err != ErrHighS {
+				return false
+			}
+			address, err := Ecrecover(hash, sig)
+			return err == nil && address == publicKey.EthereumAddress()
+		},
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+}
+
+func TestEthereumVectors(t *testing.T) {
+	// addresses of known private keys
+	for _, v := range []struct{ privKey, address string }{
+		{"01", "7e5f4552091a69125d5dfcb7b8c2659029395bdf"},
+		{"289c2857d4598e37fb9647507e47a309d6133539bf21a8b9cb6df88fd5232032", "970e8128ab834e8eac17ab8e3812f010678cf791"},
+	} {
+		privKey := privateKeyFromHex(t, v.privKey)
+		address := privKey.PublicKey.EthereumAddress()
+		if hex.EncodeToString(address[:]) != v.address {
+			t.Fatalf("wrong address for private key %s", v.privKey)
+		}
+	}
+
+	// signature of go-ethereum's crypto tests
+	hash, _ := hex.DecodeString("ce0677bb30baa8cf067c88db9811f4333d131bf8bcf12fe7065d211dce971008")
+	sig, _ := hex.DecodeString("90f27b8b488db00b00606796d2987f6a5f59ae62ea05effe84fef5b8b0e549984a691139ad57a3f0b906637673aa2f63d1f55cb1a69199d4009eea23ceaddc9301")
+	expected, _ := hex.DecodeString("e32df42865e97135acfb65f3bae71bdc86f4d49150ad6a440b6f15878109880a0a2b2667f7e725ceea70c673093bf67663e0312623c8e091b13cf2c0f11ef652")
+
+	var pk PublicKey
+	if err := pk.RecoverFromEthereum(hash, sig); err != nil {
+		t.Fatal(err)
+	}
+	if hex.EncodeToString(pk.Bytes()) != hex.EncodeToString(expected) {
+		t.Fatal("wrong recovered public key")
+	}
+	if flag, err := pk.VerifyEthereum(sig, hash); !flag || err != nil {
+		t.Fatal("signature should be valid")
+	}
+}
+
+func TestEcrecoverInvalidInputs(t *testing.T) {
+	privKey, _ := GenerateKey(rand.Reader)
+	hash := keccak256([]byte("testing Ethereum"))
+	sig, _ := privKey.SignEthereum(hash)
+
+	if _, err := Ecrecover(hash[1:], sig); err != ErrInvalidHashLength {
+		t.Fatal("expected error for a short hash")
+	}
+	if _, err := Ecrecover(hash, sig[:sizeSignature]); err != errWrongSize {
+		t.Fatal("expected error for a short signature")
+	}
+	for _, v := range []byte{2, 26, 29} {
+		sig[sizeSignature] = v
+		if _, err := Ecrecover(hash, sig); err != ErrInvalidRecoveryID {
+			t.Fatalf("expected error for the recovery id %d", v)
+		}
+	}
+	sig[sizeSignature] = 0
+	for i := 0; i < sizeFr; i++ {
+		sig[sizeFr+i] = 0
+	}
+	if _, err := Ecrecover(hash, sig); err != errZero {
+		t.Fatal("expected error for s = 0")
+	}
+}
+
+func BenchmarkEcrecover(b *testing.B) {
+	privKey, _ := GenerateKey(rand.Reader)
+	hash := keccak256([]byte("benchmarking Ethereum"))
+	sig, _ := privKey.SignEthereum(hash)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Ecrecover(hash, sig)
+	}
+}
@@ -0,0 +1,29 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+// Package ipa provides a transparent polynomial commitment scheme on
+// secp256k1, based on the inner product argument of Bulletproofs, cf
+// https://eprint.iacr.org/2017/1066.pdf, as used in Halo, cf
+// https://eprint.iacr.org/2019/1021.pdf.
+//
+// A vector a is committed with a Pedersen vector commitment C = ∑ᵢ aᵢGᵢ, where
+// the generators Gᵢ are derived by hashing, so that no trusted setup is needed.
+// An opening proof of ⟨a, b⟩ for a public vector b has 2⋅log₂(n) points, and is
+// verified with a single multi-scalar multiplication of size n.
+//
+// A polynomial p is committed in canonical basis, and opened at z with
+// b = (1, z, z², ...).
+package ipa
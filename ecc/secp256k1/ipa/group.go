@@ -0,0 +1,80 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package ipa
+
+import (
+	"encoding/binary"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/secp256k1"
+	"github.com/consensys/gnark-crypto/ecc/secp256k1/fr"
+	"github.com/consensys/gnark-crypto/internal/parallel"
+)
+
+// Digest commitment of a vector
+type Digest = secp256k1.G1Affine
+
+// multiExp returns ∑ᵢ[sᵢ]Pᵢ
+func multiExp(points []secp256k1.G1Affine, scalars []fr.Element) (secp256k1.G1Affine, error) {
+	var res secp256k1.G1Affine
+	if _, err := res.MultiExp(points, scalars, ecc.MultiExpConfig{}); err != nil {
+		return secp256k1.G1Affine{}, err
+	}
+	return res, nil
+}
+
+// foldPoints sets left[i] to left[i] + [x]right[i]
+func foldPoints(left, right []secp256k1.G1Affine, x fr.Element) {
+	var bX big.Int
+	x.BigInt(&bX)
+	res := make([]secp256k1.G1Jac, len(left))
+	parallel.Execute(len(left), func(start, end int) {
+		for i := start; i < end; i++ {
+			res[i].FromAffine(&right[i])
+			res[i].ScalarMultiplication(&res[i], &bX)
+			res[i].AddMixed(&left[i])
+		}
+	})
+	copy(left, secp256k1.BatchJacobianToAffineG1(res))
+}
+
+// hashToGroup returns n points of the group derived from seed with the hash to
+// curve, so that their discrete logarithms are unknown.
+func hashToGroup(seed string, n int) ([]secp256k1.G1Affine, error) {
+	res := make([]secp256k1.G1Affine, n)
+	var buf [8]byte
+	for i := range res {
+		binary.BigEndian.PutUint64(buf[:], uint64(i))
+		var err error
+		if res[i], err = secp256k1.HashToG1(buf[:], []byte(seed)); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// isIdentity returns true if p is the neutral element of the group
+func isIdentity(p *secp256k1.G1Affine) bool {
+	return p.IsInfinity()
+}
+
+// marshalPoint returns the encoding of p bound in the transcript
+func marshalPoint(p *secp256k1.G1Affine) []byte {
+	b := p.RawBytes()
+	return b[:]
+}
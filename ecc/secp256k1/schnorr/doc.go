@@ -0,0 +1,30 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package schnorr provides the BIP-340 Schnorr signature scheme on the
+// secp256k1 curve, as used by Bitcoin Taproot.
+//
+// Public keys are x-only: a public key is the x coordinate of the point P of
+// even y, and a secret key d of odd [d]G signs with n-d. A signature of a
+// message m is the x coordinate of R = [k]G, of even y, and s = k + e⋅d where
+//
+//	e = H_BIP0340/challenge(R.x ∥ P.x ∥ m)
+//
+// and the nonce k is derived from the secret key, the message and 32 bytes of
+// auxiliary randomness with tagged hashes. Signing with fixed auxiliary bytes
+// is deterministic.
+//
+// Documentation:
+// - BIP-340: https://github.com/bitcoin/bips/blob/master/bip-0340.mediawiki
+package schnorr
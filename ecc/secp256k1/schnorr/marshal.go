@@ -0,0 +1,109 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schnorr
+
+import (
+	"crypto/subtle"
+	"errors"
+	"io"
+
+	"github.com/consensys/gnark-crypto/ecc/secp256k1/fp"
+	"github.com/consensys/gnark-crypto/ecc/secp256k1/fr"
+)
+
+var errWrongSize = errors.New("wrong size buffer")
+var errRBiggerThanPMod = errors.New("r >= p_mod")
+var errSBiggerThanRMod = errors.New("s >= r_mod")
+var errPublicKeyMismatch = errors.New("the public key doesn't match the secret scalar")
+
+// Bytes returns the binary representation of the public key, the 32 bytes
+// big endian x coordinate of the point.
+func (pk *PublicKey) Bytes() []byte {
+	res := pk.A.X.Bytes()
+	return res[:]
+}
+
+// SetBytes sets pk from the 32 bytes big endian x coordinate of a point of
+// the curve, and takes the point of even y.
+// It returns the number of bytes read from the buffer.
+func (pk *PublicKey) SetBytes(buf []byte) (int, error) {
+	if len(buf) < sizePublicKey {
+		return 0, io.ErrShortBuffer
+	}
+	if err := liftX(&pk.A, buf[:sizePublicKey]); err != nil {
+		return 0, err
+	}
+	return sizePublicKey, nil
+}
+
+// Bytes returns the binary representation of privKey,
+// as byte array publicKey||scalar
+// where publicKey is as publicKey.Bytes(), and
+// scalar is in big endian, of size sizeFr.
+func (privKey *PrivateKey) Bytes() []byte {
+	var res [sizePrivateKey]byte
+	copy(res[:sizePublicKey], privKey.PublicKey.Bytes())
+	subtle.ConstantTimeCopy(1, res[sizePublicKey:], privKey.scalar[:])
+	return res[:]
+}
+
+// SetBytes sets privKey from buf, where buf is interpreted
+// as publicKey||scalar
+// where publicKey is as publicKey.Bytes(), and
+// scalar is in big endian, of size sizeFr.
+// It returns the number byte read.
+func (privKey *PrivateKey) SetBytes(buf []byte) (int, error) {
+	if len(buf) < sizePrivateKey {
+		return 0, io.ErrShortBuffer
+	}
+	k, err := NewPrivateKey(buf[sizePublicKey:sizePrivateKey])
+	if err != nil {
+		return 0, err
+	}
+	if subtle.ConstantTimeCompare(k.PublicKey.Bytes(), buf[:sizePublicKey]) != 1 {
+		return 0, errPublicKeyMismatch
+	}
+	*privKey = *k
+	return sizePrivateKey, nil
+}
+
+// Bytes returns the binary representation of sig
+// as a byte array of size 64 r||s
+func (sig *Signature) Bytes() []byte {
+	var res [sizeSignature]byte
+	copy(res[:sizeFp], sig.R[:])
+	copy(res[sizeFp:], sig.S[:])
+	return res[:]
+}
+
+// SetBytes sets sig from a buffer in binary.
+// buf is read interpreted as r||s, with r < p and s < n.
+// It returns the number of bytes read from buf.
+func (sig *Signature) SetBytes(buf []byte) (int, error) {
+	if len(buf) != sizeSignature {
+		return 0, errWrongSize
+	}
+	var r fp.Element
+	if err := r.SetBytesCanonical(buf[:sizeFp]); err != nil {
+		return 0, errRBiggerThanPMod
+	}
+	var s fr.Element
+	if err := s.SetBytesCanonical(buf[sizeFp:]); err != nil {
+		return 0, errSBiggerThanRMod
+	}
+	copy(sig.R[:], buf[:sizeFp])
+	copy(sig.S[:], buf[sizeFp:])
+	return sizeSignature, nil
+}
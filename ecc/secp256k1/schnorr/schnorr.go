@@ -0,0 +1,363 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schnorr
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"hash"
+	"io"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/secp256k1"
+	"github.com/consensys/gnark-crypto/ecc/secp256k1/fp"
+	"github.com/consensys/gnark-crypto/ecc/secp256k1/fr"
+	"github.com/consensys/gnark-crypto/signature"
+)
+
+const (
+	sizeFr         = fr.Bytes
+	sizeFp         = fp.Bytes
+	sizePublicKey  = sizeFp
+	sizePrivateKey = sizePublicKey + sizeFr
+	sizeSignature  = sizeFp + sizeFr
+	sizeAux        = 32
+)
+
+var (
+	// ErrInvalidPrivateKey is returned when the secret scalar is 0 or not
+	// smaller than the order of the curve.
+	ErrInvalidPrivateKey = errors.New("invalid private key")
+	// ErrInvalidPublicKey is returned when a public key is not the x
+	// coordinate of a point of the curve.
+	ErrInvalidPublicKey = errors.New("invalid public key")
+	// ErrInvalidAuxSize is returned when the auxiliary randomness is not 32 bytes.
+	ErrInvalidAuxSize = errors.New("the auxiliary randomness should be 32 bytes")
+	// ErrZeroNonce is returned when the derived nonce is 0, which happens
+	// with negligible probability.
+	ErrZeroNonce = errors.New("the nonce is 0")
+	// ErrNbSignatures is returned by BatchVerify when the numbers of public
+	// keys, signatures and messages differ.
+	ErrNbSignatures = errors.New("the numbers of public keys, signatures and messages should be equal")
+)
+
+// the tags of the hashes of BIP-340
+var (
+	tagAux       = taggedHashPrefix("BIP0340/aux")
+	tagNonce     = taggedHashPrefix("BIP0340/nonce")
+	tagChallenge = taggedHashPrefix("BIP0340/challenge")
+)
+
+// PublicKey represents a BIP-340 public key, the point of even y whose x
+// coordinate is the x-only key
+type PublicKey struct {
+	A secp256k1.G1Affine
+}
+
+// PrivateKey represents a BIP-340 private key
+type PrivateKey struct {
+	PublicKey PublicKey
+	scalar    [sizeFr]byte // secret scalar, in big Endian
+}
+
+// Signature represents a BIP-340 signature
+type Signature struct {
+	R [sizeFp]byte // x coordinate of the commitment, of even y
+	S [sizeFr]byte
+}
+
+// GenerateKey generates a public and private key pair.
+func GenerateKey(rand io.Reader) (*PrivateKey, error) {
+	var b [sizeFr]byte
+	for {
+		if _, err := io.ReadFull(rand, b[:]); err != nil {
+			return nil, err
+		}
+		privKey, err := NewPrivateKey(b[:])
+		if err == nil {
+			return privKey, nil
+		}
+		if err != ErrInvalidPrivateKey {
+			return nil, err
+		}
+	}
+}
+
+// NewPrivateKey returns the private key of the 32 bytes big endian secret
+// scalar, which must be in [1, n-1].
+func NewPrivateKey(scalar []byte) (*PrivateKey, error) {
+	if len(scalar) != sizeFr {
+		return nil, ErrInvalidPrivateKey
+	}
+	var d fr.Element
+	if err := d.SetBytesCanonical(scalar); err != nil || d.IsZero() {
+		return nil, ErrInvalidPrivateKey
+	}
+	privKey := new(PrivateKey)
+	copy(privKey.scalar[:], scalar)
+	privKey.PublicKey.A.ScalarMultiplicationBase(d.BigInt(new(big.Int)))
+	if !hasEvenY(&privKey.PublicKey.A) {
+		privKey.PublicKey.A.Neg(&privKey.PublicKey.A)
+	}
+	return privKey, nil
+}
+
+// Public returns the public key associated to the private key.
+func (privKey *PrivateKey) Public() signature.PublicKey {
+	var pub PublicKey
+	pub.A.Set(&privKey.PublicKey.A)
+	return &pub
+}
+
+// Equal compares 2 public keys
+func (pub *PublicKey) Equal(x signature.PublicKey) bool {
+	xx, ok := x.(*PublicKey)
+	if !ok {
+		return false
+	}
+	bpk := pub.Bytes()
+	bxx := xx.Bytes()
+	return subtle.ConstantTimeCompare(bpk, bxx) == 1
+}
+
+// Sign performs the BIP-340 signature, with 32 bytes of auxiliary randomness
+// read from crypto/rand. If hFunc is not nil, the message is hashed with it
+// first.
+func (privKey *PrivateKey) Sign(message []byte, hFunc hash.Hash) ([]byte, error) {
+	var aux [sizeAux]byte
+	if _, err := io.ReadFull(rand.Reader, aux[:]); err != nil {
+		return nil, err
+	}
+	return privKey.SignWithAux(message, hFunc, aux[:])
+}
+
+// SignWithAux performs the BIP-340 signature with the given 32 bytes of
+// auxiliary randomness. The signature is deterministic for fixed auxiliary
+// bytes, which may be all zero. If hFunc is not nil, the message is hashed
+// with it first.
+//
+// d = sk if [sk]G has an even y, n-sk otherwise
+// t = d ⊕ H_BIP0340/aux(aux)
+// k = H_BIP0340/nonce(t ∥ P.x ∥ m) mod n, negated if [k]G has an odd y
+// R = [k]G
+// e = H_BIP0340/challenge(R.x ∥ P.x ∥ m) mod n
+// signature = R.x ∥ k + e⋅d mod n
+func (privKey *PrivateKey) SignWithAux(message []byte, hFunc hash.Hash, aux []byte) ([]byte, error) {
+	if len(aux) != sizeAux {
+		return nil, ErrInvalidAuxSize
+	}
+	m, err := hashMessage(message, hFunc)
+	if err != nil {
+		return nil, err
+	}
+
+	var d fr.Element
+	if err := d.SetBytesCanonical(privKey.scalar[:]); err != nil || d.IsZero() {
+		return nil, ErrInvalidPrivateKey
+	}
+	var P secp256k1.G1Affine
+	P.ScalarMultiplicationBase(d.BigInt(new(big.Int)))
+	if !hasEvenY(&P) {
+		d.Neg(&d)
+	}
+	px := P.X.Bytes()
+
+	// nonce
+	t := d.Bytes()
+	auxHash := taggedHash(tagAux, aux)
+	for i := range t {
+		t[i] ^= auxHash[i]
+	}
+	var k fr.Element
+	k.SetBytes(taggedHash(tagNonce, t[:], px[:], m))
+	if k.IsZero() {
+		return nil, ErrZeroNonce
+	}
+	var R secp256k1.G1Affine
+	R.ScalarMultiplicationBase(k.BigInt(new(big.Int)))
+	if !hasEvenY(&R) {
+		k.Neg(&k)
+	}
+
+	var sig Signature
+	sig.R = R.X.Bytes()
+	e := challenge(sig.R[:], px[:], m)
+	var s fr.Element
+	s.Mul(&e, &d).Add(&s, &k)
+	sig.S = s.Bytes()
+
+	return sig.Bytes(), nil
+}
+
+// Verify validates the BIP-340 signature. If hFunc is not nil, the message is
+// hashed with it first.
+//
+// R = [s]G - [e]P
+// R.x ?= r, and R has an even y
+func (publicKey *PublicKey) Verify(sigBin, message []byte, hFunc hash.Hash) (bool, error) {
+	var sig Signature
+	if _, err := sig.SetBytes(sigBin); err != nil {
+		return false, err
+	}
+	m, err := hashMessage(message, hFunc)
+	if err != nil {
+		return false, err
+	}
+
+	px := publicKey.A.X.Bytes()
+	e := challenge(sig.R[:], px[:], m)
+	var s fr.Element
+	s.SetBytes(sig.S[:])
+	e.Neg(&e)
+
+	var _R secp256k1.G1Jac
+	_R.JointScalarMultiplicationBase(&publicKey.A, s.BigInt(new(big.Int)), e.BigInt(new(big.Int)))
+	var R secp256k1.G1Affine
+	R.FromJacobian(&_R)
+	if R.IsInfinity() || !hasEvenY(&R) {
+		return false, nil
+	}
+	rx := R.X.Bytes()
+	return subtle.ConstantTimeCompare(rx[:], sig.R[:]) == 1, nil
+}
+
+// BatchVerify validates the BIP-340 signatures sigs of the messages msgs by
+// the public keys publicKeys at once. If hFunc is not nil, the messages are
+// hashed with it first.
+//
+// With random a₀ = 1, a₁, ..., it checks with a single multi-exponentiation
+//
+//	[∑ᵢaᵢsᵢ]G - ∑ᵢ[aᵢ]Rᵢ - ∑ᵢ[aᵢeᵢ]Pᵢ ?= 0
+//
+// where Rᵢ is the point of even y of x coordinate rᵢ. It returns true if all
+// the signatures are valid, and false if at least one is not, with
+// overwhelming probability.
+func BatchVerify(publicKeys []PublicKey, sigs, msgs [][]byte, hFunc hash.Hash) (bool, error) {
+	if len(publicKeys) != len(sigs) || len(publicKeys) != len(msgs) {
+		return false, ErrNbSignatures
+	}
+	n := len(publicKeys)
+	if n == 0 {
+		return true, nil
+	}
+
+	points := make([]secp256k1.G1Affine, 2*n+1)
+	scalars := make([]fr.Element, 2*n+1)
+	_, points[0] = secp256k1.Generators()
+	for i := 0; i < n; i++ {
+		var sig Signature
+		if _, err := sig.SetBytes(sigs[i]); err != nil {
+			return false, err
+		}
+		m, err := hashMessage(msgs[i], hFunc)
+		if err != nil {
+			return false, err
+		}
+		if err := liftX(&points[1+i], sig.R[:]); err != nil {
+			return false, nil
+		}
+		points[1+n+i] = publicKeys[i].A
+
+		var a, s fr.Element
+		if i == 0 {
+			a.SetOne()
+		} else if _, err := a.SetRandom(); err != nil {
+			return false, err
+		}
+		px := publicKeys[i].A.X.Bytes()
+		e := challenge(sig.R[:], px[:], m)
+		s.SetBytes(sig.S[:])
+
+		s.Mul(&s, &a)
+		scalars[0].Add(&scalars[0], &s)
+		scalars[1+i].Neg(&a)
+		scalars[1+n+i].Mul(&e, &a).Neg(&scalars[1+n+i])
+	}
+
+	var res secp256k1.G1Jac
+	if _, err := res.MultiExp(points, scalars, ecc.MultiExpConfig{}); err != nil {
+		return false, err
+	}
+	return res.Z.IsZero(), nil
+}
+
+// hashMessage returns the message hashed by hFunc, or the message if hFunc is nil
+func hashMessage(message []byte, hFunc hash.Hash) ([]byte, error) {
+	if hFunc == nil {
+		return message, nil
+	}
+	hFunc.Reset()
+	if _, err := hFunc.Write(message); err != nil {
+		return nil, err
+	}
+	return hFunc.Sum(nil), nil
+}
+
+// challenge returns H_BIP0340/challenge(r ∥ px ∥ m) mod n
+func challenge(r, px, m []byte) fr.Element {
+	var e fr.Element
+	e.SetBytes(taggedHash(tagChallenge, r, px, m))
+	return e
+}
+
+// taggedHashPrefix returns SHA256(tag) ∥ SHA256(tag)
+func taggedHashPrefix(tag string) []byte {
+	h := sha256.Sum256([]byte(tag))
+	return append(h[:], h[:]...)
+}
+
+// taggedHash returns SHA256(SHA256(tag) ∥ SHA256(tag) ∥ data...)
+func taggedHash(prefix []byte, data ...[]byte) []byte {
+	h := sha256.New()
+	h.Write(prefix)
+	for _, d := range data {
+		h.Write(d)
+	}
+	return h.Sum(nil)
+}
+
+// hasEvenY returns true if the y coordinate of p is even
+func hasEvenY(p *secp256k1.G1Affine) bool {
+	return p.Y.Bits()[0]&1 == 0
+}
+
+// liftX sets p to the point of even y whose x coordinate is the 32 bytes big
+// endian x, which must be smaller than the field size.
+func liftX(p *secp256k1.G1Affine, x []byte) error {
+	if len(x) != sizeFp {
+		return ErrInvalidPublicKey
+	}
+	if err := p.X.SetBytesCanonical(x); err != nil {
+		return ErrInvalidPublicKey
+	}
+	// y² = x³ + 7
+	var c fp.Element
+	c.Square(&p.X).Mul(&c, &p.X).Add(&c, &curveB)
+	if p.Y.Sqrt(&c) == nil {
+		return ErrInvalidPublicKey
+	}
+	if !hasEvenY(p) {
+		p.Y.Neg(&p.Y)
+	}
+	return nil
+}
+
+var curveB = func() fp.Element {
+	_, b := secp256k1.CurveCoefficients()
+	return b
+}()
@@ -3,6 +3,7 @@ package generator
 import (
 	"fmt"
 	"io"
+	"math/big"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -14,6 +15,7 @@ import (
 	"github.com/consensys/gnark-crypto/field/generator/config"
 	"github.com/consensys/gnark-crypto/field/generator/internal/addchain"
 	"github.com/consensys/gnark-crypto/field/generator/internal/templates/element"
+	"github.com/consensys/gnark-crypto/field/generator/internal/templates/extensions"
 )
 
 // GenerateFF will generate go (and .s) files in outputDir for modulus (in base 10)
@@ -271,6 +273,121 @@ func GenerateFF(F *config.FieldConfig, outputDir, asmDirBuildPath, asmDirInclude
 	return nil
 }
 
+// GenerateExtensions will generate go files in outputDir (package extensions) for
+// the radical extensions exts of the field F, generated in basePackagePath.
+// Only extensions of degree 2 and 3 are supported, and the degree must divide p-1.
+//
+// Example usage
+//
+//	goldilocks, _ = config.NewFieldConfig("goldilocks", "Element", modulus, true)
+//	generator.GenerateExtensions(goldilocks, "github.com/consensys/gnark-crypto/field/goldilocks", "../extensions", config.NewTower(goldilocks, 2, 7))
+func GenerateExtensions(F *config.FieldConfig, basePackagePath, outputDir string, exts ...config.Extension) error {
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return err
+	}
+
+	bavardOpts := []func(*bavard.Bavard) error{
+		bavard.Apache2("ConsenSys Software Inc.", 2020),
+		bavard.Package("extensions"),
+		bavard.GeneratedBy("consensys/gnark-crypto"),
+	}
+
+	var docData struct {
+		BasePackageName string
+		ElementName     string
+		Extensions      []extensionData
+	}
+	docData.BasePackageName = F.PackageName
+	docData.ElementName = F.ElementName
+
+	for _, ext := range exts {
+		data, err := newExtensionData(F, basePackagePath, ext)
+		if err != nil {
+			return err
+		}
+		docData.Extensions = append(docData.Extensions, data)
+		name := strings.ToLower(data.TypeName)
+		if err := bavard.GenerateFromString(filepath.Join(outputDir, name+".go"), []string{extensions.Extension}, data, bavardOpts...); err != nil {
+			return err
+		}
+		if err := bavard.GenerateFromString(filepath.Join(outputDir, name+"_test.go"), []string{extensions.Test}, data, bavardOpts...); err != nil {
+			return err
+		}
+	}
+
+	docOpts := []func(*bavard.Bavard) error{
+		bavard.Apache2("ConsenSys Software Inc.", 2020),
+		bavard.GeneratedBy("consensys/gnark-crypto"),
+	}
+	if err := bavard.GenerateFromString(filepath.Join(outputDir, "doc.go"), []string{extensions.Doc}, docData, docOpts...); err != nil {
+		return err
+	}
+	if err := bavard.GenerateFromString(filepath.Join(outputDir, "common.go"), []string{extensions.Common}, nil, bavardOpts...); err != nil {
+		return err
+	}
+	if err := bavard.GenerateFromString(filepath.Join(outputDir, "common_test.go"), []string{extensions.CommonTest}, nil, bavardOpts...); err != nil {
+		return err
+	}
+
+	// run go fmt on whole directory
+	cmd := exec.Command("gofmt", "-s", "-w", outputDir)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// extensionData is the data passed to the extension templates
+type extensionData struct {
+	TypeName              string
+	BaseElement           string
+	BasePackageName       string
+	BasePackagePath       string
+	Degree                int
+	RootOf                int64
+	Coords                []string
+	FrobeniusCoefficients []string
+}
+
+func newExtensionData(F *config.FieldConfig, basePackagePath string, ext config.Extension) (extensionData, error) {
+	if ext.Degree != 2 && ext.Degree != 3 {
+		return extensionData{}, fmt.Errorf("unsupported extension degree %d", ext.Degree)
+	}
+	if ext.RootOf == 0 {
+		return extensionData{}, fmt.Errorf("the extension must be obtained by adjoining a root of a non-zero element")
+	}
+
+	// γ = α^((p-1)/n), the Frobenius map sends u^i to γ^i u^i
+	var pMinusOne, exponent, rem, gamma big.Int
+	pMinusOne.Sub(F.ModulusBig, big.NewInt(1))
+	exponent.QuoRem(&pMinusOne, big.NewInt(int64(ext.Degree)), &rem)
+	if rem.Sign() != 0 {
+		return extensionData{}, fmt.Errorf("the extension degree %d must divide p-1", ext.Degree)
+	}
+	alpha := big.NewInt(ext.RootOf)
+	alpha.Mod(alpha, F.ModulusBig)
+	gamma.Exp(alpha, &exponent, F.ModulusBig)
+	if gamma.Cmp(big.NewInt(1)) == 0 {
+		// α is an n-th power, uⁿ - α is reducible
+		return extensionData{}, fmt.Errorf("u^%d - %d is not irreducible", ext.Degree, ext.RootOf)
+	}
+
+	data := extensionData{
+		TypeName:        fmt.Sprintf("E%d", ext.Degree),
+		BaseElement:     F.PackageName + "." + F.ElementName,
+		BasePackageName: F.PackageName,
+		BasePackagePath: basePackagePath,
+		Degree:          ext.Degree,
+		RootOf:          ext.RootOf,
+	}
+	coeff := big.NewInt(1)
+	for i := 0; i < ext.Degree; i++ {
+		data.Coords = append(data.Coords, fmt.Sprintf("A%d", i))
+		data.FrobeniusCoefficients = append(data.FrobeniusCoefficients, coeff.String())
+		coeff.Mul(coeff, &gamma).Mod(coeff, F.ModulusBig)
+	}
+	return data, nil
+}
+
 func shorten(input string) string {
 	const maxLen = 15
 	if len(input) > maxLen {
@@ -0,0 +1,480 @@
+package extensions
+
+// Extension is the template of a radical extension 𝔽p[u]/(uⁿ - α), for n = 2 or 3.
+const Extension = `
+{{- $T := .TypeName }}
+{{- $F := .BaseElement }}
+import (
+	"errors"
+	"math/big"
+
+	"{{.BasePackagePath}}"
+)
+
+// {{$T}} is a degree {{.Degree}} extension of {{$F}}: {{.BasePackageName}}[u]/(u{{if eq .Degree 2}}²{{else}}³{{end}} - {{.RootOf}}).
+//
+// An element is represented as {{range $i, $c := .Coords}}{{if $i}} + {{end}}{{$c}}{{if eq $i 1}}⋅u{{else if eq $i 2}}⋅u²{{end}}{{end}}.
+type {{$T}} struct {
+	{{- range .Coords}}
+	{{.}} {{$F}}
+	{{- end}}
+}
+
+// Size{{$T}} is the number of bytes needed to represent an element of {{$T}}
+const Size{{$T}} = {{.Degree}} * {{.BasePackageName}}.Bytes
+
+var (
+	// nonResidue{{$T}} is α such that u{{if eq .Degree 2}}²{{else}}³{{end}} = α
+	nonResidue{{$T}} {{$F}}
+
+	// frobeniusCoefficients{{$T}}[i] = α^(i⋅(p-1)/{{.Degree}}), such that φ(u^i) = frobeniusCoefficients{{$T}}[i]⋅u^i
+	frobeniusCoefficients{{$T}} [{{.Degree}}]{{$F}}
+)
+
+func init() {
+	nonResidue{{$T}}.SetInt64({{.RootOf}})
+	{{- range $i, $c := .FrobeniusCoefficients}}
+	if _, err := frobeniusCoefficients{{$T}}[{{$i}}].SetString("{{$c}}"); err != nil {
+		panic(err)
+	}
+	{{- end}}
+}
+
+// mulByNonResidue{{$T}} sets z to α⋅x
+func mulByNonResidue{{$T}}(z, x *{{$F}}) {
+	z.Mul(x, &nonResidue{{$T}})
+}
+
+// Equal returns true if z equals x, false otherwise
+func (z *{{$T}}) Equal(x *{{$T}}) bool {
+	return {{range $i, $c := .Coords}}{{if $i}} && {{end}}z.{{$c}}.Equal(&x.{{$c}}){{end}}
+}
+
+// SetZero sets an {{$T}} elmt to zero
+func (z *{{$T}}) SetZero() *{{$T}} {
+	{{- range .Coords}}
+	z.{{.}}.SetZero()
+	{{- end}}
+	return z
+}
+
+// SetOne sets z to 1 in Montgomery form and returns z
+func (z *{{$T}}) SetOne() *{{$T}} {
+	{{- range $i, $c := .Coords}}
+	{{- if eq $i 0}}
+	z.{{$c}}.SetOne()
+	{{- else}}
+	z.{{$c}}.SetZero()
+	{{- end}}
+	{{- end}}
+	return z
+}
+
+// Set sets an {{$T}} from x
+func (z *{{$T}}) Set(x *{{$T}}) *{{$T}} {
+	*z = *x
+	return z
+}
+
+// SetRandom sets all the coordinates of z to random values
+func (z *{{$T}}) SetRandom() (*{{$T}}, error) {
+	{{- range .Coords}}
+	if _, err := z.{{.}}.SetRandom(); err != nil {
+		return nil, err
+	}
+	{{- end}}
+	return z, nil
+}
+
+// IsZero returns true if z is zero, false otherwise
+func (z *{{$T}}) IsZero() bool {
+	return {{range $i, $c := .Coords}}{{if $i}} && {{end}}z.{{$c}}.IsZero(){{end}}
+}
+
+// IsOne returns true if z is one, false otherwise
+func (z *{{$T}}) IsOne() bool {
+	return {{range $i, $c := .Coords}}{{if $i}} && {{end}}{{if eq $i 0}}z.{{$c}}.IsOne(){{else}}z.{{$c}}.IsZero(){{end}}{{end}}
+}
+
+// Add adds two elements of {{$T}}
+func (z *{{$T}}) Add(x, y *{{$T}}) *{{$T}} {
+	{{- range .Coords}}
+	z.{{.}}.Add(&x.{{.}}, &y.{{.}})
+	{{- end}}
+	return z
+}
+
+// Sub subtracts two elements of {{$T}}
+func (z *{{$T}}) Sub(x, y *{{$T}}) *{{$T}} {
+	{{- range .Coords}}
+	z.{{.}}.Sub(&x.{{.}}, &y.{{.}})
+	{{- end}}
+	return z
+}
+
+// Double doubles an {{$T}} element
+func (z *{{$T}}) Double(x *{{$T}}) *{{$T}} {
+	{{- range .Coords}}
+	z.{{.}}.Double(&x.{{.}})
+	{{- end}}
+	return z
+}
+
+// Neg negates an {{$T}} element
+func (z *{{$T}}) Neg(x *{{$T}}) *{{$T}} {
+	{{- range .Coords}}
+	z.{{.}}.Neg(&x.{{.}})
+	{{- end}}
+	return z
+}
+
+// MulByElement multiplies an element in {{$T}} by an element in {{.BasePackageName}}
+func (z *{{$T}}) MulByElement(x *{{$T}}, y *{{$F}}) *{{$T}} {
+	var yCopy {{$F}}
+	yCopy.Set(y)
+	{{- range .Coords}}
+	z.{{.}}.Mul(&x.{{.}}, &yCopy)
+	{{- end}}
+	return z
+}
+
+{{- if eq .Degree 2}}
+
+// Mul sets z to the {{$T}}-product of x,y, returns z
+func (z *{{$T}}) Mul(x, y *{{$T}}) *{{$T}} {
+	// Karatsuba
+	var a, b, c {{$F}}
+	a.Add(&x.A0, &x.A1)
+	b.Add(&y.A0, &y.A1)
+	a.Mul(&a, &b)
+	b.Mul(&x.A0, &y.A0)
+	c.Mul(&x.A1, &y.A1)
+	z.A1.Sub(&a, &b).Sub(&z.A1, &c)
+	mulByNonResidue{{$T}}(&c, &c)
+	z.A0.Add(&b, &c)
+	return z
+}
+
+// Square sets z to the {{$T}}-product of x,x returns z
+func (z *{{$T}}) Square(x *{{$T}}) *{{$T}} {
+	// (a0 + a1⋅u)² = a0² + α⋅a1² + 2⋅a0⋅a1⋅u
+	var a, b, c {{$F}}
+	a.Square(&x.A0)
+	b.Square(&x.A1)
+	mulByNonResidue{{$T}}(&b, &b)
+	c.Mul(&x.A0, &x.A1).Double(&c)
+	z.A0.Add(&a, &b)
+	z.A1.Set(&c)
+	return z
+}
+
+// Conjugate sets z to a0 - a1⋅u, the image of x by the non trivial automorphism of {{$T}}
+func (z *{{$T}}) Conjugate(x *{{$T}}) *{{$T}} {
+	z.A0.Set(&x.A0)
+	z.A1.Neg(&x.A1)
+	return z
+}
+
+// Norm returns the norm of x, that is a0² - α⋅a1²
+func (x *{{$T}}) Norm() {{$F}} {
+	var a, b {{$F}}
+	a.Square(&x.A0)
+	b.Square(&x.A1)
+	mulByNonResidue{{$T}}(&b, &b)
+	a.Sub(&a, &b)
+	return a
+}
+
+// Inverse sets z to the {{$T}}-inverse of x, returns z
+//
+// if x == 0, sets and returns z = x
+func (z *{{$T}}) Inverse(x *{{$T}}) *{{$T}} {
+	// 1/(a0 + a1⋅u) = (a0 - a1⋅u)/(a0² - α⋅a1²)
+	norm := x.Norm()
+	norm.Inverse(&norm)
+	z.Conjugate(x)
+	return z.MulByElement(z, &norm)
+}
+{{- else}}
+
+// Mul sets z to the {{$T}}-product of x,y, returns z
+func (z *{{$T}}) Mul(x, y *{{$T}}) *{{$T}} {
+	// Algorithm 13 from https://eprint.iacr.org/2010/354.pdf
+	var t0, t1, t2, c0, c1, c2, tmp {{$F}}
+	t0.Mul(&x.A0, &y.A0)
+	t1.Mul(&x.A1, &y.A1)
+	t2.Mul(&x.A2, &y.A2)
+
+	// c0 = t0 + α⋅((a1+a2)(b1+b2) - t1 - t2)
+	c0.Add(&x.A1, &x.A2)
+	tmp.Add(&y.A1, &y.A2)
+	c0.Mul(&c0, &tmp).Sub(&c0, &t1).Sub(&c0, &t2)
+	mulByNonResidue{{$T}}(&c0, &c0)
+	c0.Add(&c0, &t0)
+
+	// c1 = (a0+a1)(b0+b1) - t0 - t1 + α⋅t2
+	c1.Add(&x.A0, &x.A1)
+	tmp.Add(&y.A0, &y.A1)
+	c1.Mul(&c1, &tmp).Sub(&c1, &t0).Sub(&c1, &t1)
+	mulByNonResidue{{$T}}(&tmp, &t2)
+	c1.Add(&c1, &tmp)
+
+	// c2 = (a0+a2)(b0+b2) - t0 - t2 + t1
+	c2.Add(&x.A0, &x.A2)
+	tmp.Add(&y.A0, &y.A2)
+	c2.Mul(&c2, &tmp).Sub(&c2, &t0).Sub(&c2, &t2).Add(&c2, &t1)
+
+	z.A0.Set(&c0)
+	z.A1.Set(&c1)
+	z.A2.Set(&c2)
+	return z
+}
+
+// Square sets z to the {{$T}}-product of x,x returns z
+func (z *{{$T}}) Square(x *{{$T}}) *{{$T}} {
+	// (a0 + a1⋅u + a2⋅u²)² = a0² + 2α⋅a1⋅a2 + (2⋅a0⋅a1 + α⋅a2²)⋅u + (a1² + 2⋅a0⋅a2)⋅u²
+	var c0, c1, c2, tmp {{$F}}
+	c0.Mul(&x.A1, &x.A2).Double(&c0)
+	mulByNonResidue{{$T}}(&c0, &c0)
+	tmp.Square(&x.A0)
+	c0.Add(&c0, &tmp)
+
+	c1.Square(&x.A2)
+	mulByNonResidue{{$T}}(&c1, &c1)
+	tmp.Mul(&x.A0, &x.A1).Double(&tmp)
+	c1.Add(&c1, &tmp)
+
+	c2.Mul(&x.A0, &x.A2).Double(&c2)
+	tmp.Square(&x.A1)
+	c2.Add(&c2, &tmp)
+
+	z.A0.Set(&c0)
+	z.A1.Set(&c1)
+	z.A2.Set(&c2)
+	return z
+}
+
+// Inverse sets z to the {{$T}}-inverse of x, returns z
+//
+// if x == 0, sets and returns z = x
+func (z *{{$T}}) Inverse(x *{{$T}}) *{{$T}} {
+	// Algorithm 17 from https://eprint.iacr.org/2010/354.pdf
+	var t0, t1, t2, t3, t4, t5, t6, c0, c1, c2, d1, d2 {{$F}}
+	t0.Square(&x.A0)
+	t1.Square(&x.A1)
+	t2.Square(&x.A2)
+	t3.Mul(&x.A0, &x.A1)
+	t4.Mul(&x.A0, &x.A2)
+	t5.Mul(&x.A1, &x.A2)
+	// c0 = a0² - α⋅a1⋅a2
+	mulByNonResidue{{$T}}(&c0, &t5)
+	c0.Sub(&t0, &c0)
+	// c1 = α⋅a2² - a0⋅a1
+	mulByNonResidue{{$T}}(&c1, &t2)
+	c1.Sub(&c1, &t3)
+	// c2 = a1² - a0⋅a2
+	c2.Sub(&t1, &t4)
+	// t6 = a0⋅c0 + α⋅(a2⋅c1 + a1⋅c2)
+	t6.Mul(&x.A0, &c0)
+	d1.Mul(&x.A2, &c1)
+	d2.Mul(&x.A1, &c2)
+	d1.Add(&d1, &d2)
+	mulByNonResidue{{$T}}(&d1, &d1)
+	t6.Add(&t6, &d1)
+	t6.Inverse(&t6)
+	z.A0.Mul(&c0, &t6)
+	z.A1.Mul(&c1, &t6)
+	z.A2.Mul(&c2, &t6)
+
+	return z
+}
+
+// Norm returns the norm of x, that is the product of its conjugates x⋅φ(x)⋅φ²(x)
+func (x *{{$T}}) Norm() {{$F}} {
+	var c0, c1, c2, t, n {{$F}}
+	// c0 = a0² - α⋅a1⋅a2
+	c0.Mul(&x.A1, &x.A2)
+	mulByNonResidue{{$T}}(&c0, &c0)
+	t.Square(&x.A0)
+	c0.Sub(&t, &c0)
+	// c1 = α⋅a2² - a0⋅a1
+	c1.Square(&x.A2)
+	mulByNonResidue{{$T}}(&c1, &c1)
+	t.Mul(&x.A0, &x.A1)
+	c1.Sub(&c1, &t)
+	// c2 = a1² - a0⋅a2
+	c2.Square(&x.A1)
+	t.Mul(&x.A0, &x.A2)
+	c2.Sub(&c2, &t)
+	// n = a0⋅c0 + α⋅(a2⋅c1 + a1⋅c2)
+	n.Mul(&x.A2, &c1)
+	t.Mul(&x.A1, &c2)
+	n.Add(&n, &t)
+	mulByNonResidue{{$T}}(&n, &n)
+	t.Mul(&x.A0, &c0)
+	n.Add(&n, &t)
+	return n
+}
+{{- end}}
+
+// Div divides an element in {{$T}} by an element in {{$T}}
+func (z *{{$T}}) Div(x *{{$T}}, y *{{$T}}) *{{$T}} {
+	var r {{$T}}
+	r.Inverse(y).Mul(x, &r)
+	return z.Set(&r)
+}
+
+// Frobenius sets z to x^p, returns z
+func (z *{{$T}}) Frobenius(x *{{$T}}) *{{$T}} {
+	// φ(a0 + a1⋅u + ...) = a0 + a1⋅u^p + ... and u^p = α^((p-1)/{{.Degree}})⋅u
+	{{- range $i, $c := .Coords}}
+	{{- if eq $i 0}}
+	z.{{$c}}.Set(&x.{{$c}})
+	{{- else}}
+	z.{{$c}}.Mul(&x.{{$c}}, &frobeniusCoefficients{{$T}}[{{$i}}])
+	{{- end}}
+	{{- end}}
+	return z
+}
+
+// Exp sets z=xᵏ (mod q) and returns it
+func (z *{{$T}}) Exp(x {{$T}}, k *big.Int) *{{$T}} {
+	if k.IsUint64() && k.Uint64() == 0 {
+		return z.SetOne()
+	}
+
+	e := k
+	if k.Sign() == -1 {
+		// negative k, we invert
+		// if k < 0: xᵏ (mod q) == (x⁻¹)ᵏ (mod q)
+		x.Inverse(&x)
+
+		// we negate k in a temp big.Int since
+		// Int.Bit(_) of k and -k is different
+		e = bigIntPool.Get().(*big.Int)
+		defer bigIntPool.Put(e)
+		e.Neg(k)
+	}
+
+	z.SetOne()
+	b := e.Bytes()
+	for i := 0; i < len(b); i++ {
+		w := b[i]
+		for j := 0; j < 8; j++ {
+			z.Square(z)
+			if (w & (0b10000000 >> j)) != 0 {
+				z.Mul(z, &x)
+			}
+		}
+	}
+
+	return z
+}
+
+// String implements Stringer interface for fancy printing
+func (z *{{$T}}) String() string {
+	return {{range $i, $c := .Coords}}{{if $i}} + "+(" + {{end}}z.{{$c}}.String(){{if eq $i 1}} + ")*u"{{else if eq $i 2}} + ")*u**2"{{end}}{{end}}
+}
+
+// Bytes returns the value of z as a big-endian byte array, a0 || a1 ...
+func (z *{{$T}}) Bytes() (res [Size{{$T}}]byte) {
+	{{- range $i, $c := .Coords}}
+	b{{$i}} := z.{{$c}}.Bytes()
+	copy(res[{{$i}}*{{$.BasePackageName}}.Bytes:], b{{$i}}[:])
+	{{- end}}
+	return
+}
+
+// SetBytes sets z from a big-endian byte array a0 || a1 ... as output by Bytes.
+// It returns an error if len(e) != Size{{$T}} or if one of the coordinates is not
+// canonical (greater or equal than the modulus).
+func (z *{{$T}}) SetBytes(e []byte) error {
+	if len(e) != Size{{$T}} {
+		return errors.New("invalid {{$T}} encoding")
+	}
+	var res {{$T}}
+	{{- range $i, $c := .Coords}}
+	if err := res.{{$c}}.SetBytesCanonical(e[{{$i}}*{{$.BasePackageName}}.Bytes : {{add $i 1}}*{{$.BasePackageName}}.Bytes]); err != nil {
+		return err
+	}
+	{{- end}}
+	z.Set(&res)
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler
+func (z *{{$T}}) MarshalBinary() ([]byte, error) {
+	b := z.Bytes()
+	return b[:], nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler
+func (z *{{$T}}) UnmarshalBinary(data []byte) error {
+	return z.SetBytes(data)
+}
+
+// BatchInvert{{$T}} returns a new slice with every element in a inverted.
+// It uses Montgomery batch inversion trick.
+//
+// if a[i] == 0, returns result[i] = a[i]
+func BatchInvert{{$T}}(a []{{$T}}) []{{$T}} {
+	res := make([]{{$T}}, len(a))
+	if len(a) == 0 {
+		return res
+	}
+
+	zeroes := make([]bool, len(a))
+	var accumulator {{$T}}
+	accumulator.SetOne()
+
+	for i := 0; i < len(a); i++ {
+		if a[i].IsZero() {
+			zeroes[i] = true
+			continue
+		}
+		res[i].Set(&accumulator)
+		accumulator.Mul(&accumulator, &a[i])
+	}
+
+	accumulator.Inverse(&accumulator)
+
+	for i := len(a) - 1; i >= 0; i-- {
+		if zeroes[i] {
+			continue
+		}
+		res[i].Mul(&res[i], &accumulator)
+		accumulator.Mul(&accumulator, &a[i])
+	}
+
+	return res
+}
+`
+
+// Common holds the declarations shared by all the extensions of a package
+const Common = `
+import (
+	"math/big"
+	"sync"
+)
+
+var bigIntPool = sync.Pool{
+	New: func() interface{} {
+		return new(big.Int)
+	},
+}
+`
+
+// Doc is the package documentation of the generated extensions
+const Doc = `
+// Package extensions provides radical extensions of {{.BasePackageName}}.{{.ElementName}}:
+{{- range .Extensions}}
+//   - {{.TypeName}} = {{$.BasePackageName}}[u]/(u{{if eq .Degree 2}}²{{else}}³{{end}} - {{.RootOf}})
+{{- end}}
+//
+// These are typically used to sample challenges with enough entropy in
+// protocols (such as STARKs) instantiated over a small field.
+//
+// The API is similar to the one of {{.BasePackageName}}.{{.ElementName}}; elements are not in
+// constant time and should not be used with secret values.
+package extensions
+`
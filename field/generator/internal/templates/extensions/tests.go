@@ -0,0 +1,299 @@
+package extensions
+
+// Test is the test template of Extension
+const Test = `
+{{- $T := .TypeName }}
+import (
+	"math/big"
+	"testing"
+
+	"{{.BasePackagePath}}"
+
+	"github.com/leanovate/gopter"
+	"github.com/leanovate/gopter/prop"
+)
+
+// gen{{$T}} generates a random {{$T}} element
+func gen{{$T}}() gopter.Gen {
+	return func(genParams *gopter.GenParameters) *gopter.GenResult {
+		var e {{$T}}
+		if _, err := e.SetRandom(); err != nil {
+			panic(err)
+		}
+		return gopter.NewGenResult(e, gopter.NoShrinker)
+	}
+}
+
+func Test{{$T}}ReceiverIsOperand(t *testing.T) {
+	t.Parallel()
+	parameters := gopter.DefaultTestParameters()
+	if testing.Short() {
+		parameters.MinSuccessfulTests = nbFuzzShort
+	} else {
+		parameters.MinSuccessfulTests = nbFuzz
+	}
+
+	properties := gopter.NewProperties(parameters)
+
+	properties.Property("[{{$T}}] Having the receiver as operand (addition) should output the same result", prop.ForAll(
+		func(a, b {{$T}}) bool {
+			var c, d {{$T}}
+			d.Set(&a)
+			c.Add(&a, &b)
+			a.Add(&a, &b)
+			b.Add(&d, &b)
+			return a.Equal(&b) && a.Equal(&c) && b.Equal(&c)
+		},
+		gen{{$T}}(),
+		gen{{$T}}(),
+	))
+
+	properties.Property("[{{$T}}] Having the receiver as operand (mul) should output the same result", prop.ForAll(
+		func(a, b {{$T}}) bool {
+			var c, d {{$T}}
+			d.Set(&a)
+			c.Mul(&a, &b)
+			a.Mul(&a, &b)
+			b.Mul(&d, &b)
+			return a.Equal(&b) && a.Equal(&c) && b.Equal(&c)
+		},
+		gen{{$T}}(),
+		gen{{$T}}(),
+	))
+
+	properties.Property("[{{$T}}] Having the receiver as operand (square) should output the same result", prop.ForAll(
+		func(a {{$T}}) bool {
+			var b {{$T}}
+			b.Square(&a)
+			a.Square(&a)
+			return a.Equal(&b)
+		},
+		gen{{$T}}(),
+	))
+
+	properties.Property("[{{$T}}] Having the receiver as operand (inverse) should output the same result", prop.ForAll(
+		func(a {{$T}}) bool {
+			var b {{$T}}
+			b.Inverse(&a)
+			a.Inverse(&a)
+			return a.Equal(&b)
+		},
+		gen{{$T}}(),
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+}
+
+func Test{{$T}}Ops(t *testing.T) {
+	t.Parallel()
+	parameters := gopter.DefaultTestParameters()
+	if testing.Short() {
+		parameters.MinSuccessfulTests = nbFuzzShort
+	} else {
+		parameters.MinSuccessfulTests = nbFuzz
+	}
+
+	properties := gopter.NewProperties(parameters)
+
+	properties.Property("[{{$T}}] sub & add should leave an element invariant", prop.ForAll(
+		func(a, b {{$T}}) bool {
+			var c {{$T}}
+			c.Set(&a)
+			c.Add(&c, &b).Sub(&c, &b)
+			return c.Equal(&a)
+		},
+		gen{{$T}}(),
+		gen{{$T}}(),
+	))
+
+	properties.Property("[{{$T}}] mul & inverse should leave an element invariant", prop.ForAll(
+		func(a, b {{$T}}) bool {
+			var c, d {{$T}}
+			d.Inverse(&b)
+			c.Set(&a)
+			c.Mul(&c, &b).Mul(&c, &d)
+			return c.Equal(&a)
+		},
+		gen{{$T}}(),
+		gen{{$T}}(),
+	))
+
+	properties.Property("[{{$T}}] mul should be associative and commutative", prop.ForAll(
+		func(a, b, c {{$T}}) bool {
+			var ab, bc, abc1, abc2, ba {{$T}}
+			ab.Mul(&a, &b)
+			ba.Mul(&b, &a)
+			bc.Mul(&b, &c)
+			abc1.Mul(&ab, &c)
+			abc2.Mul(&a, &bc)
+			return ab.Equal(&ba) && abc1.Equal(&abc2)
+		},
+		gen{{$T}}(),
+		gen{{$T}}(),
+		gen{{$T}}(),
+	))
+
+	properties.Property("[{{$T}}] square and mul should output the same result", prop.ForAll(
+		func(a {{$T}}) bool {
+			var b, c {{$T}}
+			b.Mul(&a, &a)
+			c.Square(&a)
+			return b.Equal(&c)
+		},
+		gen{{$T}}(),
+	))
+
+	properties.Property("[{{$T}}] Double and add twice should output the same result", prop.ForAll(
+		func(a {{$T}}) bool {
+			var b, c {{$T}}
+			b.Add(&a, &a)
+			c.Double(&a)
+			return b.Equal(&c)
+		},
+		gen{{$T}}(),
+	))
+
+	properties.Property("[{{$T}}] Neg twice should leave an element invariant", prop.ForAll(
+		func(a {{$T}}) bool {
+			var b {{$T}}
+			b.Neg(&a).Neg(&b)
+			return a.Equal(&b)
+		},
+		gen{{$T}}(),
+	))
+
+	properties.Property("[{{$T}}] MulByElement should be consistent with Mul", prop.ForAll(
+		func(a {{$T}}) bool {
+			var y {{.BaseElement}}
+			y.SetRandom()
+			var b, c {{$T}}
+			c.A0.Set(&y)
+			b.MulByElement(&a, &y)
+			c.Mul(&a, &c)
+			return b.Equal(&c)
+		},
+		gen{{$T}}(),
+	))
+
+	properties.Property("[{{$T}}] Frobenius should be equal to x^p", prop.ForAll(
+		func(a {{$T}}) bool {
+			var b, c {{$T}}
+			b.Frobenius(&a)
+			c.Exp(a, {{.BasePackageName}}.Modulus())
+			return b.Equal(&c)
+		},
+		gen{{$T}}(),
+	))
+
+	properties.Property("[{{$T}}] Frobenius^{{.Degree}} should leave an element invariant", prop.ForAll(
+		func(a {{$T}}) bool {
+			var b {{$T}}
+			b.Set(&a)
+			for i := 0; i < {{.Degree}}; i++ {
+				b.Frobenius(&b)
+			}
+			return a.Equal(&b)
+		},
+		gen{{$T}}(),
+	))
+
+	properties.Property("[{{$T}}] the norm should be the product of the conjugates", prop.ForAll(
+		func(a {{$T}}) bool {
+			var b, c {{$T}}
+			c.Set(&a)
+			b.Set(&a)
+			for i := 1; i < {{.Degree}}; i++ {
+				b.Frobenius(&b)
+				c.Mul(&c, &b)
+			}
+			norm := a.Norm()
+			var n {{$T}}
+			n.A0.Set(&norm)
+			return c.Equal(&n)
+		},
+		gen{{$T}}(),
+	))
+
+	properties.Property("[{{$T}}] Exp should be consistent with Mul and Inverse", prop.ForAll(
+		func(a {{$T}}) bool {
+			var b, c, d {{$T}}
+			b.Exp(a, big.NewInt(5))
+			c.Square(&a).Square(&c).Mul(&c, &a)
+			d.Exp(a, big.NewInt(-5)).Mul(&d, &b)
+			return b.Equal(&c) && d.IsOne()
+		},
+		gen{{$T}}(),
+	))
+
+	properties.Property("[{{$T}}] BatchInvert should output the same result as Inverse", prop.ForAll(
+		func(a, b {{$T}}) bool {
+			var zero {{$T}}
+			res := BatchInvert{{$T}}([]{{$T}}{a, zero, b})
+			var aInv, bInv {{$T}}
+			aInv.Inverse(&a)
+			bInv.Inverse(&b)
+			return res[0].Equal(&aInv) && res[1].IsZero() && res[2].Equal(&bInv)
+		},
+		gen{{$T}}(),
+		gen{{$T}}(),
+	))
+
+	properties.Property("[{{$T}}] SetBytes(Bytes()) should stay the same", prop.ForAll(
+		func(a {{$T}}) bool {
+			var b {{$T}}
+			buf, err := a.MarshalBinary()
+			if err != nil {
+				return false
+			}
+			if err := b.UnmarshalBinary(buf); err != nil {
+				return false
+			}
+			return a.Equal(&b)
+		},
+		gen{{$T}}(),
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+}
+
+func Test{{$T}}SetBytesNonCanonical(t *testing.T) {
+	buf := make([]byte, Size{{$T}})
+	for i := range buf[:{{.BasePackageName}}.Bytes] {
+		buf[i] = 0xff
+	}
+	var a {{$T}}
+	if err := a.SetBytes(buf); err == nil {
+		t.Fatal("SetBytes should reject non canonical encodings")
+	}
+	if err := a.SetBytes(buf[1:]); err == nil {
+		t.Fatal("SetBytes should reject short buffers")
+	}
+}
+
+func Benchmark{{$T}}Mul(b *testing.B) {
+	var x, y {{$T}}
+	x.SetRandom()
+	y.SetRandom()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		x.Mul(&x, &y)
+	}
+}
+
+func Benchmark{{$T}}Inverse(b *testing.B) {
+	var x {{$T}}
+	x.SetRandom()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		x.Inverse(&x)
+	}
+}
+`
+
+// CommonTest holds the test declarations shared by all the extensions of a package
+const CommonTest = `
+const (
+	nbFuzzShort = 10
+	nbFuzz      = 50
+)
+`
@@ -0,0 +1,26 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+// Package extensions provides radical extensions of goldilocks.Element:
+//   - E2 = goldilocks[u]/(u² - 7)
+//   - E3 = goldilocks[u]/(u³ - 7)
+//
+// These are typically used to sample challenges with enough entropy in
+// protocols (such as STARKs) instantiated over a small field.
+//
+// The API is similar to the one of goldilocks.Element; elements are not in
+// constant time and should not be used with secret values.
+package extensions
@@ -0,0 +1,325 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package extensions
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/field/goldilocks"
+)
+
+// E2 is a degree 2 extension of goldilocks.Element: goldilocks[u]/(u² - 7).
+//
+// An element is represented as A0 + A1⋅u.
+type E2 struct {
+	A0 goldilocks.Element
+	A1 goldilocks.Element
+}
+
+// SizeE2 is the number of bytes needed to represent an element of E2
+const SizeE2 = 2 * goldilocks.Bytes
+
+var (
+	// nonResidueE2 is α such that u² = α
+	nonResidueE2 goldilocks.Element
+
+	// frobeniusCoefficientsE2[i] = α^(i⋅(p-1)/2), such that φ(u^i) = frobeniusCoefficientsE2[i]⋅u^i
+	frobeniusCoefficientsE2 [2]goldilocks.Element
+)
+
+func init() {
+	nonResidueE2.SetInt64(7)
+	if _, err := frobeniusCoefficientsE2[0].SetString("1"); err != nil {
+		panic(err)
+	}
+	if _, err := frobeniusCoefficientsE2[1].SetString("18446744069414584320"); err != nil {
+		panic(err)
+	}
+}
+
+// mulByNonResidueE2 sets z to α⋅x
+func mulByNonResidueE2(z, x *goldilocks.Element) {
+	z.Mul(x, &nonResidueE2)
+}
+
+// Equal returns true if z equals x, false otherwise
+func (z *E2) Equal(x *E2) bool {
+	return z.A0.Equal(&x.A0) && z.A1.Equal(&x.A1)
+}
+
+// SetZero sets an E2 elmt to zero
+func (z *E2) SetZero() *E2 {
+	z.A0.SetZero()
+	z.A1.SetZero()
+	return z
+}
+
+// SetOne sets z to 1 in Montgomery form and returns z
+func (z *E2) SetOne() *E2 {
+	z.A0.SetOne()
+	z.A1.SetZero()
+	return z
+}
+
+// Set sets an E2 from x
+func (z *E2) Set(x *E2) *E2 {
+	*z = *x
+	return z
+}
+
+// SetRandom sets all the coordinates of z to random values
+func (z *E2) SetRandom() (*E2, error) {
+	if _, err := z.A0.SetRandom(); err != nil {
+		return nil, err
+	}
+	if _, err := z.A1.SetRandom(); err != nil {
+		return nil, err
+	}
+	return z, nil
+}
+
+// IsZero returns true if z is zero, false otherwise
+func (z *E2) IsZero() bool {
+	return z.A0.IsZero() && z.A1.IsZero()
+}
+
+// IsOne returns true if z is one, false otherwise
+func (z *E2) IsOne() bool {
+	return z.A0.IsOne() && z.A1.IsZero()
+}
+
+// Add adds two elements of E2
+func (z *E2) Add(x, y *E2) *E2 {
+	z.A0.Add(&x.A0, &y.A0)
+	z.A1.Add(&x.A1, &y.A1)
+	return z
+}
+
+// Sub subtracts two elements of E2
+func (z *E2) Sub(x, y *E2) *E2 {
+	z.A0.Sub(&x.A0, &y.A0)
+	z.A1.Sub(&x.A1, &y.A1)
+	return z
+}
+
+// Double doubles an E2 element
+func (z *E2) Double(x *E2) *E2 {
+	z.A0.Double(&x.A0)
+	z.A1.Double(&x.A1)
+	return z
+}
+
+// Neg negates an E2 element
+func (z *E2) Neg(x *E2) *E2 {
+	z.A0.Neg(&x.A0)
+	z.A1.Neg(&x.A1)
+	return z
+}
+
+// MulByElement multiplies an element in E2 by an element in goldilocks
+func (z *E2) MulByElement(x *E2, y *goldilocks.Element) *E2 {
+	var yCopy goldilocks.Element
+	yCopy.Set(y)
+	z.A0.Mul(&x.A0, &yCopy)
+	z.A1.Mul(&x.A1, &yCopy)
+	return z
+}
+
+// Mul sets z to the E2-product of x,y, returns z
+func (z *E2) Mul(x, y *E2) *E2 {
+	// Karatsuba
+	var a, b, c goldilocks.Element
+	a.Add(&x.A0, &x.A1)
+	b.Add(&y.A0, &y.A1)
+	a.Mul(&a, &b)
+	b.Mul(&x.A0, &y.A0)
+	c.Mul(&x.A1, &y.A1)
+	z.A1.Sub(&a, &b).Sub(&z.A1, &c)
+	mulByNonResidueE2(&c, &c)
+	z.A0.Add(&b, &c)
+	return z
+}
+
+// Square sets z to the E2-product of x,x returns z
+func (z *E2) Square(x *E2) *E2 {
+	// (a0 + a1⋅u)² = a0² + α⋅a1² + 2⋅a0⋅a1⋅u
+	var a, b, c goldilocks.Element
+	a.Square(&x.A0)
+	b.Square(&x.A1)
+	mulByNonResidueE2(&b, &b)
+	c.Mul(&x.A0, &x.A1).Double(&c)
+	z.A0.Add(&a, &b)
+	z.A1.Set(&c)
+	return z
+}
+
+// Conjugate sets z to a0 - a1⋅u, the image of x by the non trivial automorphism of E2
+func (z *E2) Conjugate(x *E2) *E2 {
+	z.A0.Set(&x.A0)
+	z.A1.Neg(&x.A1)
+	return z
+}
+
+// Norm returns the norm of x, that is a0² - α⋅a1²
+func (x *E2) Norm() goldilocks.Element {
+	var a, b goldilocks.Element
+	a.Square(&x.A0)
+	b.Square(&x.A1)
+	mulByNonResidueE2(&b, &b)
+	a.Sub(&a, &b)
+	return a
+}
+
+// Inverse sets z to the E2-inverse of x, returns z
+//
+// if x == 0, sets and returns z = x
+func (z *E2) Inverse(x *E2) *E2 {
+	// 1/(a0 + a1⋅u) = (a0 - a1⋅u)/(a0² - α⋅a1²)
+	norm := x.Norm()
+	norm.Inverse(&norm)
+	z.Conjugate(x)
+	return z.MulByElement(z, &norm)
+}
+
+// Div divides an element in E2 by an element in E2
+func (z *E2) Div(x *E2, y *E2) *E2 {
+	var r E2
+	r.Inverse(y).Mul(x, &r)
+	return z.Set(&r)
+}
+
+// Frobenius sets z to x^p, returns z
+func (z *E2) Frobenius(x *E2) *E2 {
+	// φ(a0 + a1⋅u + ...) = a0 + a1⋅u^p + ... and u^p = α^((p-1)/2)⋅u
+	z.A0.Set(&x.A0)
+	z.A1.Mul(&x.A1, &frobeniusCoefficientsE2[1])
+	return z
+}
+
+// Exp sets z=xᵏ (mod q) and returns it
+func (z *E2) Exp(x E2, k *big.Int) *E2 {
+	if k.IsUint64() && k.Uint64() == 0 {
+		return z.SetOne()
+	}
+
+	e := k
+	if k.Sign() == -1 {
+		// negative k, we invert
+		// if k < 0: xᵏ (mod q) == (x⁻¹)ᵏ (mod q)
+		x.Inverse(&x)
+
+		// we negate k in a temp big.Int since
+		// Int.Bit(_) of k and -k is different
+		e = bigIntPool.Get().(*big.Int)
+		defer bigIntPool.Put(e)
+		e.Neg(k)
+	}
+
+	z.SetOne()
+	b := e.Bytes()
+	for i := 0; i < len(b); i++ {
+		w := b[i]
+		for j := 0; j < 8; j++ {
+			z.Square(z)
+			if (w & (0b10000000 >> j)) != 0 {
+				z.Mul(z, &x)
+			}
+		}
+	}
+
+	return z
+}
+
+// String implements Stringer interface for fancy printing
+func (z *E2) String() string {
+	return z.A0.String() + "+(" + z.A1.String() + ")*u"
+}
+
+// Bytes returns the value of z as a big-endian byte array, a0 || a1 ...
+func (z *E2) Bytes() (res [SizeE2]byte) {
+	b0 := z.A0.Bytes()
+	copy(res[0*goldilocks.Bytes:], b0[:])
+	b1 := z.A1.Bytes()
+	copy(res[1*goldilocks.Bytes:], b1[:])
+	return
+}
+
+// SetBytes sets z from a big-endian byte array a0 || a1 ... as output by Bytes.
+// It returns an error if len(e) != SizeE2 or if one of the coordinates is not
+// canonical (greater or equal than the modulus).
+func (z *E2) SetBytes(e []byte) error {
+	if len(e) != SizeE2 {
+		return errors.New("invalid E2 encoding")
+	}
+	var res E2
+	if err := res.A0.SetBytesCanonical(e[0*goldilocks.Bytes : 1*goldilocks.Bytes]); err != nil {
+		return err
+	}
+	if err := res.A1.SetBytesCanonical(e[1*goldilocks.Bytes : 2*goldilocks.Bytes]); err != nil {
+		return err
+	}
+	z.Set(&res)
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler
+func (z *E2) MarshalBinary() ([]byte, error) {
+	b := z.Bytes()
+	return b[:], nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler
+func (z *E2) UnmarshalBinary(data []byte) error {
+	return z.SetBytes(data)
+}
+
+// BatchInvertE2 returns a new slice with every element in a inverted.
+// It uses Montgomery batch inversion trick.
+//
+// if a[i] == 0, returns result[i] = a[i]
+func BatchInvertE2(a []E2) []E2 {
+	res := make([]E2, len(a))
+	if len(a) == 0 {
+		return res
+	}
+
+	zeroes := make([]bool, len(a))
+	var accumulator E2
+	accumulator.SetOne()
+
+	for i := 0; i < len(a); i++ {
+		if a[i].IsZero() {
+			zeroes[i] = true
+			continue
+		}
+		res[i].Set(&accumulator)
+		accumulator.Mul(&accumulator, &a[i])
+	}
+
+	accumulator.Inverse(&accumulator)
+
+	for i := len(a) - 1; i >= 0; i-- {
+		if zeroes[i] {
+			continue
+		}
+		res[i].Mul(&res[i], &accumulator)
+		accumulator.Mul(&accumulator, &a[i])
+	}
+
+	return res
+}
@@ -0,0 +1,303 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package extensions
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/field/goldilocks"
+
+	"github.com/leanovate/gopter"
+	"github.com/leanovate/gopter/prop"
+)
+
+// genE2 generates a random E2 element
+func genE2() gopter.Gen {
+	return func(genParams *gopter.GenParameters) *gopter.GenResult {
+		var e E2
+		if _, err := e.SetRandom(); err != nil {
+			panic(err)
+		}
+		return gopter.NewGenResult(e, gopter.NoShrinker)
+	}
+}
+
+func TestE2ReceiverIsOperand(t *testing.T) {
+	t.Parallel()
+	parameters := gopter.DefaultTestParameters()
+	if testing.Short() {
+		parameters.MinSuccessfulTests = nbFuzzShort
+	} else {
+		parameters.MinSuccessfulTests = nbFuzz
+	}
+
+	properties := gopter.NewProperties(parameters)
+
+	properties.Property("[E2] Having the receiver as operand (addition) should output the same result", prop.ForAll(
+		func(a, b E2) bool {
+			var c, d E2
+			d.Set(&a)
+			c.Add(&a, &b)
+			a.Add(&a, &b)
+			b.Add(&d, &b)
+			return a.Equal(&b) && a.Equal(&c) && b.Equal(&c)
+		},
+		genE2(),
+		genE2(),
+	))
+
+	properties.Property("[E2] Having the receiver as operand (mul) should output the same result", prop.ForAll(
+		func(a, b E2) bool {
+			var c, d E2
+			d.Set(&a)
+			c.Mul(&a, &b)
+			a.Mul(&a, &b)
+			b.Mul(&d, &b)
+			return a.Equal(&b) && a.Equal(&c) && b.Equal(&c)
+		},
+		genE2(),
+		genE2(),
+	))
+
+	properties.Property("[E2] Having the receiver as operand (square) should output the same result", prop.ForAll(
+		func(a E2) bool {
+			var b E2
+			b.Square(&a)
+			a.Square(&a)
+			return a.Equal(&b)
+		},
+		genE2(),
+	))
+
+	properties.Property("[E2] Having the receiver as operand (inverse) should output the same result", prop.ForAll(
+		func(a E2) bool {
+			var b E2
+			b.Inverse(&a)
+			a.Inverse(&a)
+			return a.Equal(&b)
+		},
+		genE2(),
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+}
+
+func TestE2Ops(t *testing.T) {
+	t.Parallel()
+	parameters := gopter.DefaultTestParameters()
+	if testing.Short() {
+		parameters.MinSuccessfulTests = nbFuzzShort
+	} else {
+		parameters.MinSuccessfulTests = nbFuzz
+	}
+
+	properties := gopter.NewProperties(parameters)
+
+	properties.Property("[E2] sub & add should leave an element invariant", prop.ForAll(
+		func(a, b E2) bool {
+			var c E2
+			c.Set(&a)
+			c.Add(&c, &b).Sub(&c, &b)
+			return c.Equal(&a)
+		},
+		genE2(),
+		genE2(),
+	))
+
+	properties.Property("[E2] mul & inverse should leave an element invariant", prop.ForAll(
+		func(a, b E2) bool {
+			var c, d E2
+			d.Inverse(&b)
+			c.Set(&a)
+			c.Mul(&c, &b).Mul(&c, &d)
+			return c.Equal(&a)
+		},
+		genE2(),
+		genE2(),
+	))
+
+	properties.Property("[E2] mul should be associative and commutative", prop.ForAll(
+		func(a, b, c E2) bool {
+			var ab, bc, abc1, abc2, ba E2
+			ab.Mul(&a, &b)
+			ba.Mul(&b, &a)
+			bc.Mul(&b, &c)
+			abc1.Mul(&ab, &c)
+			abc2.Mul(&a, &bc)
+			return ab.Equal(&ba) && abc1.Equal(&abc2)
+		},
+		genE2(),
+		genE2(),
+		genE2(),
+	))
+
+	properties.Property("[E2] square and mul should output the same result", prop.ForAll(
+		func(a E2) bool {
+			var b, c E2
+			b.Mul(&a, &a)
+			c.Square(&a)
+			return b.Equal(&c)
+		},
+		genE2(),
+	))
+
+	properties.Property("[E2] Double and add twice should output the same result", prop.ForAll(
+		func(a E2) bool {
+			var b, c E2
+			b.Add(&a, &a)
+			c.Double(&a)
+			return b.Equal(&c)
+		},
+		genE2(),
+	))
+
+	properties.Property("[E2] Neg twice should leave an element invariant", prop.ForAll(
+		func(a E2) bool {
+			var b E2
+			b.Neg(&a).Neg(&b)
+			return a.Equal(&b)
+		},
+		genE2(),
+	))
+
+	properties.Property("[E2] MulByElement should be consistent with Mul", prop.ForAll(
+		func(a E2) bool {
+			var y goldilocks.Element
+			y.SetRandom()
+			var b, c E2
+			c.A0.Set(&y)
+			b.MulByElement(&a, &y)
+			c.Mul(&a, &c)
+			return b.Equal(&c)
+		},
+		genE2(),
+	))
+
+	properties.Property("[E2] Frobenius should be equal to x^p", prop.ForAll(
+		func(a E2) bool {
+			var b, c E2
+			b.Frobenius(&a)
+			c.Exp(a, goldilocks.Modulus())
+			return b.Equal(&c)
+		},
+		genE2(),
+	))
+
+	properties.Property("[E2] Frobenius^2 should leave an element invariant", prop.ForAll(
+		func(a E2) bool {
+			var b E2
+			b.Set(&a)
+			for i := 0; i < 2; i++ {
+				b.Frobenius(&b)
+			}
+			return a.Equal(&b)
+		},
+		genE2(),
+	))
+
+	properties.Property("[E2] the norm should be the product of the conjugates", prop.ForAll(
+		func(a E2) bool {
+			var b, c E2
+			c.Set(&a)
+			b.Set(&a)
+			for i := 1; i < 2; i++ {
+				b.Frobenius(&b)
+				c.Mul(&c, &b)
+			}
+			norm := a.Norm()
+			var n E2
+			n.A0.Set(&norm)
+			return c.Equal(&n)
+		},
+		genE2(),
+	))
+
+	properties.Property("[E2] Exp should be consistent with Mul and Inverse", prop.ForAll(
+		func(a E2) bool {
+			var b, c, d E2
+			b.Exp(a, big.NewInt(5))
+			c.Square(&a).Square(&c).Mul(&c, &a)
+			d.Exp(a, big.NewInt(-5)).Mul(&d, &b)
+			return b.Equal(&c) && d.IsOne()
+		},
+		genE2(),
+	))
+
+	properties.Property("[E2] BatchInvert should output the same result as Inverse", prop.ForAll(
+		func(a, b E2) bool {
+			var zero E2
+			res := BatchInvertE2([]E2{a, zero, b})
+			var aInv, bInv E2
+			aInv.Inverse(&a)
+			bInv.Inverse(&b)
+			return res[0].Equal(&aInv) && res[1].IsZero() && res[2].Equal(&bInv)
+		},
+		genE2(),
+		genE2(),
+	))
+
+	properties.Property("[E2] SetBytes(Bytes()) should stay the same", prop.ForAll(
+		func(a E2) bool {
+			var b E2
+			buf, err := a.MarshalBinary()
+			if err != nil {
+				return false
+			}
+			if err := b.UnmarshalBinary(buf); err != nil {
+				return false
+			}
+			return a.Equal(&b)
+		},
+		genE2(),
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+}
+
+func TestE2SetBytesNonCanonical(t *testing.T) {
+	buf := make([]byte, SizeE2)
+	for i := range buf[:goldilocks.Bytes] {
+		buf[i] = 0xff
+	}
+	var a E2
+	if err := a.SetBytes(buf); err == nil {
+		t.Fatal("SetBytes should reject non canonical encodings")
+	}
+	if err := a.SetBytes(buf[1:]); err == nil {
+		t.Fatal("SetBytes should reject short buffers")
+	}
+}
+
+func BenchmarkE2Mul(b *testing.B) {
+	var x, y E2
+	x.SetRandom()
+	y.SetRandom()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		x.Mul(&x, &y)
+	}
+}
+
+func BenchmarkE2Inverse(b *testing.B) {
+	var x E2
+	x.SetRandom()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		x.Inverse(&x)
+	}
+}
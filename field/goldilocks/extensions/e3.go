@@ -0,0 +1,408 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package extensions
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/field/goldilocks"
+)
+
+// E3 is a degree 3 extension of goldilocks.Element: goldilocks[u]/(u³ - 7).
+//
+// An element is represented as A0 + A1⋅u + A2⋅u².
+type E3 struct {
+	A0 goldilocks.Element
+	A1 goldilocks.Element
+	A2 goldilocks.Element
+}
+
+// SizeE3 is the number of bytes needed to represent an element of E3
+const SizeE3 = 3 * goldilocks.Bytes
+
+var (
+	// nonResidueE3 is α such that u³ = α
+	nonResidueE3 goldilocks.Element
+
+	// frobeniusCoefficientsE3[i] = α^(i⋅(p-1)/3), such that φ(u^i) = frobeniusCoefficientsE3[i]⋅u^i
+	frobeniusCoefficientsE3 [3]goldilocks.Element
+)
+
+func init() {
+	nonResidueE3.SetInt64(7)
+	if _, err := frobeniusCoefficientsE3[0].SetString("1"); err != nil {
+		panic(err)
+	}
+	if _, err := frobeniusCoefficientsE3[1].SetString("18446744065119617025"); err != nil {
+		panic(err)
+	}
+	if _, err := frobeniusCoefficientsE3[2].SetString("4294967295"); err != nil {
+		panic(err)
+	}
+}
+
+// mulByNonResidueE3 sets z to α⋅x
+func mulByNonResidueE3(z, x *goldilocks.Element) {
+	z.Mul(x, &nonResidueE3)
+}
+
+// Equal returns true if z equals x, false otherwise
+func (z *E3) Equal(x *E3) bool {
+	return z.A0.Equal(&x.A0) && z.A1.Equal(&x.A1) && z.A2.Equal(&x.A2)
+}
+
+// SetZero sets an E3 elmt to zero
+func (z *E3) SetZero() *E3 {
+	z.A0.SetZero()
+	z.A1.SetZero()
+	z.A2.SetZero()
+	return z
+}
+
+// SetOne sets z to 1 in Montgomery form and returns z
+func (z *E3) SetOne() *E3 {
+	z.A0.SetOne()
+	z.A1.SetZero()
+	z.A2.SetZero()
+	return z
+}
+
+// Set sets an E3 from x
+func (z *E3) Set(x *E3) *E3 {
+	*z = *x
+	return z
+}
+
+// SetRandom sets all the coordinates of z to random values
+func (z *E3) SetRandom() (*E3, error) {
+	if _, err := z.A0.SetRandom(); err != nil {
+		return nil, err
+	}
+	if _, err := z.A1.SetRandom(); err != nil {
+		return nil, err
+	}
+	if _, err := z.A2.SetRandom(); err != nil {
+		return nil, err
+	}
+	return z, nil
+}
+
+// IsZero returns true if z is zero, false otherwise
+func (z *E3) IsZero() bool {
+	return z.A0.IsZero() && z.A1.IsZero() && z.A2.IsZero()
+}
+
+// IsOne returns true if z is one, false otherwise
+func (z *E3) IsOne() bool {
+	return z.A0.IsOne() && z.A1.IsZero() && z.A2.IsZero()
+}
+
+// Add adds two elements of E3
+func (z *E3) Add(x, y *E3) *E3 {
+	z.A0.Add(&x.A0, &y.A0)
+	z.A1.Add(&x.A1, &y.A1)
+	z.A2.Add(&x.A2, &y.A2)
+	return z
+}
+
+// Sub subtracts two elements of E3
+func (z *E3) Sub(x, y *E3) *E3 {
+	z.A0.Sub(&x.A0, &y.A0)
+	z.A1.Sub(&x.A1, &y.A1)
+	z.A2.Sub(&x.A2, &y.A2)
+	return z
+}
+
+// Double doubles an E3 element
+func (z *E3) Double(x *E3) *E3 {
+	z.A0.Double(&x.A0)
+	z.A1.Double(&x.A1)
+	z.A2.Double(&x.A2)
+	return z
+}
+
+// Neg negates an E3 element
+func (z *E3) Neg(x *E3) *E3 {
+	z.A0.Neg(&x.A0)
+	z.A1.Neg(&x.A1)
+	z.A2.Neg(&x.A2)
+	return z
+}
+
+// MulByElement multiplies an element in E3 by an element in goldilocks
+func (z *E3) MulByElement(x *E3, y *goldilocks.Element) *E3 {
+	var yCopy goldilocks.Element
+	yCopy.Set(y)
+	z.A0.Mul(&x.A0, &yCopy)
+	z.A1.Mul(&x.A1, &yCopy)
+	z.A2.Mul(&x.A2, &yCopy)
+	return z
+}
+
+// Mul sets z to the E3-product of x,y, returns z
+func (z *E3) Mul(x, y *E3) *E3 {
+	// Algorithm 13 from https://eprint.iacr.org/2010/354.pdf
+	var t0, t1, t2, c0, c1, c2, tmp goldilocks.Element
+	t0.Mul(&x.A0, &y.A0)
+	t1.Mul(&x.A1, &y.A1)
+	t2.Mul(&x.A2, &y.A2)
+
+	// c0 = t0 + α⋅((a1+a2)(b1+b2) - t1 - t2)
+	c0.Add(&x.A1, &x.A2)
+	tmp.Add(&y.A1, &y.A2)
+	c0.Mul(&c0, &tmp).Sub(&c0, &t1).Sub(&c0, &t2)
+	mulByNonResidueE3(&c0, &c0)
+	c0.Add(&c0, &t0)
+
+	// c1 = (a0+a1)(b0+b1) - t0 - t1 + α⋅t2
+	c1.Add(&x.A0, &x.A1)
+	tmp.Add(&y.A0, &y.A1)
+	c1.Mul(&c1, &tmp).Sub(&c1, &t0).Sub(&c1, &t1)
+	mulByNonResidueE3(&tmp, &t2)
+	c1.Add(&c1, &tmp)
+
+	// c2 = (a0+a2)(b0+b2) - t0 - t2 + t1
+	c2.Add(&x.A0, &x.A2)
+	tmp.Add(&y.A0, &y.A2)
+	c2.Mul(&c2, &tmp).Sub(&c2, &t0).Sub(&c2, &t2).Add(&c2, &t1)
+
+	z.A0.Set(&c0)
+	z.A1.Set(&c1)
+	z.A2.Set(&c2)
+	return z
+}
+
+// Square sets z to the E3-product of x,x returns z
+func (z *E3) Square(x *E3) *E3 {
+	// (a0 + a1⋅u + a2⋅u²)² = a0² + 2α⋅a1⋅a2 + (2⋅a0⋅a1 + α⋅a2²)⋅u + (a1² + 2⋅a0⋅a2)⋅u²
+	var c0, c1, c2, tmp goldilocks.Element
+	c0.Mul(&x.A1, &x.A2).Double(&c0)
+	mulByNonResidueE3(&c0, &c0)
+	tmp.Square(&x.A0)
+	c0.Add(&c0, &tmp)
+
+	c1.Square(&x.A2)
+	mulByNonResidueE3(&c1, &c1)
+	tmp.Mul(&x.A0, &x.A1).Double(&tmp)
+	c1.Add(&c1, &tmp)
+
+	c2.Mul(&x.A0, &x.A2).Double(&c2)
+	tmp.Square(&x.A1)
+	c2.Add(&c2, &tmp)
+
+	z.A0.Set(&c0)
+	z.A1.Set(&c1)
+	z.A2.Set(&c2)
+	return z
+}
+
+// Inverse sets z to the E3-inverse of x, returns z
+//
+// if x == 0, sets and returns z = x
+func (z *E3) Inverse(x *E3) *E3 {
+	// Algorithm 17 from https://eprint.iacr.org/2010/354.pdf
+	var t0, t1, t2, t3, t4, t5, t6, c0, c1, c2, d1, d2 goldilocks.Element
+	t0.Square(&x.A0)
+	t1.Square(&x.A1)
+	t2.Square(&x.A2)
+	t3.Mul(&x.A0, &x.A1)
+	t4.Mul(&x.A0, &x.A2)
+	t5.Mul(&x.A1, &x.A2)
+	// c0 = a0² - α⋅a1⋅a2
+	mulByNonResidueE3(&c0, &t5)
+	c0.Sub(&t0, &c0)
+	// c1 = α⋅a2² - a0⋅a1
+	mulByNonResidueE3(&c1, &t2)
+	c1.Sub(&c1, &t3)
+	// c2 = a1² - a0⋅a2
+	c2.Sub(&t1, &t4)
+	// t6 = a0⋅c0 + α⋅(a2⋅c1 + a1⋅c2)
+	t6.Mul(&x.A0, &c0)
+	d1.Mul(&x.A2, &c1)
+	d2.Mul(&x.A1, &c2)
+	d1.Add(&d1, &d2)
+	mulByNonResidueE3(&d1, &d1)
+	t6.Add(&t6, &d1)
+	t6.Inverse(&t6)
+	z.A0.Mul(&c0, &t6)
+	z.A1.Mul(&c1, &t6)
+	z.A2.Mul(&c2, &t6)
+
+	return z
+}
+
+// Norm returns the norm of x, that is the product of its conjugates x⋅φ(x)⋅φ²(x)
+func (x *E3) Norm() goldilocks.Element {
+	var c0, c1, c2, t, n goldilocks.Element
+	// c0 = a0² - α⋅a1⋅a2
+	c0.Mul(&x.A1, &x.A2)
+	mulByNonResidueE3(&c0, &c0)
+	t.Square(&x.A0)
+	c0.Sub(&t, &c0)
+	// c1 = α⋅a2² - a0⋅a1
+	c1.Square(&x.A2)
+	mulByNonResidueE3(&c1, &c1)
+	t.Mul(&x.A0, &x.A1)
+	c1.Sub(&c1, &t)
+	// c2 = a1² - a0⋅a2
+	c2.Square(&x.A1)
+	t.Mul(&x.A0, &x.A2)
+	c2.Sub(&c2, &t)
+	// n = a0⋅c0 + α⋅(a2⋅c1 + a1⋅c2)
+	n.Mul(&x.A2, &c1)
+	t.Mul(&x.A1, &c2)
+	n.Add(&n, &t)
+	mulByNonResidueE3(&n, &n)
+	t.Mul(&x.A0, &c0)
+	n.Add(&n, &t)
+	return n
+}
+
+// Div divides an element in E3 by an element in E3
+func (z *E3) Div(x *E3, y *E3) *E3 {
+	var r E3
+	r.Inverse(y).Mul(x, &r)
+	return z.Set(&r)
+}
+
+// Frobenius sets z to x^p, returns z
+func (z *E3) Frobenius(x *E3) *E3 {
+	// φ(a0 + a1⋅u + ...) = a0 + a1⋅u^p + ... and u^p = α^((p-1)/3)⋅u
+	z.A0.Set(&x.A0)
+	z.A1.Mul(&x.A1, &frobeniusCoefficientsE3[1])
+	z.A2.Mul(&x.A2, &frobeniusCoefficientsE3[2])
+	return z
+}
+
+// Exp sets z=xᵏ (mod q) and returns it
+func (z *E3) Exp(x E3, k *big.Int) *E3 {
+	if k.IsUint64() && k.Uint64() == 0 {
+		return z.SetOne()
+	}
+
+	e := k
+	if k.Sign() == -1 {
+		// negative k, we invert
+		// if k < 0: xᵏ (mod q) == (x⁻¹)ᵏ (mod q)
+		x.Inverse(&x)
+
+		// we negate k in a temp big.Int since
+		// Int.Bit(_) of k and -k is different
+		e = bigIntPool.Get().(*big.Int)
+		defer bigIntPool.Put(e)
+		e.Neg(k)
+	}
+
+	z.SetOne()
+	b := e.Bytes()
+	for i := 0; i < len(b); i++ {
+		w := b[i]
+		for j := 0; j < 8; j++ {
+			z.Square(z)
+			if (w & (0b10000000 >> j)) != 0 {
+				z.Mul(z, &x)
+			}
+		}
+	}
+
+	return z
+}
+
+// String implements Stringer interface for fancy printing
+func (z *E3) String() string {
+	return z.A0.String() + "+(" + z.A1.String() + ")*u" + "+(" + z.A2.String() + ")*u**2"
+}
+
+// Bytes returns the value of z as a big-endian byte array, a0 || a1 ...
+func (z *E3) Bytes() (res [SizeE3]byte) {
+	b0 := z.A0.Bytes()
+	copy(res[0*goldilocks.Bytes:], b0[:])
+	b1 := z.A1.Bytes()
+	copy(res[1*goldilocks.Bytes:], b1[:])
+	b2 := z.A2.Bytes()
+	copy(res[2*goldilocks.Bytes:], b2[:])
+	return
+}
+
+// SetBytes sets z from a big-endian byte array a0 || a1 ... as output by Bytes.
+// It returns an error if len(e) != SizeE3 or if one of the coordinates is not
+// canonical (greater or equal than the modulus).
+func (z *E3) SetBytes(e []byte) error {
+	if len(e) != SizeE3 {
+		return errors.New("invalid E3 encoding")
+	}
+	var res E3
+	if err := res.A0.SetBytesCanonical(e[0*goldilocks.Bytes : 1*goldilocks.Bytes]); err != nil {
+		return err
+	}
+	if err := res.A1.SetBytesCanonical(e[1*goldilocks.Bytes : 2*goldilocks.Bytes]); err != nil {
+		return err
+	}
+	if err := res.A2.SetBytesCanonical(e[2*goldilocks.Bytes : 3*goldilocks.Bytes]); err != nil {
+		return err
+	}
+	z.Set(&res)
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler
+func (z *E3) MarshalBinary() ([]byte, error) {
+	b := z.Bytes()
+	return b[:], nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler
+func (z *E3) UnmarshalBinary(data []byte) error {
+	return z.SetBytes(data)
+}
+
+// BatchInvertE3 returns a new slice with every element in a inverted.
+// It uses Montgomery batch inversion trick.
+//
+// if a[i] == 0, returns result[i] = a[i]
+func BatchInvertE3(a []E3) []E3 {
+	res := make([]E3, len(a))
+	if len(a) == 0 {
+		return res
+	}
+
+	zeroes := make([]bool, len(a))
+	var accumulator E3
+	accumulator.SetOne()
+
+	for i := 0; i < len(a); i++ {
+		if a[i].IsZero() {
+			zeroes[i] = true
+			continue
+		}
+		res[i].Set(&accumulator)
+		accumulator.Mul(&accumulator, &a[i])
+	}
+
+	accumulator.Inverse(&accumulator)
+
+	for i := len(a) - 1; i >= 0; i-- {
+		if zeroes[i] {
+			continue
+		}
+		res[i].Mul(&res[i], &accumulator)
+		accumulator.Mul(&accumulator, &a[i])
+	}
+
+	return res
+}
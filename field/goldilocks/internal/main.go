@@ -17,5 +17,11 @@ func main() {
 	if err := generator.GenerateFF(goldilocks, "..", "", ""); err != nil {
 		panic(err)
 	}
+	// extensions 𝔽p[u]/(u²-7) and 𝔽p[u]/(u³-7), 7 generates 𝔽p*
+	e2 := config.NewTower(goldilocks, 2, 7)
+	e3 := config.NewTower(goldilocks, 3, 7)
+	if err := generator.GenerateExtensions(goldilocks, "github.com/consensys/gnark-crypto/field/goldilocks", "../extensions", e2, e3); err != nil {
+		panic(err)
+	}
 	fmt.Println("successfully generated goldilocks field")
 }
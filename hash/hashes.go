@@ -24,6 +24,14 @@ import (
 	bn254 "github.com/consensys/gnark-crypto/ecc/bn254/fr/mimc"
 	bw633 "github.com/consensys/gnark-crypto/ecc/bw6-633/fr/mimc"
 	bw761 "github.com/consensys/gnark-crypto/ecc/bw6-761/fr/mimc"
+
+	poseidon2bls377 "github.com/consensys/gnark-crypto/ecc/bls12-377/fr/poseidon2"
+	poseidon2bls381 "github.com/consensys/gnark-crypto/ecc/bls12-381/fr/poseidon2"
+	poseidon2bls315 "github.com/consensys/gnark-crypto/ecc/bls24-315/fr/poseidon2"
+	poseidon2bls317 "github.com/consensys/gnark-crypto/ecc/bls24-317/fr/poseidon2"
+	poseidon2bn254 "github.com/consensys/gnark-crypto/ecc/bn254/fr/poseidon2"
+	poseidon2bw633 "github.com/consensys/gnark-crypto/ecc/bw6-633/fr/poseidon2"
+	poseidon2bw761 "github.com/consensys/gnark-crypto/ecc/bw6-761/fr/poseidon2"
 )
 
 // Hash defines an unique identifier for a hash function.
@@ -44,6 +52,20 @@ const (
 	MIMC_BLS24_317
 	// MIMC_BW6_633 is the MiMC hash function for the BW6-633 curve.
 	MIMC_BW6_633
+	// POSEIDON2_BN254 is the Poseidon2 sponge hash function for the BN254 curve.
+	POSEIDON2_BN254
+	// POSEIDON2_BLS12_381 is the Poseidon2 sponge hash function for the BLS12-381 curve.
+	POSEIDON2_BLS12_381
+	// POSEIDON2_BLS12_377 is the Poseidon2 sponge hash function for the BLS12-377 curve.
+	POSEIDON2_BLS12_377
+	// POSEIDON2_BW6_761 is the Poseidon2 sponge hash function for the BW6-761 curve.
+	POSEIDON2_BW6_761
+	// POSEIDON2_BLS24_315 is the Poseidon2 sponge hash function for the BLS24-315 curve.
+	POSEIDON2_BLS24_315
+	// POSEIDON2_BLS24_317 is the Poseidon2 sponge hash function for the BLS24-317 curve.
+	POSEIDON2_BLS24_317
+	// POSEIDON2_BW6_633 is the Poseidon2 sponge hash function for the BW6-633 curve.
+	POSEIDON2_BW6_633
 )
 
 // size of digests in bytes
@@ -55,6 +77,14 @@ var digestSize = []uint8{
 	MIMC_BLS24_315: 48,
 	MIMC_BLS24_317: 48,
 	MIMC_BW6_633:   80,
+
+	POSEIDON2_BN254:     32,
+	POSEIDON2_BLS12_381: 32,
+	POSEIDON2_BLS12_377: 32,
+	POSEIDON2_BW6_761:   48,
+	POSEIDON2_BLS24_315: 32,
+	POSEIDON2_BLS24_317: 32,
+	POSEIDON2_BW6_633:   40,
 }
 
 // New initializes the hash function.
@@ -74,8 +104,22 @@ func (m Hash) New() hash.Hash {
 		return bls317.NewMiMC()
 	case MIMC_BW6_633:
 		return bw633.NewMiMC()
+	case POSEIDON2_BN254:
+		return poseidon2bn254.NewPoseidon2()
+	case POSEIDON2_BLS12_381:
+		return poseidon2bls381.NewPoseidon2()
+	case POSEIDON2_BLS12_377:
+		return poseidon2bls377.NewPoseidon2()
+	case POSEIDON2_BW6_761:
+		return poseidon2bw761.NewPoseidon2()
+	case POSEIDON2_BLS24_315:
+		return poseidon2bls315.NewPoseidon2()
+	case POSEIDON2_BLS24_317:
+		return poseidon2bls317.NewPoseidon2()
+	case POSEIDON2_BW6_633:
+		return poseidon2bw633.NewPoseidon2()
 	default:
-		panic("Unknown mimc ID")
+		panic("Unknown hash ID")
 	}
 }
 
@@ -96,8 +140,22 @@ func (m Hash) String() string {
 		return "MIMC_BLS317"
 	case MIMC_BW6_633:
 		return "MIMC_BW633"
+	case POSEIDON2_BN254:
+		return "POSEIDON2_BN254"
+	case POSEIDON2_BLS12_381:
+		return "POSEIDON2_BLS381"
+	case POSEIDON2_BLS12_377:
+		return "POSEIDON2_BLS377"
+	case POSEIDON2_BW6_761:
+		return "POSEIDON2_BW761"
+	case POSEIDON2_BLS24_315:
+		return "POSEIDON2_BLS315"
+	case POSEIDON2_BLS24_317:
+		return "POSEIDON2_BLS317"
+	case POSEIDON2_BW6_633:
+		return "POSEIDON2_BW633"
 	default:
-		panic("Unknown mimc ID")
+		panic("Unknown hash ID")
 	}
 }
 
@@ -0,0 +1,59 @@
+package bls
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/consensys/bavard"
+	"github.com/consensys/gnark-crypto/internal/generator/config"
+)
+
+// blsConfig describes one variant of the BLS signature scheme on a curve
+type blsConfig struct {
+	config.Curve
+	PkGroup, SigGroup string // groups of the public keys and of the signatures ("G1" or "G2")
+	CiphersuiteID     string // hash-to-curve suite, e.g. BLS12381G2_XMD:SHA-256_SSWU_RO_
+}
+
+func Generate(conf config.Curve, baseDir string, bgen *bavard.BatchGenerator) error {
+
+	curveID := strings.ToUpper(strings.ReplaceAll(conf.Name, "-", ""))
+
+	variants := []struct {
+		pkg               string
+		pkGroup, sigGroup string
+		hashSuite         config.HashSuite
+	}{
+		{"minpk", "G1", "G2", conf.HashE2},
+		{"minsig", "G2", "G1", conf.HashE1},
+	}
+
+	for _, v := range variants {
+		mapping := config.SSWU
+		if _, ok := v.hashSuite.(*config.HashSuiteSvdw); ok {
+			mapping = config.SVDW
+		}
+
+		blsConf := blsConfig{
+			Curve:         conf,
+			PkGroup:       v.pkGroup,
+			SigGroup:      v.sigGroup,
+			CiphersuiteID: curveID + v.sigGroup + "_XMD:SHA-256_" + string(mapping) + "_RO_",
+		}
+		blsConf.Package = v.pkg
+		dir := filepath.Join(baseDir, v.pkg)
+
+		entries := []bavard.Entry{
+			{File: filepath.Join(dir, "doc.go"), Templates: []string{"doc.go.tmpl"}},
+			{File: filepath.Join(dir, "bls.go"), Templates: []string{"bls.go.tmpl"}},
+			{File: filepath.Join(dir, "bls_test.go"), Templates: []string{"bls.test.go.tmpl"}},
+			{File: filepath.Join(dir, "marshal.go"), Templates: []string{"marshal.go.tmpl"}},
+			{File: filepath.Join(dir, "marshal_test.go"), Templates: []string{"marshal.test.go.tmpl"}},
+		}
+		if err := bgen.Generate(blsConf, blsConf.Package, "./bls/template", entries...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
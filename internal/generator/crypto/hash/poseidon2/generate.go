@@ -7,14 +7,50 @@ import (
 	"github.com/consensys/gnark-crypto/internal/generator/config"
 )
 
+// widths are the widths of the state supported by the sponge
+var widths = []int{2, 3, 4, 8, 12, 16, 20, 24}
+
+// poseidon2Config is the data of the poseidon2 templates
+type poseidon2Config struct {
+	config.Curve
+	SBoxDegree int      // degree d of the sBox x -> x^d
+	Rounds     []rounds // numbers of rounds of the supported widths
+}
+
+type rounds struct {
+	Width, NbFullRounds, NbPartialRounds int
+}
+
 func Generate(conf config.Curve, baseDir string, bgen *bavard.BatchGenerator) error {
 
 	conf.Package = "poseidon2"
+	p2Conf := poseidon2Config{Curve: conf, SBoxDegree: sBoxDegree(conf)}
+	for _, t := range widths {
+		rf, rp := roundNumbers(conf.FrInfo.Modulus(), t, p2Conf.SBoxDegree)
+		p2Conf.Rounds = append(p2Conf.Rounds, rounds{Width: t, NbFullRounds: rf, NbPartialRounds: rp})
+	}
+
 	entries := []bavard.Entry{
 		{File: filepath.Join(baseDir, "poseidon2.go"), Templates: []string{"poseidon2.go.tmpl"}},
+		{File: filepath.Join(baseDir, "matrix.go"), Templates: []string{"matrix.go.tmpl"}},
+		{File: filepath.Join(baseDir, "hash.go"), Templates: []string{"hash.go.tmpl"}},
+		{File: filepath.Join(baseDir, "options.go"), Templates: []string{"options.go.tmpl"}},
 		{File: filepath.Join(baseDir, "poseidon2_test.go"), Templates: []string{"poseidon2.test.go.tmpl"}},
 	}
 
-	return bgen.Generate(conf, conf.Package, "./crypto/hash/poseidon2/template", entries...)
+	return bgen.Generate(p2Conf, conf.Package, "./crypto/hash/poseidon2/template", entries...)
 
 }
+
+// sBoxDegree returns the degree d of the sBox x -> x^d of the curve, which must
+// be coprime with r-1.
+func sBoxDegree(conf config.Curve) int {
+	switch {
+	case conf.Equal(config.BLS12_377):
+		return 17
+	case conf.Equal(config.BLS24_317):
+		return 7
+	default:
+		return 5
+	}
+}
@@ -0,0 +1,80 @@
+package poseidon2
+
+import (
+	"math"
+	"math/big"
+)
+
+// securityLevel is the security level in bits of the instances
+const securityLevel = 128
+
+// roundNumbers returns the numbers of full and partial rounds of the poseidon2
+// permutation of width t with the sBox x -> x^alpha over the field of
+// characteristic p.
+//
+// It follows the reference implementation, see calc_final_numbers_fixed in
+// https://github.com/HorizenLabs/poseidon2/blob/main/poseidon2_rust_params.sage:
+// the numbers of rounds minimizing the number of sBoxes among the ones
+// resisting the known attacks, plus a security margin of 2 full rounds and
+// 7.5% of partial rounds.
+func roundNumbers(p *big.Int, t, alpha int) (rf, rp int) {
+	minCost := math.MaxInt
+	for rpMin := 1; rpMin < 500; rpMin++ {
+		for rfMin := 4; rfMin < 100; rfMin += 2 {
+			if !isSecure(p, t, rfMin, rpMin, alpha) {
+				continue
+			}
+			// security margin
+			rfT := rfMin + 2
+			rpT := int(math.Ceil(float64(rpMin) * 1.075))
+			if cost := t*rfT + rpT; cost < minCost || (cost == minCost && rfT < rf) {
+				rf, rp, minCost = rfT, rpT, cost
+			}
+		}
+	}
+	return
+}
+
+// isSecure reports whether rf full rounds and rp partial rounds resist the
+// statistical, interpolation and Gröbner basis attacks, see sat_inequiv_alpha
+// in the reference implementation.
+func isSecure(p *big.Int, t, rf, rp, alpha int) bool {
+	n := float64(p.BitLen())
+	fp, _ := new(big.Float).SetInt(p).Float64()
+	log2p := math.Log2(fp)
+	T, RF, RP, M := float64(t), float64(rf), float64(rp), float64(securityLevel)
+	a := float64(alpha)
+	logA2 := 1 / math.Log2(a) // log_alpha(2)
+
+	rf1 := 10.0 // statistical
+	if M <= math.Floor(log2p-(a-1)/2)*(T+1) {
+		rf1 = 6
+	}
+	rf2 := 1 + math.Ceil(logA2*math.Min(M, n)) + math.Ceil(math.Log(T)/math.Log(a)) - RP // interpolation
+	rf3 := logA2*math.Min(M, log2p) - RP                                                 // Gröbner 1
+	rf4 := T - 1 + logA2*math.Min(M/(T+1), log2p/2) - RP                                 // Gröbner 2
+	rf5 := (T - 2 + M/(2*math.Log2(a)) - RP) / (T - 1)                                   // Gröbner 3
+	for _, bound := range []float64{rf1, rf2, rf3, rf4, rf5} {
+		if RF < math.Ceil(bound) {
+			return false
+		}
+	}
+
+	// Gröbner basis attack of https://eprint.iacr.org/2023/537.pdf
+	r := math.Floor(T / 3)
+	over := (RF-1)*T + RP + r + r*(RF/2) + RP + a
+	under := r*(RF/2) + RP + a
+	logBinomial := log2Binomial(over, under)
+	if math.IsInf(logBinomial, 0) {
+		logBinomial = M + 1
+	}
+	return math.Ceil(2*logBinomial) >= M
+}
+
+// log2Binomial returns log₂(n choose k)
+func log2Binomial(n, k float64) float64 {
+	a, _ := math.Lgamma(n + 1)
+	b, _ := math.Lgamma(k + 1)
+	c, _ := math.Lgamma(n - k + 1)
+	return (a - b - c) / math.Ln2
+}
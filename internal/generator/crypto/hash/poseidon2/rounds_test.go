@@ -0,0 +1,44 @@
+package poseidon2
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestRoundNumbers(t *testing.T) {
+	// instances of https://github.com/HorizenLabs/poseidon2/tree/main/plain_implementations/src/poseidon2
+	bn254, _ := new(big.Int).SetString("21888242871839275222246405745257275088548364400416711140085766027022447616001", 10)
+	bls12381, _ := new(big.Int).SetString("52435875175126190479447740508185965837690552500527637822603658699938581184513", 10)
+	goldilocks := new(big.Int).SetUint64(0xffffffff00000001)
+	babybear := big.NewInt(2013265921)
+
+	vectors := []struct {
+		name     string
+		p        *big.Int
+		t, alpha int
+		rf, rp   int
+	}{
+		{"bn254", bn254, 3, 5, 8, 56},
+		{"bls12-381", bls12381, 2, 5, 8, 56},
+		{"bls12-381", bls12381, 3, 5, 8, 56},
+		{"bls12-381", bls12381, 4, 5, 8, 56},
+		{"bls12-381", bls12381, 8, 5, 8, 57},
+		{"bls12-381", bls12381, 12, 5, 8, 57},
+		{"bls12-381", bls12381, 16, 5, 8, 57},
+		{"bls12-381", bls12381, 20, 5, 8, 57},
+		{"bls12-381", bls12381, 24, 5, 8, 57},
+		{"goldilocks", goldilocks, 8, 7, 8, 22},
+		{"goldilocks", goldilocks, 12, 7, 8, 22},
+		{"goldilocks", goldilocks, 16, 7, 8, 22},
+		{"goldilocks", goldilocks, 20, 7, 8, 22},
+		{"babybear", babybear, 16, 7, 8, 13},
+		{"babybear", babybear, 24, 7, 8, 21},
+	}
+
+	for _, v := range vectors {
+		rf, rp := roundNumbers(v.p, v.t, v.alpha)
+		if rf != v.rf || rp != v.rp {
+			t.Errorf("%s, t=%d: expected (%d, %d) rounds, got (%d, %d)", v.name, v.t, v.rf, v.rp, rf, rp)
+		}
+	}
+}
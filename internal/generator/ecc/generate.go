@@ -49,6 +49,8 @@ func Generate(conf config.Curve, baseDir string, bgen *bavard.BatchGenerator) er
 	entries = []bavard.Entry{
 		{File: filepath.Join(baseDir, "g1.go"), Templates: []string{"point.go.tmpl"}},
 		{File: filepath.Join(baseDir, "g1_test.go"), Templates: []string{"tests/point.go.tmpl"}},
+		{File: filepath.Join(baseDir, "g1_fixedbase.go"), Templates: []string{"fixedbase.go.tmpl"}},
+		{File: filepath.Join(baseDir, "g1_fixedbase_test.go"), Templates: []string{"tests/fixedbase.go.tmpl"}},
 	}
 	// if not secp256k1, generate the lagrange transform
 	if conf.Name != config.SECP256K1.Name {
@@ -69,6 +71,8 @@ func Generate(conf config.Curve, baseDir string, bgen *bavard.BatchGenerator) er
 		{File: filepath.Join(baseDir, "multiexp_affine.go"), Templates: []string{"multiexp_affine.go.tmpl"}},
 		{File: filepath.Join(baseDir, "multiexp_jacobian.go"), Templates: []string{"multiexp_jacobian.go.tmpl"}},
 		{File: filepath.Join(baseDir, "multiexp_test.go"), Templates: []string{"tests/multiexp.go.tmpl"}},
+		{File: filepath.Join(baseDir, "multiexp_precomputed.go"), Templates: []string{"multiexp_precomputed.go.tmpl"}},
+		{File: filepath.Join(baseDir, "multiexp_precomputed_test.go"), Templates: []string{"tests/multiexp_precomputed.go.tmpl"}},
 	}
 	conf.Package = packageName
 	funcs := make(template.FuncMap)
@@ -193,6 +197,8 @@ func Generate(conf config.Curve, baseDir string, bgen *bavard.BatchGenerator) er
 	entries = []bavard.Entry{
 		{File: filepath.Join(baseDir, "g2.go"), Templates: []string{"point.go.tmpl"}},
 		{File: filepath.Join(baseDir, "g2_test.go"), Templates: []string{"tests/point.go.tmpl"}},
+		{File: filepath.Join(baseDir, "g2_fixedbase.go"), Templates: []string{"fixedbase.go.tmpl"}},
+		{File: filepath.Join(baseDir, "g2_fixedbase_test.go"), Templates: []string{"tests/fixedbase.go.tmpl"}},
 	}
 	g2 := pconf{conf, conf.G2}
 	return bgen.Generate(g2, packageName, "./ecc/template", entries...)
@@ -18,6 +18,16 @@ func Generate(conf config.Curve, baseDir string, bgen *bavard.BatchGenerator) er
 		{File: filepath.Join(baseDir, "ecdsa_test.go"), Templates: []string{"ecdsa.test.go.tmpl"}},
 		{File: filepath.Join(baseDir, "marshal.go"), Templates: []string{"marshal.go.tmpl"}},
 		{File: filepath.Join(baseDir, "marshal_test.go"), Templates: []string{"marshal.test.go.tmpl"}},
+		{File: filepath.Join(baseDir, "rfc6979.go"), Templates: []string{"rfc6979.go.tmpl"}},
+		{File: filepath.Join(baseDir, "rfc6979_test.go"), Templates: []string{"rfc6979.test.go.tmpl"}},
+	}
+
+	// Ethereum signatures, addresses and ecrecover
+	if conf.Equal(config.SECP256K1) {
+		entries = append(entries,
+			bavard.Entry{File: filepath.Join(baseDir, "ethereum.go"), Templates: []string{"ethereum.go.tmpl"}},
+			bavard.Entry{File: filepath.Join(baseDir, "ethereum_test.go"), Templates: []string{"ethereum.test.go.tmpl"}},
+		)
 	}
 	return bgen.Generate(conf, conf.Package, "./ecdsa/template", entries...)
 
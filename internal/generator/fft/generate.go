@@ -8,7 +8,37 @@ import (
 	"github.com/consensys/gnark-crypto/internal/generator/config"
 )
 
+// fftConfig is the data passed to the templates
+type fftConfig struct {
+	config.FieldDependency
+	Package          string
+	Name             string // name of the curve or of the field, selects the roots of unity
+	CurvePackagePath string // empty if the field is not the scalar field of a curve
+}
+
+// Generate generates the fft package on the scalar field of a curve
 func Generate(conf config.Curve, baseDir string, bgen *bavard.BatchGenerator) error {
+	return generate(fftConfig{
+		FieldDependency: config.FieldDependency{
+			FieldPackagePath: "github.com/consensys/gnark-crypto/ecc/" + conf.Name + "/fr",
+			FieldPackageName: "fr",
+			ElementType:      "fr.Element",
+		},
+		Name:             conf.Name,
+		CurvePackagePath: "github.com/consensys/gnark-crypto/ecc/" + conf.Name,
+	}, baseDir, bgen)
+}
+
+// GenerateField generates the fft package on a field which is not attached to
+// a curve (e.g. goldilocks). name selects the roots of unity in the templates.
+func GenerateField(name string, fieldInfo config.FieldDependency, baseDir string, bgen *bavard.BatchGenerator) error {
+	return generate(fftConfig{
+		FieldDependency: fieldInfo,
+		Name:            name,
+	}, baseDir, bgen)
+}
+
+func generate(conf fftConfig, baseDir string, bgen *bavard.BatchGenerator) error {
 
 	conf.Package = "fft"
 
@@ -59,7 +89,7 @@ func Generate(conf config.Curve, baseDir string, bgen *bavard.BatchGenerator) er
 	entries = []bavard.Entry{
 		{File: filepath.Join(frDir, "generator.go"), Templates: []string{"fr.generator.go.tmpl"}},
 	}
-	return bgen.GenerateWithOptions(conf, "fr", "./fft/template/", bavardOpts, entries...)
+	return bgen.GenerateWithOptions(conf, conf.FieldPackageName, "./fft/template/", bavardOpts, entries...)
 }
 
 func anyToUint64(x any) uint64 {
@@ -0,0 +1,27 @@
+package ipa
+
+import (
+	"path/filepath"
+
+	"github.com/consensys/bavard"
+)
+
+// Config describes the group over which the inner product argument is
+// generated
+type Config struct {
+	CurvePackagePath string // import path of the package of the group
+	CurvePackage     string // name of the package of the group
+	FrPackagePath    string // import path of the scalar field
+	Point            string // type of the points in affine coordinates
+	TwistedEdwards   bool   // set if the group is a twisted Edwards curve
+}
+
+func Generate(conf Config, baseDir string, bgen *bavard.BatchGenerator) error {
+	entries := []bavard.Entry{
+		{File: filepath.Join(baseDir, "doc.go"), Templates: []string{"doc.go.tmpl"}},
+		{File: filepath.Join(baseDir, "group.go"), Templates: []string{"group.go.tmpl"}},
+		{File: filepath.Join(baseDir, "ipa.go"), Templates: []string{"ipa.go.tmpl"}},
+		{File: filepath.Join(baseDir, "ipa_test.go"), Templates: []string{"ipa.test.go.tmpl"}},
+	}
+	return bgen.Generate(conf, "ipa", "./ipa/template/", entries...)
+}
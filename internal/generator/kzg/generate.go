@@ -13,11 +13,48 @@ func Generate(conf config.Curve, baseDir string, bgen *bavard.BatchGenerator) er
 	conf.Package = "kzg"
 	entries := []bavard.Entry{
 		{File: filepath.Join(baseDir, "doc.go"), Templates: []string{"doc.go.tmpl"}},
+		{File: filepath.Join(baseDir, "amortized.go"), Templates: []string{"amortized.go.tmpl"}},
+		{File: filepath.Join(baseDir, "amortized_test.go"), Templates: []string{"amortized.test.go.tmpl"}},
+		{File: filepath.Join(baseDir, "hiding.go"), Templates: []string{"hiding.go.tmpl"}},
+		{File: filepath.Join(baseDir, "hiding_test.go"), Templates: []string{"hiding.test.go.tmpl"}},
 		{File: filepath.Join(baseDir, "kzg.go"), Templates: []string{"kzg.go.tmpl"}},
 		{File: filepath.Join(baseDir, "kzg_test.go"), Templates: []string{"kzg.test.go.tmpl"}},
+		{File: filepath.Join(baseDir, "lagrange.go"), Templates: []string{"lagrange.go.tmpl"}},
+		{File: filepath.Join(baseDir, "lagrange_test.go"), Templates: []string{"lagrange.test.go.tmpl"}},
 		{File: filepath.Join(baseDir, "marshal.go"), Templates: []string{"marshal.go.tmpl"}},
+		{File: filepath.Join(baseDir, "mpcsetup.go"), Templates: []string{"mpcsetup.go.tmpl"}},
+		{File: filepath.Join(baseDir, "mpcsetup_test.go"), Templates: []string{"mpcsetup.test.go.tmpl"}},
 		{File: filepath.Join(baseDir, "utils.go"), Templates: []string{"utils.go.tmpl"}},
 	}
-	return bgen.Generate(conf, conf.Package, "./kzg/template/", entries...)
+
+	// importers of the setups of public ceremonies
+	if conf.Equal(config.BLS12_381) {
+		entries = append(entries,
+			bavard.Entry{File: filepath.Join(baseDir, "import_ethereum.go"), Templates: []string{"import_ethereum.go.tmpl"}},
+			bavard.Entry{File: filepath.Join(baseDir, "import_ethereum_test.go"), Templates: []string{"import_ethereum.test.go.tmpl"}},
+		)
+	}
+	if conf.Equal(config.BN254) {
+		entries = append(entries,
+			bavard.Entry{File: filepath.Join(baseDir, "import_ptau.go"), Templates: []string{"import_ptau.go.tmpl"}},
+			bavard.Entry{File: filepath.Join(baseDir, "import_ptau_test.go"), Templates: []string{"import_ptau.test.go.tmpl"}},
+		)
+	}
+
+	if err := bgen.Generate(conf, conf.Package, "./kzg/template/", entries...); err != nil {
+		return err
+	}
+
+	// blob commitments of EIP-4844
+	if conf.Equal(config.BLS12_381) {
+		conf.Package = "eip4844"
+		entries = []bavard.Entry{
+			{File: filepath.Join(baseDir, "eip4844", "doc.go"), Templates: []string{"doc.go.tmpl"}},
+			{File: filepath.Join(baseDir, "eip4844", "eip4844.go"), Templates: []string{"eip4844.go.tmpl"}},
+			{File: filepath.Join(baseDir, "eip4844", "eip4844_test.go"), Templates: []string{"eip4844.test.go.tmpl"}},
+		}
+		return bgen.Generate(conf, conf.Package, "./kzg/template/eip4844/", entries...)
+	}
+	return nil
 
 }
@@ -10,6 +10,7 @@ import (
 	"github.com/consensys/bavard"
 	"github.com/consensys/gnark-crypto/field/generator"
 	field "github.com/consensys/gnark-crypto/field/generator/config"
+	"github.com/consensys/gnark-crypto/internal/generator/bls"
 	"github.com/consensys/gnark-crypto/internal/generator/config"
 	"github.com/consensys/gnark-crypto/internal/generator/crypto/hash/mimc"
 	"github.com/consensys/gnark-crypto/internal/generator/crypto/hash/poseidon2"
@@ -23,12 +24,14 @@ import (
 	"github.com/consensys/gnark-crypto/internal/generator/gkr"
 	"github.com/consensys/gnark-crypto/internal/generator/hash_to_field"
 	"github.com/consensys/gnark-crypto/internal/generator/iop"
+	"github.com/consensys/gnark-crypto/internal/generator/ipa"
 	"github.com/consensys/gnark-crypto/internal/generator/kzg"
 	"github.com/consensys/gnark-crypto/internal/generator/pairing"
 	"github.com/consensys/gnark-crypto/internal/generator/pedersen"
 	"github.com/consensys/gnark-crypto/internal/generator/permutation"
 	"github.com/consensys/gnark-crypto/internal/generator/plookup"
 	"github.com/consensys/gnark-crypto/internal/generator/polynomial"
+	"github.com/consensys/gnark-crypto/internal/generator/pst"
 	"github.com/consensys/gnark-crypto/internal/generator/shplonk"
 	"github.com/consensys/gnark-crypto/internal/generator/sis"
 	"github.com/consensys/gnark-crypto/internal/generator/sumcheck"
@@ -107,6 +110,13 @@ func main() {
 			assertNoError(ecc.Generate(conf, curveDir, bgen))
 
 			if conf.Equal(config.SECP256K1) {
+				// generate the inner product argument commitment scheme
+				assertNoError(ipa.Generate(ipa.Config{
+					CurvePackagePath: "github.com/consensys/gnark-crypto/ecc/" + conf.Name,
+					CurvePackage:     conf.Name,
+					FrPackagePath:    "github.com/consensys/gnark-crypto/ecc/" + conf.Name + "/fr",
+					Point:            "G1Affine",
+				}, filepath.Join(curveDir, "ipa"), bgen))
 				return
 			}
 
@@ -129,12 +139,20 @@ func main() {
 			// generate kzg on fr
 			assertNoError(kzg.Generate(conf, filepath.Join(curveDir, "kzg"), bgen))
 
+			// generate bls signatures
+			if conf.Equal(config.BLS12_381) || conf.Equal(config.BLS12_377) || conf.Equal(config.BN254) {
+				assertNoError(bls.Generate(conf, filepath.Join(curveDir, "bls"), bgen))
+			}
+
 			// generate shplonk on fr
 			assertNoError(shplonk.Generate(conf, filepath.Join(curveDir, "shplonk"), bgen))
 
 			// generate fflonk on fr
 			assertNoError(fflonk.Generate(conf, filepath.Join(curveDir, "fflonk"), bgen))
 
+			// generate pst on fr
+			assertNoError(pst.Generate(conf, filepath.Join(curveDir, "pst"), bgen))
+
 			// generate pedersen on fr
 			assertNoError(pedersen.Generate(conf, filepath.Join(curveDir, "fr", "pedersen"), bgen))
 
@@ -207,6 +225,23 @@ func main() {
 
 			// generate eddsa on companion curves
 			assertNoError(eddsa.Generate(conf, curveDir, bgen))
+
+			if conf.HasEndomorphism {
+				// generate the scalar field and the inner product argument
+				// commitment scheme on bandersnatch
+				frConf, err := field.NewFieldConfig("fr", "Element", conf.Order, true)
+				assertNoError(err)
+				assertNoError(generator.GenerateFF(frConf, filepath.Join(curveDir, "fr"), asmDirBuildPath, filepath.Join("..", asmDirIncludePath)))
+
+				curvePackagePath := "github.com/consensys/gnark-crypto/ecc/" + conf.Name + "/" + conf.Package
+				assertNoError(ipa.Generate(ipa.Config{
+					CurvePackagePath: curvePackagePath,
+					CurvePackage:     conf.Package,
+					FrPackagePath:    curvePackagePath + "/fr",
+					Point:            "PointAffine",
+					TwistedEdwards:   true,
+				}, filepath.Join(curveDir, "ipa"), bgen))
+			}
 		}(conf)
 
 	}
@@ -216,6 +251,21 @@ func main() {
 		defer wg.Done()
 		assertNoError(test_vector_utils.GenerateRationals(bgen))
 	}()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		// generate fft and polynomial on the goldilocks field
+		goldilocksDir := filepath.Join(baseDir, "field", "goldilocks")
+		goldilocksInfo := config.FieldDependency{
+			FieldPackagePath: "github.com/consensys/gnark-crypto/field/goldilocks",
+			FieldPackageName: "goldilocks",
+			ElementType:      "goldilocks.Element",
+		}
+		assertNoError(fft.GenerateField("goldilocks", goldilocksInfo, filepath.Join(goldilocksDir, "fft"), bgen))
+		assertNoError(polynomial.Generate(goldilocksInfo, filepath.Join(goldilocksDir, "polynomial"), true, bgen))
+	}()
 	wg.Wait()
 
 	// format the whole directory